@@ -0,0 +1,60 @@
+// Command purge removes the subscriptions deleted longer ago than the
+// retention period, together with their prices, tags and rollup rows, and the
+// expired idempotency keys of every tenant. Run it on a schedule, for example
+// daily from cron.
+package main
+
+import (
+	"AggregationService/internal/app"
+	"AggregationService/internal/migrations"
+	"AggregationService/internal/pkg/logger"
+	"AggregationService/internal/pkg/tenant"
+	"context"
+	"flag"
+	"os"
+	"time"
+)
+
+func main() {
+	days := flag.Int("retention-days", 30, "days a deleted subscription can still be restored before it is purged")
+	flag.Parse()
+
+	ctx := app.InitContextWithLogger(context.Background())
+	log := logger.FromContext(ctx)
+	provider := app.NewAppProvider()
+
+	if *days < 0 {
+		log.Error("Invalid retention period", "retention-days", *days)
+		os.Exit(1)
+	}
+
+	if err := migrations.MigrateDB(provider.PGClient(ctx)); err != nil {
+		log.Error("Migration failed", "error", err)
+		os.Exit(1)
+	}
+
+	tenants, err := provider.TenantRepo(ctx).GetAll(ctx)
+	if err != nil {
+		log.Error("Failed to list tenants", "error", err)
+		os.Exit(1)
+	}
+
+	deletedBefore := time.Now().AddDate(0, 0, -*days)
+	for _, t := range tenants {
+		tenantCtx := tenant.ContextWithID(ctx, t.ID)
+
+		purged, err := provider.SubscriptionRepo(ctx).Purge(tenantCtx, deletedBefore)
+		if err != nil {
+			log.Error("Purge failed", "tenant", t.ID, "error", err)
+			os.Exit(1)
+		}
+		log.Info("Deleted subscriptions purged", "tenant", t.ID, "subscriptions", purged, "deleted_before", deletedBefore.Format(time.DateOnly))
+
+		expired, err := provider.IdempotencyRepo(ctx).PurgeExpired(tenantCtx, time.Now())
+		if err != nil {
+			log.Error("Idempotency key purge failed", "tenant", t.ID, "error", err)
+			os.Exit(1)
+		}
+		log.Info("Expired idempotency keys purged", "tenant", t.ID, "keys", expired)
+	}
+}
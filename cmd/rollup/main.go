@@ -0,0 +1,45 @@
+// Command rollup rebuilds the monthly cost rollup read by the cost endpoints
+// for every tenant. Run it at least once a month so that the horizon of
+// open-ended subscriptions keeps moving forward.
+package main
+
+import (
+	"AggregationService/internal/app"
+	"AggregationService/internal/migrations"
+	"AggregationService/internal/pkg/logger"
+	"AggregationService/internal/pkg/tenant"
+	"context"
+	"flag"
+	"os"
+	"time"
+)
+
+func main() {
+	months := flag.Int("months", 60, "months after the current one to roll up open-ended subscriptions for")
+	flag.Parse()
+
+	ctx := app.InitContextWithLogger(context.Background())
+	log := logger.FromContext(ctx)
+	provider := app.NewAppProvider()
+
+	if err := migrations.MigrateDB(provider.PGClient(ctx)); err != nil {
+		log.Error("Migration failed", "error", err)
+		os.Exit(1)
+	}
+
+	tenants, err := provider.TenantRepo(ctx).GetAll(ctx)
+	if err != nil {
+		log.Error("Failed to list tenants", "error", err)
+		os.Exit(1)
+	}
+
+	horizon := time.Now().AddDate(0, *months, 0)
+	for _, t := range tenants {
+		rows, err := provider.SubscriptionRepo(ctx).RebuildRollup(tenant.ContextWithID(ctx, t.ID), horizon)
+		if err != nil {
+			log.Error("Rollup rebuild failed", "tenant", t.ID, "error", err)
+			os.Exit(1)
+		}
+		log.Info("Rollup rebuilt", "tenant", t.ID, "rows", rows, "horizon", horizon.Format("01-2006"))
+	}
+}
@@ -0,0 +1,36 @@
+// Command tenant creates a tenant and prints its API key. The key is shown
+// only once: requests of the tenant send it as Authorization: Bearer <key>.
+package main
+
+import (
+	"AggregationService/internal/app"
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/migrations"
+	"AggregationService/internal/pkg/logger"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+)
+
+func main() {
+	name := flag.String("name", "", "name of the organization")
+	flag.Parse()
+
+	ctx := app.InitContextWithLogger(context.Background())
+	log := logger.FromContext(ctx)
+	provider := app.NewAppProvider()
+
+	if err := migrations.MigrateDB(provider.PGClient(ctx)); err != nil {
+		log.Error("Migration failed", "error", err)
+		os.Exit(1)
+	}
+
+	created, err := provider.TenantUseCase(ctx).Create(ctx, &dto.CreateTenantRequest{Name: *name})
+	if err != nil {
+		log.Error("Tenant creation failed", "error", err)
+		os.Exit(1)
+	}
+	log.Info("Tenant created", "id", created.ID, "name", created.Name)
+	json.NewEncoder(os.Stdout).Encode(created)
+}
@@ -0,0 +1,29 @@
+package events
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/domain/ports/events"
+	"AggregationService/internal/pkg/logger"
+	"context"
+	"log/slog"
+)
+
+// logAlertPublisher emits budget alerts as structured log events.
+type logAlertPublisher struct{}
+
+func NewLogAlertPublisher() events.IBudgetAlertPublisher {
+	return &logAlertPublisher{}
+}
+
+func (p *logAlertPublisher) Publish(ctx context.Context, alert *entity.BudgetAlert) error {
+	logger.FromContext(ctx).Warn("budget alert",
+		slog.Int("budget_id", alert.BudgetID),
+		slog.String("user_id", alert.UserID.String()),
+		slog.String("period", alert.Period),
+		slog.String("amount", alert.Amount.String()),
+		slog.Int("threshold", alert.Threshold),
+		slog.String("spent", alert.Spent.String()),
+		slog.String("currency", alert.Currency),
+	)
+	return nil
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type IAuditUseCase interface {
+	GetAll(ctx context.Context, req *dto.ListAuditRequest) ([]*dto.AuditEntryResponse, error)
+}
+
+type AuditHandler struct {
+	useCase IAuditUseCase
+}
+
+func NewAuditHandler(useCase IAuditUseCase) *AuditHandler {
+	return &AuditHandler{useCase: useCase}
+}
+
+// GetAll lists the audit trail of all subscriptions.
+func (h *AuditHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	req, err := parseAuditRequest(r)
+	if err != nil {
+		log.Error("invalid audit query", slog.Any("err", err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.writeEntries(w, r, req)
+}
+
+// History lists the audit trail of one subscription by its public id, or by
+// the deprecated integer id. It stays available after the subscription is
+// purged.
+func (h *AuditHandler) History(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	req, err := parseAuditRequest(r)
+	if err != nil {
+		log.Error("invalid audit query", slog.Any("err", err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	req.SubscriptionID, req.SubscriptionPublicID = nil, nil
+	if err = parseAuditSubscriptionID(idStr, req); err != nil {
+		log.Error("invalid id", slog.String("id", idStr), slog.Any("err", err))
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if req.SubscriptionID != nil {
+		w.Header().Set("Deprecation", "true")
+	}
+
+	h.writeEntries(w, r, req)
+}
+
+func (h *AuditHandler) writeEntries(w http.ResponseWriter, r *http.Request, req *dto.ListAuditRequest) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	entries, err := h.useCase.GetAll(ctx, req)
+	if err != nil {
+		log.Error("failed to get audit entries", slog.Any("err", err))
+		if errors.Is(err, custom_err.ErrInvalidRequest) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Debug("success get audit entries", slog.Int("count", len(entries)))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+// parseAuditSubscriptionID sets the subscription filter of req from v, a
+// public id or a deprecated integer id.
+func parseAuditSubscriptionID(v string, req *dto.ListAuditRequest) error {
+	if publicID, err := uuid.Parse(v); err == nil {
+		req.SubscriptionPublicID = &publicID
+		return nil
+	}
+	subscriptionID, err := strconv.Atoi(v)
+	if err != nil {
+		return err
+	}
+	req.SubscriptionID = &subscriptionID
+	return nil
+}
+
+func parseAuditRequest(r *http.Request) (*dto.ListAuditRequest, error) {
+	query := r.URL.Query()
+	req := &dto.ListAuditRequest{}
+
+	if v := query.Get("subscription_id"); v != "" {
+		if err := parseAuditSubscriptionID(v, req); err != nil {
+			return nil, fmt.Errorf("invalid subscription_id")
+		}
+	}
+	if v := query.Get("user_id"); v != "" {
+		uid, err := uuid.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user_id")
+		}
+		req.UserID = &uid
+	}
+	if v := query.Get("action"); v != "" {
+		req.Action = &v
+	}
+	if v := query.Get("actor"); v != "" {
+		req.Actor = &v
+	}
+	if v := query.Get("request_id"); v != "" {
+		req.RequestID = &v
+	}
+	if v := query.Get("from"); v != "" {
+		from, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from")
+		}
+		req.From = &from
+	}
+	if v := query.Get("to"); v != "" {
+		to, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to")
+		}
+		req.To = &to
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit")
+		}
+		req.Limit = limit
+	}
+	if v := query.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid offset")
+		}
+		req.Offset = offset
+	}
+	return req, nil
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+)
+
+type mockAuditUseCase struct{ mock.Mock }
+
+func (m *mockAuditUseCase) GetAll(ctx context.Context, req *dto.ListAuditRequest) ([]*dto.AuditEntryResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).([]*dto.AuditEntryResponse), args.Error(1)
+}
+
+func TestAuditHandler_History(t *testing.T) {
+	mockUC := new(mockAuditUseCase)
+	handler := NewAuditHandler(mockUC)
+
+	mockUC.On("GetAll", mock.Anything, mock.MatchedBy(func(req *dto.ListAuditRequest) bool {
+		return req.SubscriptionID != nil && *req.SubscriptionID == 5 && req.Limit == 20
+	})).Return([]*dto.AuditEntryResponse{
+		{ID: 2, SubscriptionID: 5, Action: "update", Before: json.RawMessage(`{"price":299}`), After: json.RawMessage(`{"price":399}`), Actor: "alice"},
+		{ID: 1, SubscriptionID: 5, Action: "create", After: json.RawMessage(`{"price":299}`), Actor: "alice"},
+	}, nil)
+
+	r := chi.NewRouter()
+	r.Get("/subscriptions/{id}/history", handler.History)
+
+	req := httptest.NewRequest("GET", "/subscriptions/5/history?limit=20", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []map[string]any
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp, 2)
+	assert.Equal(t, map[string]any{"price": float64(399)}, resp[0]["after"])
+	assert.NotContains(t, resp[1], "before")
+	assert.Equal(t, "true", w.Header().Get("Deprecation"))
+}
+
+func TestAuditHandler_History_PublicID(t *testing.T) {
+	mockUC := new(mockAuditUseCase)
+	handler := NewAuditHandler(mockUC)
+	publicID := uuid.New()
+
+	mockUC.On("GetAll", mock.Anything, mock.MatchedBy(func(req *dto.ListAuditRequest) bool {
+		return req.SubscriptionID == nil && *req.SubscriptionPublicID == publicID
+	})).Return([]*dto.AuditEntryResponse{{ID: 1, SubscriptionID: 5, SubscriptionPublicID: &publicID, Action: "create"}}, nil)
+
+	r := chi.NewRouter()
+	r.Get("/subscriptions/{id}/history", handler.History)
+
+	req := httptest.NewRequest("GET", "/subscriptions/"+publicID.String()+"/history", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Deprecation"))
+	mockUC.AssertExpectations(t)
+}
+
+func TestAuditHandler_GetAll(t *testing.T) {
+	from := time.Date(2025, time.November, 1, 0, 0, 0, 0, time.UTC)
+
+	mockUC := new(mockAuditUseCase)
+	handler := NewAuditHandler(mockUC)
+
+	mockUC.On("GetAll", mock.Anything, mock.MatchedBy(func(req *dto.ListAuditRequest) bool {
+		return *req.Actor == "alice" && *req.Action == "price" && req.From.Equal(from) && req.To == nil
+	})).Return([]*dto.AuditEntryResponse{{ID: 1, Action: "price", Actor: "alice"}}, nil)
+
+	r := chi.NewRouter()
+	r.Get("/audit", handler.GetAll)
+
+	req := httptest.NewRequest("GET", "/audit?actor=alice&action=price&from=2025-11-01T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuditHandler_GetAll_BadRequest(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		err   error
+	}{
+		{name: "Invalid from", query: "from=01-11-2025"},
+		{name: "Invalid user_id", query: "user_id=abc"},
+		{name: "Invalid limit", query: "limit=ten"},
+		{name: "Rejected by use case", query: "action=rename", err: custom_err.ErrInvalidRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(mockAuditUseCase)
+			handler := NewAuditHandler(mockUC)
+			if tt.err != nil {
+				mockUC.On("GetAll", mock.Anything, mock.Anything).Return([]*dto.AuditEntryResponse(nil), tt.err)
+			}
+
+			r := chi.NewRouter()
+			r.Get("/audit", handler.GetAll)
+
+			req := httptest.NewRequest("GET", "/audit?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
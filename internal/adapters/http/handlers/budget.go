@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type IBudgetUseCase interface {
+	Create(ctx context.Context, req *dto.CreateBudgetRequest) (*dto.BudgetResponse, error)
+	GetByID(ctx context.Context, id int) (*dto.BudgetResponse, error)
+	GetAll(ctx context.Context, userID *uuid.UUID) ([]*dto.BudgetResponse, error)
+	Update(ctx context.Context, id int, req *dto.UpdateBudgetRequest) (*dto.BudgetResponse, error)
+	Delete(ctx context.Context, id int) error
+	Status(ctx context.Context, id int) (*dto.BudgetStatusResponse, error)
+}
+
+type BudgetHandler struct {
+	useCase IBudgetUseCase
+}
+
+func NewBudgetHandler(useCase IBudgetUseCase) *BudgetHandler {
+	return &BudgetHandler{useCase: useCase}
+}
+
+func (h *BudgetHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	var req dto.CreateBudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", slog.Any("err", err))
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	budget, err := h.useCase.Create(ctx, &req)
+	if err != nil {
+		log.Error("failed to create budget", slog.Any("err", err))
+		writeBudgetError(w, err)
+		return
+	}
+
+	log.Debug("success create budget", slog.Int("id", budget.ID))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(budget)
+}
+
+func (h *BudgetHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		log.Error("invalid id", slog.String("id", idStr), slog.Any("err", err))
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	budget, err := h.useCase.GetByID(ctx, id)
+	if err != nil {
+		log.Error("failed to get budget", slog.Int("id", id), slog.Any("err", err))
+		writeBudgetError(w, err)
+		return
+	}
+
+	log.Debug("success get budget", slog.Int("id", id))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(budget)
+}
+
+func (h *BudgetHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	var userID *uuid.UUID
+	if v := r.URL.Query().Get("user_id"); v != "" {
+		uid, err := uuid.Parse(v)
+		if err != nil {
+			log.Error("invalid user_id", slog.String("user_id", v), slog.Any("err", err))
+			http.Error(w, "invalid user_id", http.StatusBadRequest)
+			return
+		}
+		userID = &uid
+	}
+
+	budgets, err := h.useCase.GetAll(ctx, userID)
+	if err != nil {
+		log.Error("failed to get budgets", slog.Any("err", err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Debug("success get budgets", slog.Int("count", len(budgets)))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(budgets)
+}
+
+func (h *BudgetHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		log.Error("invalid id", slog.String("id", idStr), slog.Any("err", err))
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.UpdateBudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", slog.Any("err", err))
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	budget, err := h.useCase.Update(ctx, id, &req)
+	if err != nil {
+		log.Error("failed to update budget", slog.Int("id", id), slog.Any("err", err))
+		writeBudgetError(w, err)
+		return
+	}
+
+	log.Debug("success update budget", slog.Int("id", id))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(budget)
+}
+
+func (h *BudgetHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		log.Error("invalid id", slog.String("id", idStr), slog.Any("err", err))
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.useCase.Delete(ctx, id); err != nil {
+		log.Error("failed to delete budget", slog.Int("id", id), slog.Any("err", err))
+		writeBudgetError(w, err)
+		return
+	}
+
+	log.Debug("success delete budget", slog.Int("id", id))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *BudgetHandler) Status(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		log.Error("invalid id", slog.String("id", idStr), slog.Any("err", err))
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	status, err := h.useCase.Status(ctx, id)
+	if err != nil {
+		log.Error("failed to get budget status", slog.Int("id", id), slog.Any("err", err))
+		writeBudgetError(w, err)
+		return
+	}
+
+	log.Debug("success get budget status", slog.Int("id", id), slog.String("spent", status.Spent.String()))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+func writeBudgetError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, custom_err.ErrInvalidRequest), errors.Is(err, custom_err.ErrCategoryNotFound),
+		errors.Is(err, custom_err.ErrServiceNotFound):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, custom_err.ErrBudgetNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, custom_err.ErrExchangeRateNotFound):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
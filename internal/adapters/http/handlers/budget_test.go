@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/money"
+)
+
+type mockBudgetUseCase struct{ mock.Mock }
+
+func (m *mockBudgetUseCase) Create(ctx context.Context, req *dto.CreateBudgetRequest) (*dto.BudgetResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*dto.BudgetResponse), args.Error(1)
+}
+func (m *mockBudgetUseCase) GetByID(ctx context.Context, id int) (*dto.BudgetResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*dto.BudgetResponse), args.Error(1)
+}
+func (m *mockBudgetUseCase) GetAll(ctx context.Context, userID *uuid.UUID) ([]*dto.BudgetResponse, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]*dto.BudgetResponse), args.Error(1)
+}
+func (m *mockBudgetUseCase) Update(ctx context.Context, id int, req *dto.UpdateBudgetRequest) (*dto.BudgetResponse, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(*dto.BudgetResponse), args.Error(1)
+}
+func (m *mockBudgetUseCase) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+func (m *mockBudgetUseCase) Status(ctx context.Context, id int) (*dto.BudgetStatusResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*dto.BudgetStatusResponse), args.Error(1)
+}
+
+func TestBudgetHandler_Create(t *testing.T) {
+	mockUC := new(mockBudgetUseCase)
+	handler := NewBudgetHandler(mockUC)
+
+	reqBody := dto.CreateBudgetRequest{UserID: uuid.New(), Period: "month", Amount: money.FromMajor(1000)}
+	budget := &dto.BudgetResponse{ID: 1, UserID: reqBody.UserID, Period: "month", Amount: money.FromMajor(1000)}
+	mockUC.On("Create", mock.Anything, &reqBody).Return(budget, nil)
+
+	body, _ := json.Marshal(reqBody)
+	r := chi.NewRouter()
+	r.Post("/budgets", handler.Create)
+
+	req := httptest.NewRequest("POST", "/budgets", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp dto.BudgetResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resp.ID)
+}
+
+func TestBudgetHandler_Create_Invalid(t *testing.T) {
+	mockUC := new(mockBudgetUseCase)
+	handler := NewBudgetHandler(mockUC)
+
+	mockUC.On("Create", mock.Anything, mock.AnythingOfType("*dto.CreateBudgetRequest")).
+		Return((*dto.BudgetResponse)(nil), custom_err.ErrInvalidRequest)
+
+	r := chi.NewRouter()
+	r.Post("/budgets", handler.Create)
+
+	req := httptest.NewRequest("POST", "/budgets", bytes.NewReader([]byte(`{"period": "week"}`)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestBudgetHandler_GetByID_NotFound(t *testing.T) {
+	mockUC := new(mockBudgetUseCase)
+	handler := NewBudgetHandler(mockUC)
+
+	mockUC.On("GetByID", mock.Anything, 999).Return((*dto.BudgetResponse)(nil), custom_err.ErrBudgetNotFound)
+
+	r := chi.NewRouter()
+	r.Get("/budgets/{id}", handler.GetByID)
+
+	req := httptest.NewRequest("GET", "/budgets/999", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestBudgetHandler_Status(t *testing.T) {
+	mockUC := new(mockBudgetUseCase)
+	handler := NewBudgetHandler(mockUC)
+
+	status := &dto.BudgetStatusResponse{
+		Budget:      &dto.BudgetResponse{ID: 1, Period: "month", Amount: money.FromMajor(1000)},
+		StartDate:   "10-2025",
+		EndDate:     "10-2025",
+		Spent:       money.FromMajor(850),
+		Remaining:   money.FromMajor(150),
+		UsedPercent: 85,
+		Alerting:    true,
+	}
+	mockUC.On("Status", mock.Anything, 1).Return(status, nil)
+
+	r := chi.NewRouter()
+	r.Get("/budgets/{id}/status", handler.Status)
+
+	req := httptest.NewRequest("GET", "/budgets/1/status", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp dto.BudgetStatusResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromMajor(850), resp.Spent)
+	assert.True(t, resp.Alerting)
+}
+
+func TestBudgetHandler_Delete(t *testing.T) {
+	mockUC := new(mockBudgetUseCase)
+	handler := NewBudgetHandler(mockUC)
+
+	mockUC.On("Delete", mock.Anything, 1).Return(nil)
+
+	r := chi.NewRouter()
+	r.Delete("/budgets/{id}", handler.Delete)
+
+	req := httptest.NewRequest("DELETE", "/budgets/1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+type ICategoryUseCase interface {
+	Create(ctx context.Context, req *dto.CreateCategoryRequest) (*dto.CategoryResponse, error)
+	GetAll(ctx context.Context) ([]*dto.CategoryResponse, error)
+}
+
+type CategoryHandler struct {
+	useCase ICategoryUseCase
+}
+
+func NewCategoryHandler(useCase ICategoryUseCase) *CategoryHandler {
+	return &CategoryHandler{useCase: useCase}
+}
+
+func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	var req dto.CreateCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", slog.Any("err", err))
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	category, err := h.useCase.Create(ctx, &req)
+	if err != nil {
+		log.Error("failed to create category", slog.Any("err", err))
+		switch {
+		case errors.Is(err, custom_err.ErrInvalidRequest):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, custom_err.ErrCategoryAlreadyExists):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	log.Debug("success create category", slog.String("code", category.Code))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(category)
+}
+
+func (h *CategoryHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	categories, err := h.useCase.GetAll(ctx)
+	if err != nil {
+		log.Error("failed to get categories", slog.Any("err", err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Debug("success get categories", slog.Int("count", len(categories)))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(categories)
+}
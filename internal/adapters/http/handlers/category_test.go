@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+)
+
+type mockCategoryUseCase struct{ mock.Mock }
+
+func (m *mockCategoryUseCase) Create(ctx context.Context, req *dto.CreateCategoryRequest) (*dto.CategoryResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*dto.CategoryResponse), args.Error(1)
+}
+func (m *mockCategoryUseCase) GetAll(ctx context.Context) ([]*dto.CategoryResponse, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*dto.CategoryResponse), args.Error(1)
+}
+
+func TestCategoryHandler_Create(t *testing.T) {
+	mockUC := new(mockCategoryUseCase)
+	handler := NewCategoryHandler(mockUC)
+
+	reqBody := dto.CreateCategoryRequest{Code: "vpn", Name: "VPN"}
+	mockUC.On("Create", mock.Anything, &reqBody).Return(&dto.CategoryResponse{Code: "vpn", Name: "VPN"}, nil)
+
+	body, _ := json.Marshal(reqBody)
+	r := chi.NewRouter()
+	r.Post("/categories", handler.Create)
+
+	req := httptest.NewRequest("POST", "/categories", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp dto.CategoryResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "vpn", resp.Code)
+}
+
+func TestCategoryHandler_Create_Duplicate(t *testing.T) {
+	mockUC := new(mockCategoryUseCase)
+	handler := NewCategoryHandler(mockUC)
+
+	mockUC.On("Create", mock.Anything, mock.AnythingOfType("*dto.CreateCategoryRequest")).
+		Return((*dto.CategoryResponse)(nil), custom_err.ErrCategoryAlreadyExists)
+
+	r := chi.NewRouter()
+	r.Post("/categories", handler.Create)
+
+	req := httptest.NewRequest("POST", "/categories", bytes.NewReader([]byte(`{"code": "music", "name": "Музыка"}`)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestCategoryHandler_GetAll(t *testing.T) {
+	mockUC := new(mockCategoryUseCase)
+	handler := NewCategoryHandler(mockUC)
+
+	mockUC.On("GetAll", mock.Anything).Return([]*dto.CategoryResponse{
+		{Code: "music", Name: "Музыка"},
+		{Code: "streaming", Name: "Стриминг"},
+	}, nil)
+
+	r := chi.NewRouter()
+	r.Get("/categories", handler.GetAll)
+
+	req := httptest.NewRequest("GET", "/categories", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []*dto.CategoryResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp, 2)
+}
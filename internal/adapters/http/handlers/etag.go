@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var errPreconditionFailed = errors.New("precondition failed")
+
+// etag formats the version of a subscription as a strong entity tag.
+func etag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// parseIfMatch returns the version required by the If-Match header, nil when
+// there is no header or it is "*". Weak or malformed tags can't match a
+// version and fail with errPreconditionFailed.
+func parseIfMatch(r *http.Request) (*int, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+	if len(header) < 2 || header[0] != '"' || header[len(header)-1] != '"' {
+		return nil, errPreconditionFailed
+	}
+	version, err := strconv.Atoi(header[1 : len(header)-1])
+	if err != nil {
+		return nil, errPreconditionFailed
+	}
+	return &version, nil
+}
+
+// writeVersionMismatch answers a change that lost to a concurrent one: 412 when
+// the client sent If-Match, 409 when the subscription changed between reading
+// and writing it.
+func writeVersionMismatch(w http.ResponseWriter, r *http.Request, err error) {
+	if r.Header.Get("If-Match") != "" {
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusConflict)
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type IExchangeRateUseCase interface {
+	Create(ctx context.Context, req *dto.CreateExchangeRateRequest) (*dto.ExchangeRateResponse, error)
+	GetAll(ctx context.Context, currency *string) ([]*dto.ExchangeRateResponse, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type ExchangeRateHandler struct {
+	useCase IExchangeRateUseCase
+}
+
+func NewExchangeRateHandler(useCase IExchangeRateUseCase) *ExchangeRateHandler {
+	return &ExchangeRateHandler{useCase: useCase}
+}
+
+func (h *ExchangeRateHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	var req dto.CreateExchangeRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", slog.Any("err", err))
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	rate, err := h.useCase.Create(ctx, &req)
+	if err != nil {
+		log.Error("failed to set exchange rate", slog.Any("err", err))
+		if errors.Is(err, custom_err.ErrInvalidRequest) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	log.Debug("success set exchange rate", slog.Int("id", rate.ID))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rate)
+}
+
+func (h *ExchangeRateHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	var currency *string
+	if v := r.URL.Query().Get("currency"); v != "" {
+		currency = &v
+	}
+
+	rates, err := h.useCase.GetAll(ctx, currency)
+	if err != nil {
+		log.Error("failed to get exchange rates", slog.Any("err", err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Debug("success get exchange rates", slog.Int("count", len(rates)))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rates)
+}
+
+func (h *ExchangeRateHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		log.Error("invalid id", slog.String("id", idStr), slog.Any("err", err))
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.useCase.Delete(ctx, id); err != nil {
+		log.Error("failed to delete exchange rate", slog.Int("id", id), slog.Any("err", err))
+		if errors.Is(err, custom_err.ErrExchangeRateNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	log.Debug("success delete exchange rate", slog.Int("id", id))
+	w.WriteHeader(http.StatusNoContent)
+}
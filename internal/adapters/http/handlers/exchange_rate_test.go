@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+)
+
+type mockExchangeRateUseCase struct{ mock.Mock }
+
+func (m *mockExchangeRateUseCase) Create(ctx context.Context, req *dto.CreateExchangeRateRequest) (*dto.ExchangeRateResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*dto.ExchangeRateResponse), args.Error(1)
+}
+func (m *mockExchangeRateUseCase) GetAll(ctx context.Context, currency *string) ([]*dto.ExchangeRateResponse, error) {
+	args := m.Called(ctx, currency)
+	return args.Get(0).([]*dto.ExchangeRateResponse), args.Error(1)
+}
+func (m *mockExchangeRateUseCase) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestExchangeRateHandler_Create(t *testing.T) {
+	mockUC := new(mockExchangeRateUseCase)
+	handler := NewExchangeRateHandler(mockUC)
+
+	reqBody := dto.CreateExchangeRateRequest{Currency: "USD", Rate: 92.5, ValidFrom: "09-2025"}
+	rate := &dto.ExchangeRateResponse{ID: 1, Currency: "USD", Rate: 92.5, ValidFrom: "09-2025"}
+	mockUC.On("Create", mock.Anything, &reqBody).Return(rate, nil)
+
+	body, _ := json.Marshal(reqBody)
+	r := chi.NewRouter()
+	r.Post("/exchange-rates", handler.Create)
+
+	req := httptest.NewRequest("POST", "/exchange-rates", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp dto.ExchangeRateResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, rate.ID, resp.ID)
+}
+
+func TestExchangeRateHandler_Create_Invalid(t *testing.T) {
+	mockUC := new(mockExchangeRateUseCase)
+	handler := NewExchangeRateHandler(mockUC)
+
+	mockUC.On("Create", mock.Anything, mock.AnythingOfType("*dto.CreateExchangeRateRequest")).Return((*dto.ExchangeRateResponse)(nil), custom_err.ErrInvalidRequest)
+
+	body, _ := json.Marshal(dto.CreateExchangeRateRequest{Currency: "RUB"})
+	r := chi.NewRouter()
+	r.Post("/exchange-rates", handler.Create)
+
+	req := httptest.NewRequest("POST", "/exchange-rates", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestExchangeRateHandler_GetAll(t *testing.T) {
+	mockUC := new(mockExchangeRateUseCase)
+	handler := NewExchangeRateHandler(mockUC)
+
+	currency := "EUR"
+	rates := []*dto.ExchangeRateResponse{
+		{ID: 1, Currency: "EUR", Rate: 99.1, ValidFrom: "08-2025"},
+		{ID: 2, Currency: "EUR", Rate: 101.2, ValidFrom: "09-2025"},
+	}
+	mockUC.On("GetAll", mock.Anything, &currency).Return(rates, nil)
+
+	r := chi.NewRouter()
+	r.Get("/exchange-rates", handler.GetAll)
+
+	req := httptest.NewRequest("GET", "/exchange-rates?currency=EUR", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []dto.ExchangeRateResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp, 2)
+}
+
+func TestExchangeRateHandler_Delete_NotFound(t *testing.T) {
+	mockUC := new(mockExchangeRateUseCase)
+	handler := NewExchangeRateHandler(mockUC)
+
+	mockUC.On("Delete", mock.Anything, 999).Return(custom_err.ErrExchangeRateNotFound)
+
+	r := chi.NewRouter()
+	r.Delete("/exchange-rates/{id}", handler.Delete)
+
+	req := httptest.NewRequest("DELETE", "/exchange-rates/999", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+// IdempotencyKeyHeader lets clients retry a request without repeating it.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader marks a response replayed for a repeated request.
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+const maxIdempotencyKeyLength = 255
+
+// replayedHeaders are the response headers stored with a replayed response.
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+type IIdempotencyUseCase interface {
+	Begin(ctx context.Context, scope, key string, body []byte) (*entity.IdempotencyRecord, error)
+	Complete(ctx context.Context, record *entity.IdempotencyRecord) error
+	Release(ctx context.Context, record *entity.IdempotencyRecord) error
+}
+
+type IdempotencyHandler struct {
+	useCase IIdempotencyUseCase
+}
+
+func NewIdempotencyHandler(useCase IIdempotencyUseCase) *IdempotencyHandler {
+	return &IdempotencyHandler{useCase: useCase}
+}
+
+// Wrap makes next idempotent for requests with an Idempotency-Key: the first
+// response is stored and replayed to repeats with the same key and body. A
+// key reused with another body answers 422, a key whose first request is
+// still running 409. Server errors are not stored, so the request can be
+// retried with the same key.
+func (h *IdempotencyHandler) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		log := logger.FromContext(ctx)
+
+		if len(key) > maxIdempotencyKeyLength {
+			log.Error("invalid idempotency key", slog.Int("length", len(key)))
+			http.Error(w, "invalid idempotency key", http.StatusBadRequest)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			log.Error("failed to read request", slog.Any("err", err))
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		record, err := h.useCase.Begin(ctx, r.Method+" "+r.URL.Path, key, body)
+		if err != nil {
+			log.Error("failed to begin idempotent request", slog.String("key", key), slog.Any("err", err))
+			writeIdempotencyError(w, err)
+			return
+		}
+		if record.Completed() {
+			log.Debug("replaying idempotent response", slog.String("key", key))
+			for name, value := range record.Header {
+				w.Header().Set(name, value)
+			}
+			w.Header().Set(IdempotentReplayedHeader, "true")
+			w.WriteHeader(*record.StatusCode)
+			w.Write(record.Body)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w}
+		defer func() {
+			// the request is not repeated after a panic, the key stays free
+			if p := recover(); p != nil {
+				h.useCase.Release(context.WithoutCancel(ctx), record)
+				panic(p)
+			}
+		}()
+		next.ServeHTTP(rec, r)
+
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if status >= http.StatusInternalServerError {
+			h.useCase.Release(context.WithoutCancel(ctx), record)
+			return
+		}
+		record.StatusCode = &status
+		record.Body = rec.body.Bytes()
+		record.Header = make(entity.ResponseHeader)
+		for _, name := range replayedHeaders {
+			if value := w.Header().Get(name); value != "" {
+				record.Header[name] = value
+			}
+		}
+		if err = h.useCase.Complete(context.WithoutCancel(ctx), record); err != nil {
+			h.useCase.Release(context.WithoutCancel(ctx), record)
+		}
+	})
+}
+
+// responseRecorder keeps a copy of the response it writes.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func writeIdempotencyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, custom_err.ErrIdempotencyKeyReused):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	case errors.Is(err, custom_err.ErrIdempotencyKeyInUse):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
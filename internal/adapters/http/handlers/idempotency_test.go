@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	custom_err "AggregationService/internal/errors"
+)
+
+type mockIdempotencyUseCase struct{ mock.Mock }
+
+func (m *mockIdempotencyUseCase) Begin(ctx context.Context, scope, key string, body []byte) (*entity.IdempotencyRecord, error) {
+	args := m.Called(ctx, scope, key, body)
+	return args.Get(0).(*entity.IdempotencyRecord), args.Error(1)
+}
+func (m *mockIdempotencyUseCase) Complete(ctx context.Context, record *entity.IdempotencyRecord) error {
+	return m.Called(ctx, record).Error(0)
+}
+func (m *mockIdempotencyUseCase) Release(ctx context.Context, record *entity.IdempotencyRecord) error {
+	return m.Called(ctx, record).Error(0)
+}
+
+func newIdempotentRouter(subUC *mockUseCase, idemUC *mockIdempotencyUseCase) chi.Router {
+	r := chi.NewRouter()
+	r.With(NewIdempotencyHandler(idemUC).Wrap).Post("/subscriptions", newTestHandler(subUC).Create)
+	return r
+}
+
+func TestIdempotencyHandler_FirstRequestIsStored(t *testing.T) {
+	subUC := new(mockUseCase)
+	idemUC := new(mockIdempotencyUseCase)
+	body := `{"service_name": "yandex", "price": 299, "user_id": "60601fee-2bf1-4721-ae6f-7636e79a0cba", "start_date": "07-2025"}`
+
+	reservation := &entity.IdempotencyRecord{Scope: "POST /subscriptions", Key: "key-1"}
+	idemUC.On("Begin", mock.Anything, "POST /subscriptions", "key-1", []byte(body)).Return(reservation, nil)
+	subUC.On("Create", mock.Anything, mock.Anything).Return(&dto.SubscriptionResponse{ID: 1, Version: 1}, nil)
+	idemUC.On("Complete", mock.Anything, mock.MatchedBy(func(r *entity.IdempotencyRecord) bool {
+		return *r.StatusCode == http.StatusCreated && r.Header["ETag"] == `"1"` && bytes.Contains(r.Body, []byte(`"id":1`))
+	})).Return(nil)
+
+	req := httptest.NewRequest("POST", "/subscriptions", bytes.NewReader([]byte(body)))
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	w := httptest.NewRecorder()
+	newIdempotentRouter(subUC, idemUC).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	idemUC.AssertExpectations(t)
+	subUC.AssertExpectations(t)
+}
+
+func TestIdempotencyHandler_RepeatIsReplayed(t *testing.T) {
+	subUC := new(mockUseCase)
+	idemUC := new(mockIdempotencyUseCase)
+
+	created := http.StatusCreated
+	idemUC.On("Begin", mock.Anything, "POST /subscriptions", "key-1", mock.Anything).Return(&entity.IdempotencyRecord{
+		StatusCode: &created,
+		Header:     entity.ResponseHeader{"Content-Type": "application/json", "ETag": `"1"`},
+		Body:       []byte(`{"id":1}`),
+	}, nil)
+
+	req := httptest.NewRequest("POST", "/subscriptions", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	w := httptest.NewRecorder()
+	newIdempotentRouter(subUC, idemUC).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+	assert.Equal(t, "true", w.Header().Get(IdempotentReplayedHeader))
+	assert.JSONEq(t, `{"id":1}`, w.Body.String())
+	subUC.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestIdempotencyHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{name: "Key reused with another body", err: custom_err.ErrIdempotencyKeyReused, wantCode: http.StatusUnprocessableEntity},
+		{name: "First request still running", err: custom_err.ErrIdempotencyKeyInUse, wantCode: http.StatusConflict},
+		{name: "Storage error", err: custom_err.ErrInternalServer, wantCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idemUC := new(mockIdempotencyUseCase)
+			idemUC.On("Begin", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return((*entity.IdempotencyRecord)(nil), tt.err)
+
+			req := httptest.NewRequest("POST", "/subscriptions", bytes.NewReader([]byte(`{}`)))
+			req.Header.Set(IdempotencyKeyHeader, "key-1")
+			w := httptest.NewRecorder()
+			newIdempotentRouter(new(mockUseCase), idemUC).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+func TestIdempotencyHandler_ClientErrorIsStored(t *testing.T) {
+	subUC := new(mockUseCase)
+	idemUC := new(mockIdempotencyUseCase)
+
+	reservation := &entity.IdempotencyRecord{Scope: "POST /subscriptions", Key: "key-1"}
+	idemUC.On("Begin", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(reservation, nil)
+	subUC.On("Create", mock.Anything, mock.Anything).Return((*dto.SubscriptionResponse)(nil), custom_err.ErrSubscriptionAlreadyFound)
+	idemUC.On("Complete", mock.Anything, reservation).Return(nil)
+
+	req := httptest.NewRequest("POST", "/subscriptions", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	w := httptest.NewRecorder()
+	newIdempotentRouter(subUC, idemUC).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	idemUC.AssertExpectations(t)
+}
+
+func TestIdempotencyHandler_ServerErrorReleasesKey(t *testing.T) {
+	subUC := new(mockUseCase)
+	idemUC := new(mockIdempotencyUseCase)
+
+	reservation := &entity.IdempotencyRecord{Scope: "POST /subscriptions", Key: "key-1"}
+	idemUC.On("Begin", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(reservation, nil)
+	subUC.On("Create", mock.Anything, mock.Anything).Return((*dto.SubscriptionResponse)(nil), custom_err.ErrInternalServer)
+	idemUC.On("Release", mock.Anything, reservation).Return(nil)
+
+	req := httptest.NewRequest("POST", "/subscriptions", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	w := httptest.NewRecorder()
+	newIdempotentRouter(subUC, idemUC).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	idemUC.AssertExpectations(t)
+	idemUC.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything)
+}
+
+func TestIdempotencyHandler_WithoutKey(t *testing.T) {
+	subUC := new(mockUseCase)
+	idemUC := new(mockIdempotencyUseCase)
+	subUC.On("Create", mock.Anything, mock.Anything).Return(&dto.SubscriptionResponse{ID: 1, Version: 1}, nil)
+
+	req := httptest.NewRequest("POST", "/subscriptions", bytes.NewReader([]byte(`{}`)))
+	w := httptest.NewRecorder()
+	newIdempotentRouter(subUC, idemUC).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	idemUC.AssertNotCalled(t, "Begin", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type IServiceUseCase interface {
+	Create(ctx context.Context, req *dto.CreateServiceRequest) (*dto.ServiceResponse, error)
+	GetByID(ctx context.Context, id int) (*dto.ServiceResponse, error)
+	GetAll(ctx context.Context, name *string) ([]*dto.ServiceResponse, error)
+	Update(ctx context.Context, id int, req *dto.UpdateServiceRequest) (*dto.ServiceResponse, error)
+	Delete(ctx context.Context, id int) error
+	AddAlias(ctx context.Context, id int, req *dto.CreateServiceAliasRequest) (*dto.ServiceAliasResponse, error)
+	GetAliases(ctx context.Context, id int) ([]*dto.ServiceAliasResponse, error)
+	DeleteAlias(ctx context.Context, id, aliasID int) error
+	SuggestDuplicates(ctx context.Context, req *dto.ServiceDuplicatesRequest) ([]*dto.ServiceDuplicateResponse, error)
+	Merge(ctx context.Context, id int, req *dto.MergeServiceRequest) (*dto.ServiceResponse, error)
+}
+
+type ServiceHandler struct {
+	useCase IServiceUseCase
+}
+
+func NewServiceHandler(useCase IServiceUseCase) *ServiceHandler {
+	return &ServiceHandler{useCase: useCase}
+}
+
+func (h *ServiceHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	var req dto.CreateServiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", slog.Any("err", err))
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	service, err := h.useCase.Create(ctx, &req)
+	if err != nil {
+		log.Error("failed to create service", slog.Any("err", err))
+		writeServiceError(w, err)
+		return
+	}
+
+	log.Debug("success create service", slog.Int("id", service.ID))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(service)
+}
+
+func (h *ServiceHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		log.Error("invalid id", slog.String("id", idStr), slog.Any("err", err))
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	service, err := h.useCase.GetByID(ctx, id)
+	if err != nil {
+		log.Error("failed to get service", slog.Int("id", id), slog.Any("err", err))
+		writeServiceError(w, err)
+		return
+	}
+
+	log.Debug("success get service", slog.Int("id", id))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(service)
+}
+
+func (h *ServiceHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	var name *string
+	if v := r.URL.Query().Get("name"); v != "" {
+		name = &v
+	}
+
+	services, err := h.useCase.GetAll(ctx, name)
+	if err != nil {
+		log.Error("failed to get services", slog.Any("err", err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Debug("success get services", slog.Int("count", len(services)))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(services)
+}
+
+func (h *ServiceHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		log.Error("invalid id", slog.String("id", idStr), slog.Any("err", err))
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.UpdateServiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", slog.Any("err", err))
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	service, err := h.useCase.Update(ctx, id, &req)
+	if err != nil {
+		log.Error("failed to update service", slog.Int("id", id), slog.Any("err", err))
+		writeServiceError(w, err)
+		return
+	}
+
+	log.Debug("success update service", slog.Int("id", id))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(service)
+}
+
+func (h *ServiceHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		log.Error("invalid id", slog.String("id", idStr), slog.Any("err", err))
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.useCase.Delete(ctx, id); err != nil {
+		log.Error("failed to delete service", slog.Int("id", id), slog.Any("err", err))
+		writeServiceError(w, err)
+		return
+	}
+
+	log.Debug("success delete service", slog.Int("id", id))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ServiceHandler) AddAlias(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		log.Error("invalid id", slog.String("id", idStr), slog.Any("err", err))
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.CreateServiceAliasRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", slog.Any("err", err))
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	alias, err := h.useCase.AddAlias(ctx, id, &req)
+	if err != nil {
+		log.Error("failed to add service alias", slog.Int("id", id), slog.Any("err", err))
+		writeServiceError(w, err)
+		return
+	}
+
+	log.Debug("success add service alias", slog.Int("id", id), slog.Int("alias_id", alias.ID))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(alias)
+}
+
+func (h *ServiceHandler) GetAliases(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		log.Error("invalid id", slog.String("id", idStr), slog.Any("err", err))
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	aliases, err := h.useCase.GetAliases(ctx, id)
+	if err != nil {
+		log.Error("failed to get service aliases", slog.Int("id", id), slog.Any("err", err))
+		writeServiceError(w, err)
+		return
+	}
+
+	log.Debug("success get service aliases", slog.Int("id", id), slog.Int("count", len(aliases)))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(aliases)
+}
+
+func (h *ServiceHandler) DeleteAlias(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		log.Error("invalid id", slog.String("id", idStr), slog.Any("err", err))
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	aliasIDStr := chi.URLParam(r, "aliasId")
+	aliasID, err := strconv.Atoi(aliasIDStr)
+	if err != nil {
+		log.Error("invalid alias id", slog.String("alias_id", aliasIDStr), slog.Any("err", err))
+		http.Error(w, "invalid alias id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.useCase.DeleteAlias(ctx, id, aliasID); err != nil {
+		log.Error("failed to delete service alias", slog.Int("id", id), slog.Int("alias_id", aliasID), slog.Any("err", err))
+		writeServiceError(w, err)
+		return
+	}
+
+	log.Debug("success delete service alias", slog.Int("id", id), slog.Int("alias_id", aliasID))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Duplicates lists pairs of services with similar names. min_similarity
+// defaults to 0.4 and limit to 50.
+func (h *ServiceHandler) Duplicates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	req := dto.ServiceDuplicatesRequest{MinSimilarity: 0.4, Limit: 50}
+	if v := r.URL.Query().Get("min_similarity"); v != "" {
+		minSimilarity, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			log.Error("invalid min_similarity", slog.String("min_similarity", v), slog.Any("err", err))
+			http.Error(w, "invalid min_similarity", http.StatusBadRequest)
+			return
+		}
+		req.MinSimilarity = minSimilarity
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			log.Error("invalid limit", slog.String("limit", v), slog.Any("err", err))
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		req.Limit = limit
+	}
+
+	duplicates, err := h.useCase.SuggestDuplicates(ctx, &req)
+	if err != nil {
+		log.Error("failed to suggest duplicate services", slog.Any("err", err))
+		writeServiceError(w, err)
+		return
+	}
+
+	log.Debug("success suggest duplicate services", slog.Int("count", len(duplicates)))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(duplicates)
+}
+
+func (h *ServiceHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		log.Error("invalid id", slog.String("id", idStr), slog.Any("err", err))
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.MergeServiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", slog.Any("err", err))
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	service, err := h.useCase.Merge(ctx, id, &req)
+	if err != nil {
+		log.Error("failed to merge services", slog.Int("id", id), slog.Int("source_id", req.ServiceID), slog.Any("err", err))
+		writeServiceError(w, err)
+		return
+	}
+
+	log.Debug("success merge services", slog.Int("id", id), slog.Int("source_id", req.ServiceID))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(service)
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, custom_err.ErrInvalidRequest):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, custom_err.ErrServiceNotFound), errors.Is(err, custom_err.ErrServiceAliasNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, custom_err.ErrServiceAlreadyExists), errors.Is(err, custom_err.ErrServiceInUse),
+		errors.Is(err, custom_err.ErrSubscriptionAlreadyFound):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+)
+
+type mockServiceUseCase struct{ mock.Mock }
+
+func (m *mockServiceUseCase) Create(ctx context.Context, req *dto.CreateServiceRequest) (*dto.ServiceResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*dto.ServiceResponse), args.Error(1)
+}
+func (m *mockServiceUseCase) GetByID(ctx context.Context, id int) (*dto.ServiceResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*dto.ServiceResponse), args.Error(1)
+}
+func (m *mockServiceUseCase) GetAll(ctx context.Context, name *string) ([]*dto.ServiceResponse, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).([]*dto.ServiceResponse), args.Error(1)
+}
+func (m *mockServiceUseCase) Update(ctx context.Context, id int, req *dto.UpdateServiceRequest) (*dto.ServiceResponse, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(*dto.ServiceResponse), args.Error(1)
+}
+func (m *mockServiceUseCase) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+func (m *mockServiceUseCase) AddAlias(ctx context.Context, id int, req *dto.CreateServiceAliasRequest) (*dto.ServiceAliasResponse, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(*dto.ServiceAliasResponse), args.Error(1)
+}
+func (m *mockServiceUseCase) GetAliases(ctx context.Context, id int) ([]*dto.ServiceAliasResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]*dto.ServiceAliasResponse), args.Error(1)
+}
+func (m *mockServiceUseCase) DeleteAlias(ctx context.Context, id, aliasID int) error {
+	args := m.Called(ctx, id, aliasID)
+	return args.Error(0)
+}
+func (m *mockServiceUseCase) SuggestDuplicates(ctx context.Context, req *dto.ServiceDuplicatesRequest) ([]*dto.ServiceDuplicateResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).([]*dto.ServiceDuplicateResponse), args.Error(1)
+}
+func (m *mockServiceUseCase) Merge(ctx context.Context, id int, req *dto.MergeServiceRequest) (*dto.ServiceResponse, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(*dto.ServiceResponse), args.Error(1)
+}
+
+func TestServiceHandler_Create(t *testing.T) {
+	mockUC := new(mockServiceUseCase)
+	handler := NewServiceHandler(mockUC)
+
+	reqBody := dto.CreateServiceRequest{Name: "Yandex Plus"}
+	mockUC.On("Create", mock.Anything, &reqBody).Return(&dto.ServiceResponse{ID: 1, Name: "Yandex Plus"}, nil)
+
+	body, _ := json.Marshal(reqBody)
+	r := chi.NewRouter()
+	r.Post("/services", handler.Create)
+
+	req := httptest.NewRequest("POST", "/services", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp dto.ServiceResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resp.ID)
+}
+
+func TestServiceHandler_Create_Duplicate(t *testing.T) {
+	mockUC := new(mockServiceUseCase)
+	handler := NewServiceHandler(mockUC)
+
+	mockUC.On("Create", mock.Anything, mock.AnythingOfType("*dto.CreateServiceRequest")).
+		Return((*dto.ServiceResponse)(nil), custom_err.ErrServiceAlreadyExists)
+
+	r := chi.NewRouter()
+	r.Post("/services", handler.Create)
+
+	req := httptest.NewRequest("POST", "/services", bytes.NewReader([]byte(`{"name": "yandex plus "}`)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestServiceHandler_Delete_InUse(t *testing.T) {
+	mockUC := new(mockServiceUseCase)
+	handler := NewServiceHandler(mockUC)
+
+	mockUC.On("Delete", mock.Anything, 1).Return(custom_err.ErrServiceInUse)
+
+	r := chi.NewRouter()
+	r.Delete("/services/{id}", handler.Delete)
+
+	req := httptest.NewRequest("DELETE", "/services/1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestServiceHandler_GetByID_NotFound(t *testing.T) {
+	mockUC := new(mockServiceUseCase)
+	handler := NewServiceHandler(mockUC)
+
+	mockUC.On("GetByID", mock.Anything, 999).Return((*dto.ServiceResponse)(nil), custom_err.ErrServiceNotFound)
+
+	r := chi.NewRouter()
+	r.Get("/services/{id}", handler.GetByID)
+
+	req := httptest.NewRequest("GET", "/services/999", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestServiceHandler_AddAlias(t *testing.T) {
+	mockUC := new(mockServiceUseCase)
+	handler := NewServiceHandler(mockUC)
+
+	reqBody := dto.CreateServiceAliasRequest{Alias: "Кинопоиск"}
+	mockUC.On("AddAlias", mock.Anything, 2, &reqBody).
+		Return(&dto.ServiceAliasResponse{ID: 1, ServiceID: 2, Alias: "Кинопоиск"}, nil)
+
+	body, _ := json.Marshal(reqBody)
+	r := chi.NewRouter()
+	r.Post("/services/{id}/aliases", handler.AddAlias)
+
+	req := httptest.NewRequest("POST", "/services/2/aliases", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp dto.ServiceAliasResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, resp.ServiceID)
+}
+
+func TestServiceHandler_DeleteAlias_NotFound(t *testing.T) {
+	mockUC := new(mockServiceUseCase)
+	handler := NewServiceHandler(mockUC)
+
+	mockUC.On("DeleteAlias", mock.Anything, 2, 7).Return(custom_err.ErrServiceAliasNotFound)
+
+	r := chi.NewRouter()
+	r.Delete("/services/{id}/aliases/{aliasId}", handler.DeleteAlias)
+
+	req := httptest.NewRequest("DELETE", "/services/2/aliases/7", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestServiceHandler_Duplicates(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		wantReq  *dto.ServiceDuplicatesRequest
+		wantCode int
+	}{
+		{
+			name:     "Defaults",
+			query:    "",
+			wantReq:  &dto.ServiceDuplicatesRequest{MinSimilarity: 0.4, Limit: 50},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Custom threshold",
+			query:    "?min_similarity=0.7&limit=10",
+			wantReq:  &dto.ServiceDuplicatesRequest{MinSimilarity: 0.7, Limit: 10},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Invalid threshold",
+			query:    "?min_similarity=high",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(mockServiceUseCase)
+			handler := NewServiceHandler(mockUC)
+
+			if tt.wantReq != nil {
+				mockUC.On("SuggestDuplicates", mock.Anything, tt.wantReq).
+					Return([]*dto.ServiceDuplicateResponse{{ServiceID: 1, DuplicateID: 2, Similarity: 0.8}}, nil)
+			}
+
+			r := chi.NewRouter()
+			r.Get("/services/duplicates", handler.Duplicates)
+
+			req := httptest.NewRequest("GET", "/services/duplicates"+tt.query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServiceHandler_Merge(t *testing.T) {
+	mockUC := new(mockServiceUseCase)
+	handler := NewServiceHandler(mockUC)
+
+	reqBody := dto.MergeServiceRequest{ServiceID: 3}
+	mockUC.On("Merge", mock.Anything, 2, &reqBody).Return(&dto.ServiceResponse{ID: 2, Name: "Kinopoisk"}, nil)
+
+	body, _ := json.Marshal(reqBody)
+	r := chi.NewRouter()
+	r.Post("/services/{id}/merge", handler.Merge)
+
+	req := httptest.NewRequest("POST", "/services/2/merge", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp dto.ServiceResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, resp.ID)
+}
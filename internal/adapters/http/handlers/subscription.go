@@ -2,25 +2,41 @@ package handlers
 
 import (
 	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
 	"AggregationService/internal/pkg/logger"
-	"AggregationService/internal/pkg/utils"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
-	"time"
+	"strings"
 )
 
 type ISubscriptionUseCase interface {
 	Create(ctx context.Context, req *dto.CreateSubscriptionRequest) (*dto.SubscriptionResponse, error)
-	GetByID(ctx context.Context, id int) (*dto.SubscriptionResponse, error)
-	Update(ctx context.Context, id int, req *dto.UpdateSubscriptionRequest) (*dto.SubscriptionResponse, error)
-	Delete(ctx context.Context, id int) error
-	GetAll(ctx context.Context, userID *uuid.UUID, service *string, limit, offset int) ([]*dto.SubscriptionResponse, error)
-	CalculateCost(ctx context.Context, userID *uuid.UUID, serviceName *string, startDate, endDate *time.Time) (int, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*dto.SubscriptionResponse, error)
+	PublicID(ctx context.Context, id int) (uuid.UUID, error)
+	Update(ctx context.Context, id uuid.UUID, req *dto.UpdateSubscriptionRequest, version *int) (*dto.SubscriptionResponse, error)
+	Delete(ctx context.Context, id uuid.UUID, version *int) error
+	Batch(ctx context.Context, req *dto.SubscriptionBatchRequest) (*dto.SubscriptionBatchResponse, error)
+	Import(ctx context.Context, file io.Reader, req *dto.ImportSubscriptionsRequest) (*dto.ImportSubscriptionsResponse, error)
+	Restore(ctx context.Context, id uuid.UUID) (*dto.SubscriptionResponse, error)
+	Pause(ctx context.Context, id uuid.UUID, req *dto.PauseSubscriptionRequest, version *int) (*dto.SubscriptionResponse, error)
+	Resume(ctx context.Context, id uuid.UUID, req *dto.ResumeSubscriptionRequest, version *int) (*dto.SubscriptionResponse, error)
+	Cancel(ctx context.Context, id uuid.UUID, req *dto.CancelSubscriptionRequest, version *int) (*dto.SubscriptionResponse, error)
+	GetPauses(ctx context.Context, id uuid.UUID) ([]*dto.SubscriptionPauseResponse, error)
+	GetAll(ctx context.Context, req *dto.ListSubscriptionsRequest) ([]*dto.SubscriptionResponse, error)
+	AddPrice(ctx context.Context, id uuid.UUID, req *dto.CreateSubscriptionPriceRequest) (*dto.SubscriptionPriceResponse, error)
+	GetPrices(ctx context.Context, id uuid.UUID) ([]*dto.SubscriptionPriceResponse, error)
+	CalculateCost(ctx context.Context, req *dto.CalculateCostRequest) (*dto.CalculateCostResponse, error)
+	CostTimeSeries(ctx context.Context, req *dto.CalculateCostRequest) (*dto.CostTimeSeriesResponse, error)
+	Forecast(ctx context.Context, req *dto.ForecastRequest) (*dto.ForecastResponse, error)
+	Upcoming(ctx context.Context, req *dto.UpcomingChargesRequest) (*dto.UpcomingChargesResponse, error)
 }
 
 type SubscriptionHandler struct {
@@ -45,12 +61,22 @@ func (h *SubscriptionHandler) Create(w http.ResponseWriter, r *http.Request) {
 	sub, err := h.useCase.Create(ctx, &req)
 	if err != nil {
 		log.Error("failed to create subscription", slog.Any("err", err))
+		if errors.Is(err, custom_err.ErrSubscriptionAlreadyFound) {
+			writeSubscriptionConflict(w, err)
+			return
+		}
+		// a server error must not be kept under an Idempotency-Key
+		if errors.Is(err, custom_err.ErrInternalServer) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	log.Debug("success create subscription", slog.Int("id", sub.ID))
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(sub.Version))
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(sub)
 }
@@ -59,23 +85,21 @@ func (h *SubscriptionHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := logger.FromContext(ctx)
 
-	idStr := chi.URLParam(r, "id")
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		log.Error("invalid id", slog.String("id", idStr), slog.Any("err", err))
-		http.Error(w, "invalid id", http.StatusBadRequest)
+	id, ok := h.subscriptionID(w, r)
+	if !ok {
 		return
 	}
 
 	sub, err := h.useCase.GetByID(ctx, id)
 	if err != nil {
-		log.Error("failed to get subscription", slog.Int("id", id), slog.Any("err", err))
+		log.Error("failed to get subscription", slog.String("id", id.String()), slog.Any("err", err))
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
 	}
 
-	log.Debug("success get subscription", slog.Int("id", id))
+	log.Debug("success get subscription", slog.String("id", id.String()))
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(sub.Version))
 	json.NewEncoder(w).Encode(sub)
 }
 
@@ -83,11 +107,15 @@ func (h *SubscriptionHandler) Update(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := logger.FromContext(ctx)
 
-	idStr := chi.URLParam(r, "id")
-	id, err := strconv.Atoi(idStr)
+	id, ok := h.subscriptionID(w, r)
+	if !ok {
+		return
+	}
+
+	version, err := parseIfMatch(r)
 	if err != nil {
-		log.Error("invalid id", slog.String("id", idStr), slog.Any("err", err))
-		http.Error(w, "invalid id", http.StatusBadRequest)
+		log.Error("invalid If-Match", slog.String("If-Match", r.Header.Get("If-Match")))
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
 		return
 	}
 
@@ -98,10 +126,14 @@ func (h *SubscriptionHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sub, err := h.useCase.Update(ctx, id, &req)
+	sub, err := h.useCase.Update(ctx, id, &req, version)
 	if err != nil {
-		log.Error("failed to update subscription", slog.Int("id", id), slog.Any("err", err))
-		if err.Error() == "subscription not found" {
+		log.Error("failed to update subscription", slog.String("id", id.String()), slog.Any("err", err))
+		if errors.Is(err, custom_err.ErrVersionMismatch) {
+			writeVersionMismatch(w, r, err)
+		} else if errors.Is(err, custom_err.ErrSubscriptionAlreadyFound) {
+			writeSubscriptionConflict(w, err)
+		} else if err.Error() == "subscription not found" {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusBadRequest)
@@ -109,8 +141,9 @@ func (h *SubscriptionHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.Debug("success update subscription", slog.Int("id", id))
+	log.Debug("success update subscription", slog.String("id", id.String()))
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(sub.Version))
 	json.NewEncoder(w).Encode(sub)
 }
 
@@ -118,55 +151,134 @@ func (h *SubscriptionHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := logger.FromContext(ctx)
 
-	idStr := chi.URLParam(r, "id")
-	id, err := strconv.Atoi(idStr)
+	id, ok := h.subscriptionID(w, r)
+	if !ok {
+		return
+	}
+
+	version, err := parseIfMatch(r)
 	if err != nil {
-		log.Error("invalid id", slog.String("id", idStr), slog.Any("err", err))
-		http.Error(w, "invalid id", http.StatusBadRequest)
+		log.Error("invalid If-Match", slog.String("If-Match", r.Header.Get("If-Match")))
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
 		return
 	}
 
-	if err := h.useCase.Delete(ctx, id); err != nil {
-		log.Error("failed to delete subscription", slog.Int("id", id), slog.Any("err", err))
+	if err := h.useCase.Delete(ctx, id, version); err != nil {
+		log.Error("failed to delete subscription", slog.String("id", id.String()), slog.Any("err", err))
+		if errors.Is(err, custom_err.ErrVersionMismatch) {
+			writeVersionMismatch(w, r, err)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
 	}
 
-	log.Debug("success delete subscription", slog.Int("id", id))
+	log.Debug("success delete subscription", slog.String("id", id.String()))
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (h *SubscriptionHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	id, ok := h.subscriptionID(w, r)
+	if !ok {
+		return
+	}
+
+	sub, err := h.useCase.Restore(ctx, id)
+	if err != nil {
+		log.Error("failed to restore subscription", slog.String("id", id.String()), slog.Any("err", err))
+		switch {
+		case errors.Is(err, custom_err.ErrSubscriptionNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, custom_err.ErrSubscriptionNotDeleted):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, custom_err.ErrSubscriptionAlreadyFound):
+			writeSubscriptionConflict(w, err)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	log.Debug("success restore subscription", slog.String("id", id.String()))
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(sub.Version))
+	json.NewEncoder(w).Encode(sub)
+}
+
 func (h *SubscriptionHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := logger.FromContext(ctx)
 
-	var userID *uuid.UUID
-	var serviceName *string
-	var limit, offset int
+	var req dto.ListSubscriptionsRequest
 
 	if v := r.URL.Query().Get("user_id"); v != "" {
 		uid, err := uuid.Parse(v)
 		if err == nil {
-			userID = &uid
+			req.UserID = &uid
 		} else {
 			log.Error("invalid user_id", slog.String("user_id", v), slog.Any("err", err))
 			http.Error(w, "invalid user_id", http.StatusBadRequest)
 			return
 		}
 	}
+	if v := r.URL.Query().Get("service_id"); v != "" {
+		serviceID, err := strconv.Atoi(v)
+		if err != nil {
+			log.Error("invalid service_id", slog.String("service_id", v), slog.Any("err", err))
+			http.Error(w, "invalid service_id", http.StatusBadRequest)
+			return
+		}
+		req.ServiceID = &serviceID
+	}
 	if v := r.URL.Query().Get("service_name"); v != "" {
-		serviceName = &v
+		req.ServiceName = &v
+	}
+	if v := r.URL.Query().Get("category"); v != "" {
+		req.Category = &v
+	}
+	if v := r.URL.Query().Get("tag"); v != "" {
+		req.Tag = &v
+	}
+	if v := r.URL.Query().Get("status"); v != "" {
+		req.Status = &v
+	}
+	// metadata.key=value filters match the subscription metadata
+	for param, values := range r.URL.Query() {
+		key, ok := strings.CutPrefix(param, "metadata.")
+		if !ok {
+			continue
+		}
+		if req.Metadata == nil {
+			req.Metadata = make(map[string]string)
+		}
+		req.Metadata[key] = values[0]
+	}
+	if v := r.URL.Query().Get("include_deleted"); v != "" {
+		includeDeleted, err := strconv.ParseBool(v)
+		if err != nil {
+			log.Error("invalid include_deleted", slog.String("include_deleted", v), slog.Any("err", err))
+			http.Error(w, "invalid include_deleted", http.StatusBadRequest)
+			return
+		}
+		req.IncludeDeleted = includeDeleted
 	}
 	if v := r.URL.Query().Get("limit"); v != "" {
-		limit, _ = strconv.Atoi(v)
+		req.Limit, _ = strconv.Atoi(v)
 	}
 	if v := r.URL.Query().Get("offset"); v != "" {
-		offset, _ = strconv.Atoi(v)
+		req.Offset, _ = strconv.Atoi(v)
 	}
 
-	subs, err := h.useCase.GetAll(ctx, userID, serviceName, limit, offset)
+	subs, err := h.useCase.GetAll(ctx, &req)
 	if err != nil {
 		log.Error("failed to get subscriptions", slog.Any("err", err))
+		if errors.Is(err, custom_err.ErrInvalidRequest) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -180,52 +292,221 @@ func (h *SubscriptionHandler) CalculateCost(w http.ResponseWriter, r *http.Reque
 	ctx := r.Context()
 	log := logger.FromContext(ctx)
 
-	var userID *uuid.UUID
-	var serviceName *string
-	var startDate, endDate *time.Time
+	req, err := parseCostRequest(r)
+	if err != nil {
+		log.Error("invalid cost query", slog.Any("err", err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	if v := r.URL.Query().Get("user_id"); v != "" {
+	cost, err := h.useCase.CalculateCost(ctx, req)
+	if err != nil {
+		log.Error("failed to calculate cost", slog.Any("err", err))
+		writeCostError(w, err)
+		return
+	}
+
+	log.Debug("success calculate cost", slog.String("cost", cost.TotalCost.String()))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cost)
+}
+
+func (h *SubscriptionHandler) CostTimeSeries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	req, err := parseCostRequest(r)
+	if err != nil {
+		log.Error("invalid cost query", slog.Any("err", err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	series, err := h.useCase.CostTimeSeries(ctx, req)
+	if err != nil {
+		log.Error("failed to build cost time series", slog.Any("err", err))
+		writeCostError(w, err)
+		return
+	}
+
+	log.Debug("success build cost time series", slog.Int("buckets", len(series.Buckets)))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(series)
+}
+
+func (h *SubscriptionHandler) Forecast(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	req, err := parseForecastRequest(r)
+	if err != nil {
+		log.Error("invalid forecast query", slog.Any("err", err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	forecast, err := h.useCase.Forecast(ctx, req)
+	if err != nil {
+		log.Error("failed to forecast cost", slog.Any("err", err))
+		writeCostError(w, err)
+		return
+	}
+
+	log.Debug("success forecast cost", slog.String("cost", forecast.TotalCost.String()))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(forecast)
+}
+
+// subscriptionID reads the {id} of a subscription route. It is the public
+// UUID, or during the deprecation window the old integer id: that one is
+// resolved to the public id and answered with a Deprecation header and a Link
+// to the public URL. On an invalid or unknown id it answers the request
+// itself and returns false.
+func (h *SubscriptionHandler) subscriptionID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	idStr := chi.URLParam(r, "id")
+	if id, err := uuid.Parse(idStr); err == nil {
+		return id, true
+	}
+	legacyID, err := strconv.Atoi(idStr)
+	if err != nil {
+		log.Error("invalid id", slog.String("id", idStr), slog.Any("err", err))
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return uuid.Nil, false
+	}
+
+	id, err := h.useCase.PublicID(ctx, legacyID)
+	if err != nil {
+		log.Error("failed to resolve subscription id", slog.Int("id", legacyID), slog.Any("err", err))
+		if errors.Is(err, custom_err.ErrSubscriptionNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return uuid.Nil, false
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return uuid.Nil, false
+	}
+
+	log.Debug("deprecated subscription id", slog.Int("id", legacyID), slog.String("public_id", id.String()))
+	w.Header().Set("Deprecation", "true")
+	w.Header().Set("Link", fmt.Sprintf(`</subscriptions/%s>; rel="alternate"`, id))
+	return id, true
+}
+
+// parseCostRequest reads the cost filters from the query string. group_by
+// accepts both a comma separated list and repeated parameters.
+func parseCostRequest(r *http.Request) (*dto.CalculateCostRequest, error) {
+	query := r.URL.Query()
+	req := &dto.CalculateCostRequest{
+		StartDate: query.Get("start_date"),
+		EndDate:   query.Get("end_date"),
+		Currency:  strings.ToUpper(query.Get("currency")),
+		Mode:      query.Get("mode"),
+	}
+
+	if v := query.Get("user_id"); v != "" {
 		uid, err := uuid.Parse(v)
-		if err == nil {
-			userID = &uid
-		} else {
-			log.Error("invalid user_id", slog.String("user_id", v), slog.Any("err", err))
-			http.Error(w, "invalid user_id", http.StatusBadRequest)
-			return
+		if err != nil {
+			return nil, fmt.Errorf("invalid user_id")
 		}
+		req.UserID = &uid
 	}
-	if v := r.URL.Query().Get("service_name"); v != "" {
-		serviceName = &v
+	if v := query.Get("service_id"); v != "" {
+		serviceID, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid service_id")
+		}
+		req.ServiceID = &serviceID
 	}
-	if v := r.URL.Query().Get("start_date"); v != "" {
-		t, err := utils.ParseMonthYearToTime(v)
-		if err == nil {
-			startDate = &t
-		} else {
-			log.Error("invalid start_date", slog.String("start_date", v), slog.Any("err", err))
-			http.Error(w, "invalid start_date", http.StatusBadRequest)
-			return
+	if v := query.Get("service_name"); v != "" {
+		req.ServiceName = &v
+	}
+	if v := query.Get("category"); v != "" {
+		req.Category = &v
+	}
+	if v := query.Get("tag"); v != "" {
+		req.Tag = &v
+	}
+	for _, v := range query["group_by"] {
+		for _, key := range strings.Split(v, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				req.GroupBy = append(req.GroupBy, key)
+			}
 		}
 	}
-	if v := r.URL.Query().Get("end_date"); v != "" {
-		t, err := utils.ParseMonthYearToTime(v)
-		if err == nil {
-			endDate = &t
-		} else {
-			log.Error("invalid end_date", slog.String("end_date", v), slog.Any("err", err))
-			http.Error(w, "invalid end_date", http.StatusBadRequest)
-			return
+	return req, nil
+}
+
+// defaultForecastMonths is the forecast horizon when months is not given.
+const defaultForecastMonths = 12
+
+// parseForecastRequest reads the forecast horizon and filters from the query
+// string.
+func parseForecastRequest(r *http.Request) (*dto.ForecastRequest, error) {
+	query := r.URL.Query()
+	req := &dto.ForecastRequest{
+		Months:   defaultForecastMonths,
+		Currency: strings.ToUpper(query.Get("currency")),
+		Mode:     query.Get("mode"),
+	}
+
+	if v := query.Get("months"); v != "" {
+		months, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid months")
+		}
+		req.Months = months
+	}
+	if v := query.Get("user_id"); v != "" {
+		uid, err := uuid.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user_id")
+		}
+		req.UserID = &uid
+	}
+	if v := query.Get("service_id"); v != "" {
+		serviceID, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid service_id")
 		}
+		req.ServiceID = &serviceID
+	}
+	if v := query.Get("service_name"); v != "" {
+		req.ServiceName = &v
+	}
+	if v := query.Get("category"); v != "" {
+		req.Category = &v
 	}
+	if v := query.Get("tag"); v != "" {
+		req.Tag = &v
+	}
+	return req, nil
+}
 
-	cost, err := h.useCase.CalculateCost(ctx, userID, serviceName, startDate, endDate)
-	if err != nil {
-		log.Error("failed to calculate cost", slog.Any("err", err))
+func writeCostError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, custom_err.ErrInvalidRequest):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, custom_err.ErrExchangeRateNotFound):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
 		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
 	}
+}
 
-	log.Debug("success calculate cost", slog.Int("cost", cost))
+// writeSubscriptionConflict answers 409 with the subscription that the
+// rejected one overlaps, when it is known.
+func writeSubscriptionConflict(w http.ResponseWriter, err error) {
+	resp := dto.SubscriptionConflictResponse{Error: custom_err.ErrSubscriptionAlreadyFound.Error()}
+	var conflict *custom_err.SubscriptionConflictError
+	if errors.As(err, &conflict) {
+		resp.ConflictingSubscriptionID = conflict.ConflictingID
+		if conflict.ConflictingPublicID != uuid.Nil {
+			resp.ConflictingSubscriptionPublicID = &conflict.ConflictingPublicID
+		}
+	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]int{"cost": cost})
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(resp)
 }
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// Batch runs a list of create, update and delete operations in one
+// transaction. The batch is atomic unless atomic=false. It answers 200 when
+// every operation succeeded and 207 with the status of each one otherwise.
+func (h *SubscriptionHandler) Batch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	req := dto.SubscriptionBatchRequest{Atomic: true}
+	if v := r.URL.Query().Get("atomic"); v != "" {
+		atomic, err := strconv.ParseBool(v)
+		if err != nil {
+			log.Error("invalid atomic", slog.String("atomic", v))
+			http.Error(w, "invalid atomic", http.StatusBadRequest)
+			return
+		}
+		req.Atomic = atomic
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req.Operations); err != nil {
+		log.Error("failed to decode request", slog.Any("err", err))
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.useCase.Batch(ctx, &req)
+	if err != nil {
+		log.Error("failed to run subscription batch", slog.Any("err", err))
+		if errors.Is(err, custom_err.ErrInvalidRequest) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	status := http.StatusOK
+	for i, result := range resp.Results {
+		result.Status = batchStatus(result, req.Operations[i].Version != nil)
+		if result.Err != nil {
+			result.Error = result.Err.Error()
+			status = http.StatusMultiStatus
+		}
+	}
+
+	log.Debug("success run subscription batch", slog.Int("operations", len(resp.Results)), slog.Int("status", status))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// batchStatus is the status the operation of result would get as a request of
+// its own. versioned tells whether the operation had a version, like If-Match.
+func batchStatus(result *dto.SubscriptionBatchResult, versioned bool) int {
+	switch err := result.Err; {
+	case err == nil && result.Op == entity.BatchActionCreate:
+		return http.StatusCreated
+	case err == nil && result.Op == entity.BatchActionDelete:
+		return http.StatusNoContent
+	case err == nil:
+		return http.StatusOK
+	case errors.Is(err, custom_err.ErrBatchRolledBack):
+		return http.StatusFailedDependency
+	case errors.Is(err, custom_err.ErrSubscriptionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, custom_err.ErrVersionMismatch) && versioned:
+		return http.StatusPreconditionFailed
+	case errors.Is(err, custom_err.ErrVersionMismatch), errors.Is(err, custom_err.ErrSubscriptionAlreadyFound):
+		return http.StatusConflict
+	case errors.Is(err, custom_err.ErrInternalServer):
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadRequest
+	}
+}
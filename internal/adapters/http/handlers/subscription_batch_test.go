@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+)
+
+func TestSubscriptionHandler_Batch(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	mockUC.On("Batch", mock.Anything, mock.MatchedBy(func(req *dto.SubscriptionBatchRequest) bool {
+		return req.Atomic && len(req.Operations) == 2 && req.Operations[1].Op == "delete"
+	})).Return(&dto.SubscriptionBatchResponse{Atomic: true, Results: []*dto.SubscriptionBatchResult{
+		{Op: "create", ID: &subID, Subscription: &dto.SubscriptionResponse{PublicID: subID}},
+		{Op: "delete", ID: &missingID},
+	}}, nil)
+
+	body := []byte(`[
+		{"op": "create", "data": {"service_name": "Netflix", "price": 499, "user_id": "60601fee-2bf1-4721-ae6f-7636e79a0cba", "start_date": "07-2025"}},
+		{"op": "delete", "id": "` + missingID.String() + `"}
+	]`)
+	req := httptest.NewRequest("POST", "/subscriptions/batch", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	handler.Batch(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp dto.SubscriptionBatchResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, http.StatusCreated, resp.Results[0].Status)
+	assert.Equal(t, http.StatusNoContent, resp.Results[1].Status)
+}
+
+func TestSubscriptionHandler_Batch_PartialFailure(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	version := 3
+	mockUC.On("Batch", mock.Anything, mock.MatchedBy(func(req *dto.SubscriptionBatchRequest) bool {
+		return !req.Atomic
+	})).Return(&dto.SubscriptionBatchResponse{Results: []*dto.SubscriptionBatchResult{
+		{Op: "update", ID: &subID, Err: custom_err.ErrVersionMismatch},
+		{Op: "delete", ID: &missingID, Err: custom_err.ErrSubscriptionNotFound},
+		{Op: "create", Err: custom_err.ErrInvalidRequest},
+	}}, nil)
+
+	ops, _ := json.Marshal([]dto.SubscriptionBatchOperation{
+		{Op: "update", ID: &subID, Version: &version, Data: json.RawMessage(`{"category": "music"}`)},
+		{Op: "delete", ID: &missingID},
+		{Op: "create", Data: json.RawMessage(`{}`)},
+	})
+	req := httptest.NewRequest("POST", "/subscriptions/batch?atomic=false", bytes.NewReader(ops))
+	w := httptest.NewRecorder()
+	handler.Batch(w, req)
+
+	assert.Equal(t, http.StatusMultiStatus, w.Code)
+	var resp dto.SubscriptionBatchResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, http.StatusPreconditionFailed, resp.Results[0].Status)
+	assert.Equal(t, http.StatusNotFound, resp.Results[1].Status)
+	assert.Equal(t, http.StatusBadRequest, resp.Results[2].Status)
+	assert.Equal(t, custom_err.ErrInvalidRequest.Error(), resp.Results[2].Error)
+}
+
+func TestSubscriptionHandler_Batch_RolledBack(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	mockUC.On("Batch", mock.Anything, mock.Anything).Return(&dto.SubscriptionBatchResponse{Atomic: true, Results: []*dto.SubscriptionBatchResult{
+		{Op: "create", Err: custom_err.ErrBatchRolledBack},
+		{Op: "create", Err: custom_err.ErrSubscriptionAlreadyFound},
+	}}, nil)
+
+	req := httptest.NewRequest("POST", "/subscriptions/batch", bytes.NewReader([]byte(`[{"op": "create"}, {"op": "create"}]`)))
+	w := httptest.NewRecorder()
+	handler.Batch(w, req)
+
+	assert.Equal(t, http.StatusMultiStatus, w.Code)
+	var resp dto.SubscriptionBatchResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, http.StatusFailedDependency, resp.Results[0].Status)
+	assert.Equal(t, http.StatusConflict, resp.Results[1].Status)
+}
+
+func TestSubscriptionHandler_Batch_Invalid(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	req := httptest.NewRequest("POST", "/subscriptions/batch?atomic=maybe", bytes.NewReader([]byte(`[]`)))
+	w := httptest.NewRecorder()
+	handler.Batch(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockUC.On("Batch", mock.Anything, mock.Anything).Return((*dto.SubscriptionBatchResponse)(nil), custom_err.ErrInvalidRequest)
+	req = httptest.NewRequest("POST", "/subscriptions/batch", bytes.NewReader([]byte(`[]`)))
+	w = httptest.NewRecorder()
+	handler.Batch(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// defaultUpcomingDays is the window of upcoming charges when days is not given.
+const defaultUpcomingDays = 30
+
+func (h *SubscriptionHandler) Upcoming(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	req, err := parseUpcomingRequest(r)
+	if err != nil {
+		log.Error("invalid upcoming charges query", slog.Any("err", err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	upcoming, err := h.useCase.Upcoming(ctx, req)
+	if err != nil {
+		log.Error("failed to get upcoming charges", slog.Any("err", err))
+		if errors.Is(err, custom_err.ErrInvalidRequest) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Debug("success get upcoming charges", slog.Int("users", len(upcoming.Users)))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(upcoming)
+}
+
+func parseUpcomingRequest(r *http.Request) (*dto.UpcomingChargesRequest, error) {
+	query := r.URL.Query()
+	req := &dto.UpcomingChargesRequest{Days: defaultUpcomingDays}
+
+	if v := query.Get("days"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid days")
+		}
+		req.Days = days
+	}
+	if v := query.Get("user_id"); v != "" {
+		uid, err := uuid.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user_id")
+		}
+		req.UserID = &uid
+	}
+	return req, nil
+}
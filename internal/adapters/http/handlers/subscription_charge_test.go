@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/money"
+)
+
+func TestSubscriptionHandler_Upcoming(t *testing.T) {
+	userID := uuid.MustParse("60601fee-2bf1-4721-ae6f-7636e79a0cba")
+
+	tests := []struct {
+		name       string
+		query      string
+		setupMocks func(m *mockUseCase)
+		wantCode   int
+	}{
+		{
+			name:  "Charges of a user",
+			query: "?days=7&user_id=" + userID.String(),
+			setupMocks: func(m *mockUseCase) {
+				m.On("Upcoming", mock.Anything, &dto.UpcomingChargesRequest{UserID: &userID, Days: 7}).
+					Return(&dto.UpcomingChargesResponse{From: "17-10-2026", To: "23-10-2026", Users: []*dto.UserChargesResponse{{
+						UserID:  userID,
+						Totals:  []*dto.ChargeTotalResponse{{Currency: "RUB", Amount: money.FromMajor(299)}},
+						Charges: []*dto.ChargeResponse{{SubscriptionID: 1, ServiceName: "Yandex Plus", Date: "20-10-2026", Amount: money.FromMajor(299), Currency: "RUB"}},
+					}}}, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:  "Default window",
+			query: "",
+			setupMocks: func(m *mockUseCase) {
+				m.On("Upcoming", mock.Anything, &dto.UpcomingChargesRequest{Days: defaultUpcomingDays}).
+					Return(&dto.UpcomingChargesResponse{Users: []*dto.UserChargesResponse{}}, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid days",
+			query:      "?days=week",
+			setupMocks: func(m *mockUseCase) {},
+			wantCode:   http.StatusBadRequest,
+		},
+		{
+			name:  "Days out of range",
+			query: "?days=1000",
+			setupMocks: func(m *mockUseCase) {
+				m.On("Upcoming", mock.Anything, mock.Anything).
+					Return((*dto.UpcomingChargesResponse)(nil), custom_err.ErrInvalidRequest)
+			},
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(mockUseCase)
+			handler := newTestHandler(mockUC)
+			tt.setupMocks(mockUC)
+
+			r := chi.NewRouter()
+			r.Get("/subscriptions/upcoming", handler.Upcoming)
+
+			req := httptest.NewRequest("GET", "/subscriptions/upcoming"+tt.query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode == http.StatusOK {
+				var resp dto.UpcomingChargesResponse
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			}
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
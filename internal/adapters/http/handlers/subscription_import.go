@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Import stores the subscriptions of the CSV file in the body. delimiter sets
+// the column separator, column.<field>=<header> the column of a field and
+// dry_run=true checks the file without storing it.
+func (h *SubscriptionHandler) Import(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	req := dto.ImportSubscriptionsRequest{Delimiter: r.URL.Query().Get("delimiter")}
+	for param, values := range r.URL.Query() {
+		field, ok := strings.CutPrefix(param, "column.")
+		if !ok {
+			continue
+		}
+		if req.Columns == nil {
+			req.Columns = make(map[string]string)
+		}
+		req.Columns[field] = values[0]
+	}
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		dryRun, err := strconv.ParseBool(v)
+		if err != nil {
+			log.Error("invalid dry_run", slog.String("dry_run", v), slog.Any("err", err))
+			http.Error(w, "invalid dry_run", http.StatusBadRequest)
+			return
+		}
+		req.DryRun = dryRun
+	}
+
+	resp, err := h.useCase.Import(ctx, r.Body, &req)
+	if err != nil {
+		log.Error("failed to import subscriptions", slog.Any("err", err))
+		switch {
+		case errors.Is(err, custom_err.ErrInvalidRequest), errors.Is(err, custom_err.ErrCategoryNotFound):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, custom_err.ErrSubscriptionAlreadyFound):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	log.Debug("success import subscriptions", slog.Int("rows", resp.Rows), slog.Int64("imported", resp.Imported),
+		slog.Bool("dry_run", resp.DryRun))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+)
+
+func TestSubscriptionHandler_Import(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	mockUC.On("Import", mock.Anything, mock.Anything, &dto.ImportSubscriptionsRequest{
+		Delimiter: ";",
+		Columns:   map[string]string{"service_name": "Service", "user_id": "Customer"},
+		DryRun:    true,
+	}).Return(&dto.ImportSubscriptionsResponse{DryRun: true, Rows: 2, Imported: 1, Errors: []*dto.ImportLineError{
+		{Line: 3, Errors: map[string][]string{"price": {"price is required"}}},
+	}}, nil)
+
+	req := httptest.NewRequest("POST", "/subscriptions/import?dry_run=true&delimiter=%3B&column.service_name=Service&column.user_id=Customer",
+		strings.NewReader("Service;price;Customer;start_date\n"))
+	w := httptest.NewRecorder()
+	handler.Import(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp dto.ImportSubscriptionsResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.True(t, resp.DryRun)
+	assert.Equal(t, int64(1), resp.Imported)
+	assert.Equal(t, 3, resp.Errors[0].Line)
+}
+
+func TestSubscriptionHandler_Import_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		err        error
+		wantStatus int
+	}{
+		{name: "Invalid dry_run", query: "?dry_run=maybe", wantStatus: http.StatusBadRequest},
+		{name: "Invalid file", err: custom_err.ErrInvalidRequest, wantStatus: http.StatusBadRequest},
+		{name: "Hidden overlap", err: custom_err.ErrSubscriptionAlreadyFound, wantStatus: http.StatusConflict},
+		{name: "Internal error", err: custom_err.ErrInternalServer, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(mockUseCase)
+			handler := newTestHandler(mockUC)
+			if tt.err != nil {
+				mockUC.On("Import", mock.Anything, mock.Anything, mock.Anything).Return((*dto.ImportSubscriptionsResponse)(nil), tt.err)
+			}
+
+			req := httptest.NewRequest("POST", "/subscriptions/import"+tt.query, strings.NewReader("service_name\n"))
+			w := httptest.NewRecorder()
+			handler.Import(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
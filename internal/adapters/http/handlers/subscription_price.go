@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+func (h *SubscriptionHandler) AddPrice(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	id, ok := h.subscriptionID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateSubscriptionPriceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", slog.Any("err", err))
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	price, err := h.useCase.AddPrice(ctx, id, &req)
+	if err != nil {
+		log.Error("failed to add subscription price", slog.String("id", id.String()), slog.Any("err", err))
+		writePriceError(w, err)
+		return
+	}
+
+	log.Debug("success add subscription price", slog.String("id", id.String()), slog.Int("price_id", price.ID))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(price)
+}
+
+func (h *SubscriptionHandler) GetPrices(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	id, ok := h.subscriptionID(w, r)
+	if !ok {
+		return
+	}
+
+	prices, err := h.useCase.GetPrices(ctx, id)
+	if err != nil {
+		log.Error("failed to get subscription prices", slog.String("id", id.String()), slog.Any("err", err))
+		writePriceError(w, err)
+		return
+	}
+
+	log.Debug("success get subscription prices", slog.String("id", id.String()), slog.Int("count", len(prices)))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prices)
+}
+
+func writePriceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, custom_err.ErrInvalidRequest):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, custom_err.ErrSubscriptionNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
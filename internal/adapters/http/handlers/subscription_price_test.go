@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/money"
+)
+
+func TestSubscriptionHandler_AddPrice(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	priceReq := &dto.CreateSubscriptionPriceRequest{Price: money.FromMajor(399), EffectiveFrom: "11-2025"}
+	price := &dto.SubscriptionPriceResponse{ID: 2, SubscriptionID: 1, Price: money.FromMajor(399), EffectiveFrom: "11-2025"}
+	mockUC.On("AddPrice", mock.Anything, subID, priceReq).Return(price, nil)
+
+	r := chi.NewRouter()
+	r.Post("/subscriptions/{id}/prices", handler.AddPrice)
+
+	body := []byte(`{"price": 399, "effective_from": "11-2025"}`)
+	req := httptest.NewRequest("POST", "/subscriptions/"+subID.String()+"/prices", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp dto.SubscriptionPriceResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "11-2025", resp.EffectiveFrom)
+	assert.Equal(t, money.FromMajor(399), resp.Price)
+}
+
+func TestSubscriptionHandler_AddPrice_NotFound(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	mockUC.On("AddPrice", mock.Anything, missingID, mock.AnythingOfType("*dto.CreateSubscriptionPriceRequest")).
+		Return((*dto.SubscriptionPriceResponse)(nil), custom_err.ErrSubscriptionNotFound)
+
+	r := chi.NewRouter()
+	r.Post("/subscriptions/{id}/prices", handler.AddPrice)
+
+	body := []byte(`{"price": 399, "effective_from": "11-2025"}`)
+	req := httptest.NewRequest("POST", "/subscriptions/"+missingID.String()+"/prices", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSubscriptionHandler_AddPrice_Invalid(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	mockUC.On("AddPrice", mock.Anything, subID, mock.AnythingOfType("*dto.CreateSubscriptionPriceRequest")).
+		Return((*dto.SubscriptionPriceResponse)(nil), custom_err.ErrInvalidRequest)
+
+	r := chi.NewRouter()
+	r.Post("/subscriptions/{id}/prices", handler.AddPrice)
+
+	body := []byte(`{"price": 399, "effective_from": "01-2020"}`)
+	req := httptest.NewRequest("POST", "/subscriptions/"+subID.String()+"/prices", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSubscriptionHandler_GetPrices(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	prices := []*dto.SubscriptionPriceResponse{
+		{ID: 1, SubscriptionID: 1, Price: money.FromMajor(299), EffectiveFrom: "09-2025"},
+		{ID: 2, SubscriptionID: 1, Price: money.FromMajor(399), EffectiveFrom: "11-2025"},
+	}
+	mockUC.On("GetPrices", mock.Anything, subID).Return(prices, nil)
+
+	r := chi.NewRouter()
+	r.Get("/subscriptions/{id}/prices", handler.GetPrices)
+
+	req := httptest.NewRequest("GET", "/subscriptions/"+subID.String()+"/prices", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []*dto.SubscriptionPriceResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp, 2)
+}
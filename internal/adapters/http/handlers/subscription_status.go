@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+func (h *SubscriptionHandler) Pause(w http.ResponseWriter, r *http.Request) {
+	var req dto.PauseSubscriptionRequest
+	h.changeStatus(w, r, "pause", &req, func(id uuid.UUID, version *int) (*dto.SubscriptionResponse, error) {
+		return h.useCase.Pause(r.Context(), id, &req, version)
+	})
+}
+
+func (h *SubscriptionHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	var req dto.ResumeSubscriptionRequest
+	h.changeStatus(w, r, "resume", &req, func(id uuid.UUID, version *int) (*dto.SubscriptionResponse, error) {
+		return h.useCase.Resume(r.Context(), id, &req, version)
+	})
+}
+
+func (h *SubscriptionHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	var req dto.CancelSubscriptionRequest
+	h.changeStatus(w, r, "cancel", &req, func(id uuid.UUID, version *int) (*dto.SubscriptionResponse, error) {
+		return h.useCase.Cancel(r.Context(), id, &req, version)
+	})
+}
+
+func (h *SubscriptionHandler) GetPauses(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	id, ok := h.subscriptionID(w, r)
+	if !ok {
+		return
+	}
+
+	pauses, err := h.useCase.GetPauses(ctx, id)
+	if err != nil {
+		log.Error("failed to get subscription pauses", slog.String("id", id.String()), slog.Any("err", err))
+		writeStatusError(w, r, err)
+		return
+	}
+
+	log.Debug("success get subscription pauses", slog.String("id", id.String()), slog.Int("count", len(pauses)))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pauses)
+}
+
+// changeStatus runs one of the status actions: it reads the id, If-Match and
+// the optional body into req and answers with the changed subscription.
+func (h *SubscriptionHandler) changeStatus(w http.ResponseWriter, r *http.Request, action string, req any,
+	change func(id uuid.UUID, version *int) (*dto.SubscriptionResponse, error)) {
+	log := logger.FromContext(r.Context())
+
+	id, ok := h.subscriptionID(w, r)
+	if !ok {
+		return
+	}
+
+	version, err := parseIfMatch(r)
+	if err != nil {
+		log.Error("invalid If-Match", slog.String("If-Match", r.Header.Get("If-Match")))
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		return
+	}
+
+	// the body is optional, every field has a default
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil && !errors.Is(err, io.EOF) {
+		log.Error("failed to decode request", slog.Any("err", err))
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	sub, err := change(id, version)
+	if err != nil {
+		log.Error("failed to "+action+" subscription", slog.String("id", id.String()), slog.Any("err", err))
+		writeStatusError(w, r, err)
+		return
+	}
+
+	log.Debug("success "+action+" subscription", slog.String("id", id.String()), slog.String("status", sub.Status))
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(sub.Version))
+	json.NewEncoder(w).Encode(sub)
+}
+
+func writeStatusError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, custom_err.ErrInvalidRequest):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, custom_err.ErrSubscriptionNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, custom_err.ErrVersionMismatch):
+		writeVersionMismatch(w, r, err)
+	case errors.Is(err, custom_err.ErrInvalidStatusTransition):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
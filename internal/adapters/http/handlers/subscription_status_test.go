@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+)
+
+func TestSubscriptionHandler_Pause(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	from := "11-2025"
+	version := 3
+	mockUC.On("Pause", mock.Anything, subID, &dto.PauseSubscriptionRequest{From: &from}, &version).
+		Return(&dto.SubscriptionResponse{ID: 1, Status: "paused", Version: 4}, nil)
+
+	r := chi.NewRouter()
+	r.Post("/subscriptions/{id}/pause", handler.Pause)
+
+	req := httptest.NewRequest("POST", "/subscriptions/"+subID.String()+"/pause", bytes.NewReader([]byte(`{"from": "11-2025"}`)))
+	req.Header.Set("If-Match", `"3"`)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+	var resp dto.SubscriptionResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "paused", resp.Status)
+}
+
+func TestSubscriptionHandler_Resume_EmptyBody(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	mockUC.On("Resume", mock.Anything, subID, &dto.ResumeSubscriptionRequest{}, (*int)(nil)).
+		Return(&dto.SubscriptionResponse{ID: 1, Status: "active", Version: 5}, nil)
+
+	r := chi.NewRouter()
+	r.Post("/subscriptions/{id}/resume", handler.Resume)
+
+	req := httptest.NewRequest("POST", "/subscriptions/"+subID.String()+"/resume", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestSubscriptionHandler_Cancel_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		ifMatch  string
+		wantCode int
+	}{
+		{name: "Not found", err: custom_err.ErrSubscriptionNotFound, wantCode: http.StatusNotFound},
+		{name: "Already cancelled", err: custom_err.ErrInvalidStatusTransition, wantCode: http.StatusConflict},
+		{name: "Invalid end date", err: custom_err.ErrInvalidRequest, wantCode: http.StatusBadRequest},
+		{name: "Stale If-Match", err: custom_err.ErrVersionMismatch, ifMatch: `"1"`, wantCode: http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(mockUseCase)
+			handler := newTestHandler(mockUC)
+
+			mockUC.On("Cancel", mock.Anything, subID, mock.AnythingOfType("*dto.CancelSubscriptionRequest"), mock.Anything).
+				Return((*dto.SubscriptionResponse)(nil), tt.err)
+
+			r := chi.NewRouter()
+			r.Post("/subscriptions/{id}/cancel", handler.Cancel)
+
+			req := httptest.NewRequest("POST", "/subscriptions/"+subID.String()+"/cancel", bytes.NewReader([]byte(`{"end_date": "12-2025"}`)))
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+func TestSubscriptionHandler_GetPauses(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	resumed := "01-2026"
+	pauses := []*dto.SubscriptionPauseResponse{{ID: 1, SubscriptionID: 1, PausedFrom: "11-2025", ResumedFrom: &resumed}}
+	mockUC.On("GetPauses", mock.Anything, subID).Return(pauses, nil)
+
+	r := chi.NewRouter()
+	r.Get("/subscriptions/{id}/pauses", handler.GetPauses)
+
+	req := httptest.NewRequest("GET", "/subscriptions/"+subID.String()+"/pauses", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []dto.SubscriptionPauseResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp, 1)
+	assert.Equal(t, "11-2025", resp[0].PausedFrom)
+}
@@ -4,10 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
-	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -16,6 +16,7 @@ import (
 
 	"AggregationService/internal/domain/models/dto"
 	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/money"
 )
 
 // Мок usecase с правильной сигнатурой CalculateCost
@@ -25,27 +26,86 @@ func (m *mockUseCase) Create(ctx context.Context, req *dto.CreateSubscriptionReq
 	args := m.Called(ctx, req)
 	return args.Get(0).(*dto.SubscriptionResponse), args.Error(1)
 }
-func (m *mockUseCase) GetByID(ctx context.Context, id int) (*dto.SubscriptionResponse, error) {
+func (m *mockUseCase) GetByID(ctx context.Context, id uuid.UUID) (*dto.SubscriptionResponse, error) {
 	args := m.Called(ctx, id)
 	return args.Get(0).(*dto.SubscriptionResponse), args.Error(1)
 }
-func (m *mockUseCase) Update(ctx context.Context, id int, req *dto.UpdateSubscriptionRequest) (*dto.SubscriptionResponse, error) {
-	args := m.Called(ctx, id, req)
+func (m *mockUseCase) PublicID(ctx context.Context, id int) (uuid.UUID, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+func (m *mockUseCase) Update(ctx context.Context, id uuid.UUID, req *dto.UpdateSubscriptionRequest, version *int) (*dto.SubscriptionResponse, error) {
+	args := m.Called(ctx, id, req, version)
 	return args.Get(0).(*dto.SubscriptionResponse), args.Error(1)
 }
-func (m *mockUseCase) Delete(ctx context.Context, id int) error {
-	args := m.Called(ctx, id)
+func (m *mockUseCase) Delete(ctx context.Context, id uuid.UUID, version *int) error {
+	args := m.Called(ctx, id, version)
 	return args.Error(0)
 }
-func (m *mockUseCase) GetAll(ctx context.Context, userID *uuid.UUID, service *string, limit, offset int) ([]*dto.SubscriptionResponse, error) {
-	args := m.Called(ctx, userID, service, limit, offset)
+func (m *mockUseCase) Batch(ctx context.Context, req *dto.SubscriptionBatchRequest) (*dto.SubscriptionBatchResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*dto.SubscriptionBatchResponse), args.Error(1)
+}
+func (m *mockUseCase) Import(ctx context.Context, file io.Reader, req *dto.ImportSubscriptionsRequest) (*dto.ImportSubscriptionsResponse, error) {
+	args := m.Called(ctx, file, req)
+	return args.Get(0).(*dto.ImportSubscriptionsResponse), args.Error(1)
+}
+func (m *mockUseCase) Restore(ctx context.Context, id uuid.UUID) (*dto.SubscriptionResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*dto.SubscriptionResponse), args.Error(1)
+}
+func (m *mockUseCase) Pause(ctx context.Context, id uuid.UUID, req *dto.PauseSubscriptionRequest, version *int) (*dto.SubscriptionResponse, error) {
+	args := m.Called(ctx, id, req, version)
+	return args.Get(0).(*dto.SubscriptionResponse), args.Error(1)
+}
+func (m *mockUseCase) Resume(ctx context.Context, id uuid.UUID, req *dto.ResumeSubscriptionRequest, version *int) (*dto.SubscriptionResponse, error) {
+	args := m.Called(ctx, id, req, version)
+	return args.Get(0).(*dto.SubscriptionResponse), args.Error(1)
+}
+func (m *mockUseCase) Cancel(ctx context.Context, id uuid.UUID, req *dto.CancelSubscriptionRequest, version *int) (*dto.SubscriptionResponse, error) {
+	args := m.Called(ctx, id, req, version)
+	return args.Get(0).(*dto.SubscriptionResponse), args.Error(1)
+}
+func (m *mockUseCase) GetPauses(ctx context.Context, id uuid.UUID) ([]*dto.SubscriptionPauseResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]*dto.SubscriptionPauseResponse), args.Error(1)
+}
+func (m *mockUseCase) GetAll(ctx context.Context, req *dto.ListSubscriptionsRequest) ([]*dto.SubscriptionResponse, error) {
+	args := m.Called(ctx, req)
 	return args.Get(0).([]*dto.SubscriptionResponse), args.Error(1)
 }
-func (m *mockUseCase) CalculateCost(ctx context.Context, userID *uuid.UUID, serviceName *string, startDate, endDate *time.Time) (int, error) {
-	args := m.Called(ctx, userID, serviceName, startDate, endDate)
-	return args.Int(0), args.Error(1)
+func (m *mockUseCase) CalculateCost(ctx context.Context, req *dto.CalculateCostRequest) (*dto.CalculateCostResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*dto.CalculateCostResponse), args.Error(1)
+}
+func (m *mockUseCase) CostTimeSeries(ctx context.Context, req *dto.CalculateCostRequest) (*dto.CostTimeSeriesResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*dto.CostTimeSeriesResponse), args.Error(1)
+}
+func (m *mockUseCase) AddPrice(ctx context.Context, id uuid.UUID, req *dto.CreateSubscriptionPriceRequest) (*dto.SubscriptionPriceResponse, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(*dto.SubscriptionPriceResponse), args.Error(1)
+}
+func (m *mockUseCase) GetPrices(ctx context.Context, id uuid.UUID) ([]*dto.SubscriptionPriceResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]*dto.SubscriptionPriceResponse), args.Error(1)
+}
+func (m *mockUseCase) Forecast(ctx context.Context, req *dto.ForecastRequest) (*dto.ForecastResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*dto.ForecastResponse), args.Error(1)
+}
+func (m *mockUseCase) Upcoming(ctx context.Context, req *dto.UpcomingChargesRequest) (*dto.UpcomingChargesResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*dto.UpcomingChargesResponse), args.Error(1)
 }
 
+// subID and missingID are the public ids of an existing and a missing
+// subscription.
+var (
+	subID     = uuid.MustParse("01932c07-a6b2-7a3e-9f1c-2d4b5e6f7a81")
+	missingID = uuid.MustParse("01932c07-a6b2-7a3e-9f1c-000000000999")
+)
+
 // Конструктор хэндлера
 func newTestHandler(useCase *mockUseCase) *SubscriptionHandler {
 	return &SubscriptionHandler{useCase: useCase}
@@ -80,6 +140,55 @@ func TestSubscriptionHandler_Create(t *testing.T) {
 	assert.Equal(t, sub.ID, resp.ID)
 }
 
+func TestSubscriptionHandler_Create_Overlap(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	mockUC.On("Create", mock.Anything, mock.AnythingOfType("*dto.CreateSubscriptionRequest")).
+		Return((*dto.SubscriptionResponse)(nil), &custom_err.SubscriptionConflictError{ConflictingID: 42})
+
+	body, _ := json.Marshal(dto.CreateSubscriptionRequest{
+		UserID:      uuid.New(),
+		ServiceName: "yandex",
+		Price:       299,
+		StartDate:   "09-2025",
+	})
+	r := chi.NewRouter()
+	r.Post("/subscriptions", handler.Create)
+
+	req := httptest.NewRequest("POST", "/subscriptions", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var resp dto.SubscriptionConflictResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, resp.ConflictingSubscriptionID)
+}
+
+func TestSubscriptionHandler_Create_DecimalPrice(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	sub := &dto.SubscriptionResponse{ID: 1, ServiceName: "kinopoisk", Price: 19990}
+	mockUC.On("Create", mock.Anything, mock.MatchedBy(func(req *dto.CreateSubscriptionRequest) bool {
+		return req.Price == money.Amount(19990)
+	})).Return(sub, nil)
+
+	body := `{"service_name": "kinopoisk", "price": 199.90, "user_id": "` + uuid.New().String() + `", "start_date": "09-2025"}`
+	r := chi.NewRouter()
+	r.Post("/subscriptions", handler.Create)
+
+	req := httptest.NewRequest("POST", "/subscriptions", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"price":199.90`)
+}
+
 func TestSubscriptionHandler_Create_Invalid(t *testing.T) {
 	mockUC := new(mockUseCase)
 	handler := newTestHandler(mockUC)
@@ -103,17 +212,18 @@ func TestSubscriptionHandler_GetByID(t *testing.T) {
 	mockUC := new(mockUseCase)
 	handler := newTestHandler(mockUC)
 
-	sub := &dto.SubscriptionResponse{ID: 1, ServiceName: "yandex"}
-	mockUC.On("GetByID", mock.Anything, 1).Return(sub, nil)
+	sub := &dto.SubscriptionResponse{ID: 1, ServiceName: "yandex", Version: 5}
+	mockUC.On("GetByID", mock.Anything, subID).Return(sub, nil)
 
 	r := chi.NewRouter()
 	r.Get("/subscriptions/{id}", handler.GetByID)
 
-	req := httptest.NewRequest("GET", "/subscriptions/1", nil)
+	req := httptest.NewRequest("GET", "/subscriptions/"+subID.String(), nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"5"`, w.Header().Get("ETag"))
 	var resp dto.SubscriptionResponse
 	err := json.NewDecoder(w.Body).Decode(&resp)
 	assert.NoError(t, err)
@@ -124,59 +234,157 @@ func TestSubscriptionHandler_GetByID_NotFound(t *testing.T) {
 	mockUC := new(mockUseCase)
 	handler := newTestHandler(mockUC)
 
-	mockUC.On("GetByID", mock.Anything, 999).Return((*dto.SubscriptionResponse)(nil), custom_err.ErrSubscriptionNotFound)
+	mockUC.On("GetByID", mock.Anything, missingID).Return((*dto.SubscriptionResponse)(nil), custom_err.ErrSubscriptionNotFound)
 
 	r := chi.NewRouter()
 	r.Get("/subscriptions/{id}", handler.GetByID)
 
-	req := httptest.NewRequest("GET", "/subscriptions/999", nil)
+	req := httptest.NewRequest("GET", "/subscriptions/"+missingID.String(), nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
+func TestSubscriptionHandler_DeprecatedIntegerID(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		setupMocks func(uc *mockUseCase)
+		wantCode   int
+	}{
+		{
+			name: "Resolved to public id",
+			id:   "1",
+			setupMocks: func(uc *mockUseCase) {
+				uc.On("PublicID", mock.Anything, 1).Return(subID, nil)
+				uc.On("GetByID", mock.Anything, subID).Return(&dto.SubscriptionResponse{ID: 1, PublicID: subID, Version: 1}, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "Unknown integer id",
+			id:   "999",
+			setupMocks: func(uc *mockUseCase) {
+				uc.On("PublicID", mock.Anything, 999).Return(uuid.Nil, custom_err.ErrSubscriptionNotFound)
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:       "Neither uuid nor integer",
+			id:         "abc",
+			setupMocks: func(uc *mockUseCase) {},
+			wantCode:   http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(mockUseCase)
+			tt.setupMocks(mockUC)
+
+			r := chi.NewRouter()
+			r.Get("/subscriptions/{id}", newTestHandler(mockUC).GetByID)
+
+			req := httptest.NewRequest("GET", "/subscriptions/"+tt.id, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode == http.StatusOK {
+				assert.Equal(t, "true", w.Header().Get("Deprecation"))
+				assert.Equal(t, `</subscriptions/`+subID.String()+`>; rel="alternate"`, w.Header().Get("Link"))
+			}
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
 func TestSubscriptionHandler_Update(t *testing.T) {
 	mockUC := new(mockUseCase)
 	handler := newTestHandler(mockUC)
 
 	serviceName := "yandex plus"
-	price := 399
+	price := money.FromMajor(399)
 	reqBody := dto.UpdateSubscriptionRequest{
 		ServiceName: &serviceName,
 		Price:       &price,
 	}
-	sub := &dto.SubscriptionResponse{ID: 1, ServiceName: "yandex plus"}
-	mockUC.On("Update", mock.Anything, 1, mock.AnythingOfType("*dto.UpdateSubscriptionRequest")).Return(sub, nil)
+	sub := &dto.SubscriptionResponse{ID: 1, ServiceName: "yandex plus", Version: 2}
+	mockUC.On("Update", mock.Anything, subID, mock.AnythingOfType("*dto.UpdateSubscriptionRequest"), (*int)(nil)).Return(sub, nil)
 
 	body, _ := json.Marshal(reqBody)
 	r := chi.NewRouter()
 	r.Put("/subscriptions/{id}", handler.Update)
 
-	req := httptest.NewRequest("PUT", "/subscriptions/1", bytes.NewReader(body))
+	req := httptest.NewRequest("PUT", "/subscriptions/"+subID.String(), bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"2"`, w.Header().Get("ETag"))
 	var resp dto.SubscriptionResponse
 	err := json.NewDecoder(w.Body).Decode(&resp)
 	assert.NoError(t, err)
 	assert.Equal(t, sub.ID, resp.ID)
 }
 
+func TestSubscriptionHandler_Update_IfMatch(t *testing.T) {
+	version := 3
+	tests := []struct {
+		name     string
+		ifMatch  string
+		version  *int
+		err      error
+		wantCode int
+	}{
+		{name: "Matching version", ifMatch: `"3"`, version: &version, wantCode: http.StatusOK},
+		{name: "Any version", ifMatch: "*", wantCode: http.StatusOK},
+		{name: "Stale version", ifMatch: `"3"`, version: &version, err: custom_err.ErrVersionMismatch, wantCode: http.StatusPreconditionFailed},
+		{name: "Concurrent change without If-Match", err: custom_err.ErrVersionMismatch, wantCode: http.StatusConflict},
+		{name: "Weak tag", ifMatch: `W/"3"`, wantCode: http.StatusPreconditionFailed},
+		{name: "Malformed tag", ifMatch: "3", wantCode: http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(mockUseCase)
+			handler := newTestHandler(mockUC)
+
+			mockUC.On("Update", mock.Anything, subID, mock.AnythingOfType("*dto.UpdateSubscriptionRequest"), tt.version).
+				Return(&dto.SubscriptionResponse{ID: 1, Version: 4}, tt.err).Maybe()
+
+			r := chi.NewRouter()
+			r.Put("/subscriptions/{id}", handler.Update)
+
+			req := httptest.NewRequest("PUT", "/subscriptions/"+subID.String(), bytes.NewReader([]byte(`{}`)))
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode == http.StatusOK {
+				assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
 func TestSubscriptionHandler_Update_NotFound(t *testing.T) {
 	mockUC := new(mockUseCase)
 	handler := newTestHandler(mockUC)
 
-	mockUC.On("Update", mock.Anything, 999, mock.AnythingOfType("*dto.UpdateSubscriptionRequest")).Return((*dto.SubscriptionResponse)(nil), custom_err.ErrSubscriptionNotFound)
+	mockUC.On("Update", mock.Anything, missingID, mock.AnythingOfType("*dto.UpdateSubscriptionRequest"), (*int)(nil)).Return((*dto.SubscriptionResponse)(nil), custom_err.ErrSubscriptionNotFound)
 
 	reqBody := dto.UpdateSubscriptionRequest{}
 	body, _ := json.Marshal(reqBody)
 	r := chi.NewRouter()
 	r.Put("/subscriptions/{id}", handler.Update)
 
-	req := httptest.NewRequest("PUT", "/subscriptions/999", bytes.NewReader(body))
+	req := httptest.NewRequest("PUT", "/subscriptions/"+missingID.String(), bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
@@ -188,12 +396,12 @@ func TestSubscriptionHandler_Delete(t *testing.T) {
 	mockUC := new(mockUseCase)
 	handler := newTestHandler(mockUC)
 
-	mockUC.On("Delete", mock.Anything, 1).Return(nil)
+	mockUC.On("Delete", mock.Anything, subID, (*int)(nil)).Return(nil)
 
 	r := chi.NewRouter()
 	r.Delete("/subscriptions/{id}", handler.Delete)
 
-	req := httptest.NewRequest("DELETE", "/subscriptions/1", nil)
+	req := httptest.NewRequest("DELETE", "/subscriptions/"+subID.String(), nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
@@ -204,18 +412,89 @@ func TestSubscriptionHandler_Delete_NotFound(t *testing.T) {
 	mockUC := new(mockUseCase)
 	handler := newTestHandler(mockUC)
 
-	mockUC.On("Delete", mock.Anything, 999).Return(custom_err.ErrSubscriptionNotFound)
+	mockUC.On("Delete", mock.Anything, missingID, (*int)(nil)).Return(custom_err.ErrSubscriptionNotFound)
 
 	r := chi.NewRouter()
 	r.Delete("/subscriptions/{id}", handler.Delete)
 
-	req := httptest.NewRequest("DELETE", "/subscriptions/999", nil)
+	req := httptest.NewRequest("DELETE", "/subscriptions/"+missingID.String(), nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
+func TestSubscriptionHandler_Delete_IfMatch(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	version := 2
+	mockUC.On("Delete", mock.Anything, subID, &version).Return(custom_err.ErrVersionMismatch)
+
+	r := chi.NewRouter()
+	r.Delete("/subscriptions/{id}", handler.Delete)
+
+	req := httptest.NewRequest("DELETE", "/subscriptions/"+subID.String(), nil)
+	req.Header.Set("If-Match", `"2"`)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+}
+
+func TestSubscriptionHandler_Restore(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   *dto.SubscriptionResponse
+		err      error
+		wantCode int
+	}{
+		{name: "Restored", result: &dto.SubscriptionResponse{ID: 1, ServiceName: "yandex"}, wantCode: http.StatusOK},
+		{name: "Not found", err: custom_err.ErrSubscriptionNotFound, wantCode: http.StatusNotFound},
+		{name: "Not deleted", err: custom_err.ErrSubscriptionNotDeleted, wantCode: http.StatusConflict},
+		{name: "Overlaps active", err: &custom_err.SubscriptionConflictError{ConflictingID: 2}, wantCode: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(mockUseCase)
+			handler := newTestHandler(mockUC)
+
+			mockUC.On("Restore", mock.Anything, subID).Return(tt.result, tt.err)
+
+			r := chi.NewRouter()
+			r.Post("/subscriptions/{id}/restore", handler.Restore)
+
+			req := httptest.NewRequest("POST", "/subscriptions/"+subID.String()+"/restore", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+func TestSubscriptionHandler_GetAll_IncludeDeleted(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	mockUC.On("GetAll", mock.Anything, &dto.ListSubscriptionsRequest{IncludeDeleted: true}).
+		Return([]*dto.SubscriptionResponse{{ID: 1}, {ID: 2}}, nil)
+
+	r := chi.NewRouter()
+	r.Get("/subscriptions", handler.GetAll)
+
+	req := httptest.NewRequest("GET", "/subscriptions?include_deleted=true", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest("GET", "/subscriptions?include_deleted=maybe", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestSubscriptionHandler_GetAll(t *testing.T) {
 	mockUC := new(mockUseCase)
 	handler := newTestHandler(mockUC)
@@ -226,7 +505,11 @@ func TestSubscriptionHandler_GetAll(t *testing.T) {
 		{ID: 1, ServiceName: "yandex"},
 		{ID: 2, ServiceName: "yandex plus"},
 	}
-	mockUC.On("GetAll", mock.Anything, &validUUID, &serviceName, 10, 0).Return(subs, nil)
+	mockUC.On("GetAll", mock.Anything, &dto.ListSubscriptionsRequest{
+		UserID:      &validUUID,
+		ServiceName: &serviceName,
+		Limit:       10,
+	}).Return(subs, nil)
 
 	r := chi.NewRouter()
 	r.Get("/subscriptions", handler.GetAll)
@@ -242,15 +525,44 @@ func TestSubscriptionHandler_GetAll(t *testing.T) {
 	assert.Len(t, resp, 2)
 }
 
+func TestSubscriptionHandler_GetAll_Metadata(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	mockUC.On("GetAll", mock.Anything, &dto.ListSubscriptionsRequest{
+		Metadata: map[string]string{"plan": "family", "cost.center": "R&D"},
+	}).Return([]*dto.SubscriptionResponse{{ID: 1, Metadata: map[string]string{"plan": "family", "cost.center": "R&D"}}}, nil)
+
+	r := chi.NewRouter()
+	r.Get("/subscriptions", handler.GetAll)
+
+	req := httptest.NewRequest("GET", "/subscriptions?metadata.plan=family&metadata.cost.center=R%26D", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
 func TestSubscriptionHandler_CalculateCost(t *testing.T) {
 	mockUC := new(mockUseCase)
 	handler := newTestHandler(mockUC)
 
 	validUUID := uuid.New()
 	serviceName := "yandex"
-	startDate := time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)
-	endDate := time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)
-	mockUC.On("CalculateCost", mock.Anything, &validUUID, &serviceName, &startDate, &endDate).Return(451, nil)
+	costReq := &dto.CalculateCostRequest{
+		UserID:      &validUUID,
+		ServiceName: &serviceName,
+		StartDate:   "09-2025",
+		EndDate:     "12-2025",
+	}
+	cost := &dto.CalculateCostResponse{
+		TotalCost: 1804,
+		Subscriptions: []*dto.SubscriptionCostResponse{
+			{SubscriptionID: 1, ServiceName: "yandex", UserID: validUUID, Price: 451, Months: 4, Cost: 1804},
+		},
+	}
+	mockUC.On("CalculateCost", mock.Anything, costReq).Return(cost, nil)
 
 	r := chi.NewRouter()
 	r.Get("/subscriptions/cost", handler.CalculateCost)
@@ -260,30 +572,274 @@ func TestSubscriptionHandler_CalculateCost(t *testing.T) {
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
-	var resp struct {
-		Cost int `json:"cost"`
+	var resp dto.CalculateCostResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, money.Amount(1804), resp.TotalCost)
+	assert.Len(t, resp.Subscriptions, 1)
+	assert.Equal(t, 4, resp.Subscriptions[0].Months)
+}
+
+func TestSubscriptionHandler_CalculateCost_GroupBy(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	validUUID := uuid.New()
+	costReq := &dto.CalculateCostRequest{
+		UserID:    &validUUID,
+		StartDate: "09-2025",
+		EndDate:   "12-2025",
+		GroupBy:   []string{"service_name", "month"},
+	}
+	yandex, kion := "yandex", "kion"
+	september, october := "09-2025", "10-2025"
+	cost := &dto.CalculateCostResponse{
+		TotalCost: 1000,
+		Groups: []*dto.CostGroupResponse{
+			{ServiceName: &kion, Month: &september, Subscriptions: 1, Cost: 200},
+			{ServiceName: &yandex, Month: &september, Subscriptions: 1, Cost: 400},
+			{ServiceName: &yandex, Month: &october, Subscriptions: 1, Cost: 400},
+		},
+	}
+	mockUC.On("CalculateCost", mock.Anything, costReq).Return(cost, nil)
+
+	r := chi.NewRouter()
+	r.Get("/subscriptions/cost", handler.CalculateCost)
+
+	req := httptest.NewRequest("GET", "/subscriptions/cost?user_id="+validUUID.String()+"&start_date=09-2025&end_date=12-2025&group_by=service_name,month", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp dto.CalculateCostResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, money.Amount(1000), resp.TotalCost)
+	assert.Len(t, resp.Groups, 3)
+	assert.Empty(t, resp.Subscriptions)
+}
+
+func TestSubscriptionHandler_CalculateCost_Category(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	tag := "fun"
+	costReq := &dto.CalculateCostRequest{
+		Tag:       &tag,
+		StartDate: "09-2025",
+		EndDate:   "12-2025",
+		GroupBy:   []string{"category"},
+	}
+	music, streaming := "music", "streaming"
+	cost := &dto.CalculateCostResponse{
+		TotalCost: 1000,
+		Groups: []*dto.CostGroupResponse{
+			{Category: &music, Subscriptions: 1, Cost: 400},
+			{Category: &streaming, Subscriptions: 2, Cost: 600},
+		},
 	}
+	mockUC.On("CalculateCost", mock.Anything, costReq).Return(cost, nil)
+
+	r := chi.NewRouter()
+	r.Get("/subscriptions/cost", handler.CalculateCost)
+
+	req := httptest.NewRequest("GET", "/subscriptions/cost?tag=fun&start_date=09-2025&end_date=12-2025&group_by=category", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp dto.CalculateCostResponse
 	err := json.NewDecoder(w.Body).Decode(&resp)
 	assert.NoError(t, err)
-	assert.Equal(t, 451, resp.Cost)
+	assert.Len(t, resp.Groups, 2)
+	assert.Equal(t, "music", *resp.Groups[0].Category)
 }
 
 func TestSubscriptionHandler_CalculateCost_Error(t *testing.T) {
 	mockUC := new(mockUseCase)
 	handler := newTestHandler(mockUC)
 
-	validUUID := uuid.New()
-	serviceName := "yandex"
-	startDate := time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)
-	endDate := time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)
-	mockUC.On("CalculateCost", mock.Anything, &validUUID, &serviceName, &startDate, &endDate).Return(0, custom_err.ErrInternalServer)
+	mockUC.On("CalculateCost", mock.Anything, mock.AnythingOfType("*dto.CalculateCostRequest")).Return((*dto.CalculateCostResponse)(nil), custom_err.ErrInternalServer)
 
 	r := chi.NewRouter()
 	r.Get("/subscriptions/cost", handler.CalculateCost)
 
-	req := httptest.NewRequest("GET", "/subscriptions/cost?user_id="+validUUID.String()+"&service_name="+serviceName+"&start_date=09-2025&end_date=12-2025", nil)
+	req := httptest.NewRequest("GET", "/subscriptions/cost?user_id="+uuid.New().String()+"&service_name=yandex&start_date=09-2025&end_date=12-2025", nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
+
+func TestSubscriptionHandler_CalculateCost_InvalidRequest(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	mockUC.On("CalculateCost", mock.Anything, mock.AnythingOfType("*dto.CalculateCostRequest")).Return((*dto.CalculateCostResponse)(nil), custom_err.ErrInvalidRequest)
+
+	r := chi.NewRouter()
+	r.Get("/subscriptions/cost", handler.CalculateCost)
+
+	req := httptest.NewRequest("GET", "/subscriptions/cost?start_date=12-2025&end_date=09-2025", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSubscriptionHandler_CostTimeSeries(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	validUUID := uuid.New()
+	costReq := &dto.CalculateCostRequest{
+		UserID:    &validUUID,
+		StartDate: "09-2025",
+		EndDate:   "11-2025",
+	}
+	series := &dto.CostTimeSeriesResponse{
+		TotalCost: 800,
+		Buckets: []*dto.CostBucketResponse{
+			{Month: "09-2025", Cost: 400, Subscriptions: 1},
+			{Month: "10-2025", Cost: 400, Subscriptions: 1},
+			{Month: "11-2025", Cost: 0, Subscriptions: 0},
+		},
+	}
+	mockUC.On("CostTimeSeries", mock.Anything, costReq).Return(series, nil)
+
+	r := chi.NewRouter()
+	r.Get("/subscriptions/cost/timeseries", handler.CostTimeSeries)
+
+	req := httptest.NewRequest("GET", "/subscriptions/cost/timeseries?user_id="+validUUID.String()+"&start_date=09-2025&end_date=11-2025", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp dto.CostTimeSeriesResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, money.Amount(800), resp.TotalCost)
+	assert.Len(t, resp.Buckets, 3)
+}
+
+func TestSubscriptionHandler_CostTimeSeries_InvalidUserID(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	r := chi.NewRouter()
+	r.Get("/subscriptions/cost/timeseries", handler.CostTimeSeries)
+
+	req := httptest.NewRequest("GET", "/subscriptions/cost/timeseries?user_id=abc&start_date=09-2025&end_date=11-2025", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "CostTimeSeries")
+}
+
+func TestSubscriptionHandler_CalculateCost_Currency(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	costReq := &dto.CalculateCostRequest{
+		StartDate: "09-2025",
+		EndDate:   "12-2025",
+		Currency:  "USD",
+	}
+	cost := &dto.CalculateCostResponse{TotalCost: 48, Currency: "USD"}
+	mockUC.On("CalculateCost", mock.Anything, costReq).Return(cost, nil)
+
+	r := chi.NewRouter()
+	r.Get("/subscriptions/cost", handler.CalculateCost)
+
+	req := httptest.NewRequest("GET", "/subscriptions/cost?start_date=09-2025&end_date=12-2025&currency=usd", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp dto.CalculateCostResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", resp.Currency)
+}
+
+func TestSubscriptionHandler_CalculateCost_Amortized(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	costReq := &dto.CalculateCostRequest{
+		StartDate: "09-2025",
+		EndDate:   "12-2025",
+		Mode:      "amortized",
+	}
+	cost := &dto.CalculateCostResponse{TotalCost: 400, Currency: "RUB", Mode: "amortized"}
+	mockUC.On("CalculateCost", mock.Anything, costReq).Return(cost, nil)
+
+	r := chi.NewRouter()
+	r.Get("/subscriptions/cost", handler.CalculateCost)
+
+	req := httptest.NewRequest("GET", "/subscriptions/cost?start_date=09-2025&end_date=12-2025&mode=amortized", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp dto.CalculateCostResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "amortized", resp.Mode)
+}
+
+func TestSubscriptionHandler_CalculateCost_MissingRate(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	mockUC.On("CalculateCost", mock.Anything, mock.AnythingOfType("*dto.CalculateCostRequest")).Return((*dto.CalculateCostResponse)(nil), custom_err.ErrExchangeRateNotFound)
+
+	r := chi.NewRouter()
+	r.Get("/subscriptions/cost", handler.CalculateCost)
+
+	req := httptest.NewRequest("GET", "/subscriptions/cost?start_date=09-2025&end_date=12-2025&currency=KZT", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestSubscriptionHandler_Forecast(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	forecast := &dto.ForecastResponse{TotalCost: 1200, Currency: "RUB", Mode: "charged"}
+	mockUC.On("Forecast", mock.Anything, &dto.ForecastRequest{Months: 12}).Return(forecast, nil)
+	mockUC.On("Forecast", mock.Anything, &dto.ForecastRequest{Months: 3, Currency: "USD"}).Return(forecast, nil)
+
+	r := chi.NewRouter()
+	r.Get("/subscriptions/forecast", handler.Forecast)
+
+	for _, url := range []string{"/subscriptions/forecast", "/subscriptions/forecast?months=3&currency=usd"} {
+		req := httptest.NewRequest("GET", url, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp dto.ForecastResponse
+		err := json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(t, err)
+		assert.Equal(t, money.Amount(1200), resp.TotalCost)
+	}
+	mockUC.AssertExpectations(t)
+}
+
+func TestSubscriptionHandler_Forecast_InvalidMonths(t *testing.T) {
+	mockUC := new(mockUseCase)
+	handler := newTestHandler(mockUC)
+
+	r := chi.NewRouter()
+	r.Get("/subscriptions/forecast", handler.Forecast)
+
+	req := httptest.NewRequest("GET", "/subscriptions/forecast?months=year", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
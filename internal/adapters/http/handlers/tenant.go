@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/audit"
+	"AggregationService/internal/pkg/logger"
+	"AggregationService/internal/pkg/tenant"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+const bearerPrefix = "Bearer "
+
+type ITenantUseCase interface {
+	Authenticate(ctx context.Context, apiKey string) (uuid.UUID, string, error)
+}
+
+type TenantHandler struct {
+	useCase ITenantUseCase
+}
+
+func NewTenantHandler(useCase ITenantUseCase) *TenantHandler {
+	return &TenantHandler{useCase: useCase}
+}
+
+// Authenticate resolves the tenant of a request from its API key, sent as
+// Authorization: Bearer <key>, and scopes the rest of the request to it. The
+// key is also the actor of the changes the request makes in the audit trail.
+// Requests without a valid key answer 401.
+func (h *TenantHandler) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		log := logger.FromContext(ctx)
+
+		apiKey, ok := strings.CutPrefix(r.Header.Get("Authorization"), bearerPrefix)
+		if !ok {
+			apiKey = ""
+		}
+		tenantID, actor, err := h.useCase.Authenticate(ctx, strings.TrimSpace(apiKey))
+		if err != nil {
+			log.Error("failed to authenticate request", slog.Any("err", err))
+			if errors.Is(err, custom_err.ErrUnauthorized) {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		meta := audit.FromContext(ctx)
+		meta.Actor = actor
+		ctx = audit.ContextWithMeta(tenant.ContextWithID(ctx, tenantID), meta)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
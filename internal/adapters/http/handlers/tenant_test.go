@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/audit"
+	"AggregationService/internal/pkg/tenant"
+)
+
+type mockTenantUseCase struct{ mock.Mock }
+
+func (m *mockTenantUseCase) Authenticate(ctx context.Context, apiKey string) (uuid.UUID, string, error) {
+	args := m.Called(ctx, apiKey)
+	return args.Get(0).(uuid.UUID), args.String(1), args.Error(2)
+}
+
+func TestTenantHandler_Authenticate(t *testing.T) {
+	tenantID := uuid.New()
+
+	tests := []struct {
+		name          string
+		authorization string
+		apiKey        string
+		authErr       error
+		wantStatus    int
+	}{
+		{name: "Valid key", authorization: "Bearer secret", apiKey: "secret", wantStatus: http.StatusOK},
+		{name: "Missing header", authorization: "", apiKey: "", authErr: custom_err.ErrUnauthorized, wantStatus: http.StatusUnauthorized},
+		{name: "Not a bearer token", authorization: "Basic secret", apiKey: "", authErr: custom_err.ErrUnauthorized, wantStatus: http.StatusUnauthorized},
+		{name: "Unknown key", authorization: "Bearer other", apiKey: "other", authErr: custom_err.ErrUnauthorized, wantStatus: http.StatusUnauthorized},
+		{name: "Internal error", authorization: "Bearer secret", apiKey: "secret", authErr: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCase := new(mockTenantUseCase)
+			if tt.authErr != nil {
+				useCase.On("Authenticate", mock.Anything, tt.apiKey).Return(uuid.Nil, "", tt.authErr)
+			} else {
+				useCase.On("Authenticate", mock.Anything, tt.apiKey).Return(tenantID, "api-key:0123456789ab", nil)
+			}
+
+			var scoped uuid.UUID
+			var meta audit.Meta
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				scoped, _ = tenant.FromContext(r.Context())
+				meta = audit.FromContext(r.Context())
+			})
+
+			req := httptest.NewRequest("GET", "/subscriptions", nil)
+			req = req.WithContext(audit.ContextWithMeta(req.Context(), audit.Meta{RequestID: "req-1", Actor: audit.AnonymousActor}))
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			NewTenantHandler(useCase).Authenticate(next).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, tenantID, scoped)
+				assert.Equal(t, audit.Meta{RequestID: "req-1", Actor: "api-key:0123456789ab"}, meta)
+				return
+			}
+			assert.Equal(t, uuid.Nil, scoped)
+			if tt.wantStatus == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
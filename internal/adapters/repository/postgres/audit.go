@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/domain/ports/repository"
+	"AggregationService/internal/infrastructure/database/go_postgres"
+	"AggregationService/internal/pkg/audit"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+const tableSubscriptionAudit = "subscription_audit"
+
+type auditRepository struct {
+	client *go_postgres.PostgresClient
+}
+
+func NewAuditRepository(client *go_postgres.PostgresClient) repository.IAuditRepository {
+	return &auditRepository{client: client}
+}
+
+// Record appends an entry to the audit trail. The table rejects updates and
+// deletes, so entries can't be changed once recorded.
+func (a *auditRepository) Record(ctx context.Context, entry *entity.AuditEntry) error {
+	const op = "repository.postgres.audit.Record"
+
+	err := a.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return insertAudit(ctx, tx, a.client.Builder, entry)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (a *auditRepository) GetAll(ctx context.Context, filter *entity.AuditFilter) ([]*entity.AuditEntry, error) {
+	const op = "repository.postgres.audit.GetAll"
+
+	sq := a.client.Builder.
+		Select("*").
+		From(tableSubscriptionAudit).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx)})
+	if filter.SubscriptionID != nil {
+		sq = sq.Where(squirrel.Eq{"subscription_id": *filter.SubscriptionID})
+	}
+	if filter.SubscriptionPublicID != nil {
+		sq = sq.Where(squirrel.Eq{"subscription_public_id": *filter.SubscriptionPublicID})
+	}
+	if filter.UserID != nil {
+		sq = sq.Where(squirrel.Eq{"user_id": *filter.UserID})
+	}
+	if filter.Action != nil {
+		sq = sq.Where(squirrel.Eq{"action": *filter.Action})
+	}
+	if filter.Actor != nil {
+		sq = sq.Where(squirrel.Eq{"actor": *filter.Actor})
+	}
+	if filter.RequestID != nil {
+		sq = sq.Where(squirrel.Eq{"request_id": *filter.RequestID})
+	}
+	if filter.From != nil {
+		sq = sq.Where(squirrel.GtOrEq{"created_at": *filter.From})
+	}
+	if filter.To != nil {
+		sq = sq.Where(squirrel.LtOrEq{"created_at": *filter.To})
+	}
+	query, args, err := sq.
+		OrderBy("id DESC").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	entries := make([]*entity.AuditEntry, 0)
+	err = a.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.SelectContext(ctx, &entries, query, args...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: select: %w", op, err)
+	}
+	return entries, nil
+}
+
+// recordAudit appends a change of sub to the audit trail inside tx, the
+// transaction that makes the change, so that no stored change goes without
+// its entry. The request ID and actor are those of ctx. before and after are
+// the states around the change, nil when there is none.
+func recordAudit(ctx context.Context, tx *sqlx.Tx, builder squirrel.StatementBuilderType, action string, sub *entity.Subscription, before, after any) error {
+	meta := audit.FromContext(ctx)
+	entry := &entity.AuditEntry{
+		SubscriptionID:       sub.ID,
+		SubscriptionPublicID: sub.PublicID,
+		UserID:               sub.UserID,
+		Action:               action,
+		RequestID:            meta.RequestID,
+		Actor:                meta.Actor,
+		CreatedAt:            time.Now(),
+	}
+	var err error
+	if entry.Before, err = auditSnapshot(before); err != nil {
+		return fmt.Errorf("to snapshot audit: %w", err)
+	}
+	if entry.After, err = auditSnapshot(after); err != nil {
+		return fmt.Errorf("to snapshot audit: %w", err)
+	}
+	return insertAudit(ctx, tx, builder, entry)
+}
+
+// insertAudit stores entry inside tx and sets its id.
+func insertAudit(ctx context.Context, tx *sqlx.Tx, builder squirrel.StatementBuilderType, entry *entity.AuditEntry) error {
+	query, args, err := builder.
+		Insert(tableSubscriptionAudit).
+		Columns("subscription_id", "subscription_public_id", "user_id", "action", "before", "after", "request_id", "actor", "created_at").
+		Values(
+			entry.SubscriptionID,
+			entry.SubscriptionPublicID,
+			entry.UserID,
+			entry.Action,
+			nullableJSON(entry.Before),
+			nullableJSON(entry.After),
+			entry.RequestID,
+			entry.Actor,
+			entry.CreatedAt,
+		).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("to sql: %w", err)
+	}
+	if err = tx.QueryRowxContext(ctx, query, args...).Scan(&entry.ID); err != nil {
+		return fmt.Errorf("to insert audit: %w", err)
+	}
+	return nil
+}
+
+func auditSnapshot(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if string(raw) == "null" {
+		return nil, nil
+	}
+	return raw, nil
+}
+
+// nullableJSON stores an empty snapshot as NULL.
+func nullableJSON(raw json.RawMessage) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	return []byte(raw)
+}
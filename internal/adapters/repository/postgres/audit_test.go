@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"AggregationService/internal/domain/models/entity"
+	errors_custom "AggregationService/internal/errors"
+	"AggregationService/internal/infrastructure/database/go_postgres"
+	"AggregationService/internal/pkg/audit"
+	"AggregationService/internal/pkg/money"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditRepository_RecordAndFilter(t *testing.T) {
+	client, err := go_postgres.NewTestClient()
+	assert.NoError(t, err)
+	repo := NewAuditRepository(client)
+	ctx := testContext()
+	userID := uuid.New()
+	subscriptionID := int(time.Now().UnixNano() % 1_000_000_000)
+	actor := "auditor-" + userID.String()
+
+	created := &entity.AuditEntry{
+		SubscriptionID: subscriptionID,
+		UserID:         userID,
+		Action:         entity.AuditActionCreate,
+		After:          json.RawMessage(`{"price": 299}`),
+		RequestID:      "req-1",
+		Actor:          actor,
+		CreatedAt:      time.Now(),
+	}
+	assert.NoError(t, repo.Record(ctx, created))
+	assert.NotZero(t, created.ID)
+	assert.NoError(t, repo.Record(ctx, &entity.AuditEntry{
+		SubscriptionID: subscriptionID,
+		UserID:         userID,
+		Action:         entity.AuditActionUpdate,
+		Before:         json.RawMessage(`{"price": 299}`),
+		After:          json.RawMessage(`{"price": 399}`),
+		RequestID:      "req-2",
+		Actor:          actor,
+		CreatedAt:      time.Now(),
+	}))
+
+	entries, err := repo.GetAll(ctx, &entity.AuditFilter{SubscriptionID: &subscriptionID, Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, entity.AuditActionUpdate, entries[0].Action)
+	assert.JSONEq(t, `{"price": 399}`, string(entries[0].After))
+	assert.Nil(t, entries[1].Before)
+
+	action := entity.AuditActionCreate
+	entries, err = repo.GetAll(ctx, &entity.AuditFilter{Actor: &actor, Action: &action, Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "req-1", entries[0].RequestID)
+
+	// the table is append-only
+	_, err = client.DB.ExecContext(ctx, "UPDATE subscription_audit SET actor = 'someone' WHERE id = $1", created.ID)
+	assert.Error(t, err)
+	_, err = client.DB.ExecContext(ctx, "DELETE FROM subscription_audit WHERE id = $1", created.ID)
+	assert.Error(t, err)
+}
+
+func TestSubscriptionRepository_AuditTrail(t *testing.T) {
+	client, err := go_postgres.NewTestClient()
+	assert.NoError(t, err)
+	repo := NewSubscriptionsRepository(client)
+	audits := NewAuditRepository(client)
+	ctx := audit.ContextWithMeta(testContext(), audit.Meta{RequestID: "req-audit", Actor: "api-key:0123456789ab"})
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	created, err := repo.Create(ctx, &entity.Subscription{
+		ServiceName:   "yandex",
+		Price:         money.FromMajor(299),
+		BillingPeriod: entity.BillingPeriodMonth,
+		BillingMonths: 1,
+		UserID:        uuid.New(),
+		StartDate:     start,
+	})
+	assert.NoError(t, err)
+	stale := *created
+
+	created.ServiceName = "yandex plus"
+	updated, err := repo.Update(ctx, created, &entity.SubscriptionPrice{
+		SubscriptionID: created.ID,
+		Price:          money.FromMajor(399),
+		EffectiveFrom:  start.AddDate(0, 2, 0),
+	})
+	assert.NoError(t, err)
+
+	// a change that fails leaves no entry
+	_, err = repo.Update(ctx, &stale, nil)
+	assert.ErrorIs(t, err, errors_custom.ErrVersionMismatch)
+
+	assert.NoError(t, repo.Delete(ctx, updated.ID, updated.Version))
+
+	entries, err := audits.GetAll(ctx, &entity.AuditFilter{SubscriptionID: &created.ID, Limit: 10})
+	assert.NoError(t, err)
+	actions := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		actions = append(actions, entry.Action)
+		assert.Equal(t, created.PublicID, entry.SubscriptionPublicID)
+		assert.Equal(t, created.UserID, entry.UserID)
+		assert.Equal(t, "req-audit", entry.RequestID)
+		assert.Equal(t, "api-key:0123456789ab", entry.Actor)
+	}
+	assert.Equal(t, []string{
+		entity.AuditActionDelete,
+		entity.AuditActionPrice,
+		entity.AuditActionUpdate,
+		entity.AuditActionCreate,
+	}, actions)
+	if len(entries) == 4 {
+		assert.Nil(t, entries[0].After)
+		assert.Contains(t, string(entries[2].Before), `"yandex"`)
+		assert.Contains(t, string(entries[2].After), `"yandex plus"`)
+		assert.Nil(t, entries[3].Before)
+	}
+}
@@ -0,0 +1,207 @@
+package postgres
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/domain/ports/repository"
+	errors_custom "AggregationService/internal/errors"
+	"AggregationService/internal/infrastructure/database/go_postgres"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const tableBudgets = "budgets"
+
+type budgetsRepository struct {
+	client *go_postgres.PostgresClient
+}
+
+func NewBudgetsRepository(client *go_postgres.PostgresClient) repository.IBudgetRepository {
+	return &budgetsRepository{client: client}
+}
+
+func (b *budgetsRepository) Create(ctx context.Context, budget *entity.Budget) (*entity.Budget, error) {
+	const op = "repository.postgres.budgets.Create"
+
+	sq := b.client.Builder.
+		Insert(tableBudgets).
+		Columns(
+			"user_id",
+			"service_id",
+			"service_name",
+			"category",
+			"period",
+			"amount",
+			"currency",
+			"threshold",
+			"created_at",
+			"updated_at",
+		).
+		Values(
+			budget.UserID,
+			budget.ServiceID,
+			budget.ServiceName,
+			budget.Category,
+			budget.Period,
+			budget.Amount,
+			budget.Currency,
+			budget.Threshold,
+			budget.CreatedAt,
+			budget.UpdatedAt,
+		).
+		Suffix("RETURNING id")
+
+	query, args, err := sq.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+	err = b.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.QueryRowxContext(ctx, query, args...).Scan(&budget.ID)
+	})
+	if err != nil {
+		if isPgError(err, pgForeignKeyViolation) {
+			return nil, budgetReferenceError(err)
+		}
+		return nil, fmt.Errorf("%s: to scan: %w", op, err)
+	}
+	return budget, nil
+}
+
+func (b *budgetsRepository) GetByID(ctx context.Context, id int) (*entity.Budget, error) {
+	const op = "repository.postgres.budgets.GetByID"
+
+	sq := b.client.Builder.
+		Select("*").
+		From(tableBudgets).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "id": id})
+
+	query, args, err := sq.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	var budget entity.Budget
+	err = b.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.GetContext(ctx, &budget, query, args...)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors_custom.ErrBudgetNotFound
+		}
+		return nil, fmt.Errorf("%s: query error: %w", op, err)
+	}
+	return &budget, nil
+}
+
+func (b *budgetsRepository) GetAll(ctx context.Context, userID *uuid.UUID) ([]*entity.Budget, error) {
+	const op = "repository.postgres.budgets.GetAll"
+
+	sq := b.client.Builder.
+		Select("*").
+		From(tableBudgets).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx)}).
+		OrderBy("id")
+	if userID != nil {
+		sq = sq.Where(squirrel.Eq{"user_id": *userID})
+	}
+
+	query, args, err := sq.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	budgets := make([]*entity.Budget, 0)
+	err = b.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.SelectContext(ctx, &budgets, query, args...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: select: %w", op, err)
+	}
+	return budgets, nil
+}
+
+func (b *budgetsRepository) Update(ctx context.Context, budget *entity.Budget) (*entity.Budget, error) {
+	const op = "repository.postgres.budgets.Update"
+
+	sq := b.client.Builder.
+		Update(tableBudgets).
+		Set("service_id", budget.ServiceID).
+		Set("service_name", budget.ServiceName).
+		Set("category", budget.Category).
+		Set("period", budget.Period).
+		Set("amount", budget.Amount).
+		Set("currency", budget.Currency).
+		Set("threshold", budget.Threshold).
+		Set("updated_at", budget.UpdatedAt).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "id": budget.ID})
+
+	query, args, err := sq.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	var affectedRows int64
+	err = b.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		affectedRows, _ = res.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		if isPgError(err, pgForeignKeyViolation) {
+			return nil, budgetReferenceError(err)
+		}
+		return nil, fmt.Errorf("%s: to update: %w", op, err)
+	}
+	if affectedRows == 0 {
+		return nil, errors_custom.ErrBudgetNotFound
+	}
+	return budget, nil
+}
+
+func (b *budgetsRepository) Delete(ctx context.Context, id int) error {
+	const op = "repository.postgres.budgets.Delete"
+
+	sq := b.client.Builder.
+		Delete(tableBudgets).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "id": id})
+
+	query, args, err := sq.ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	var affectedRows int64
+	err = b.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		affectedRows, _ = res.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: to delete: %w", op, err)
+	}
+	if affectedRows == 0 {
+		return errors_custom.ErrBudgetNotFound
+	}
+	return nil
+}
+
+// budgetReferenceError tells which reference of a budget a foreign key
+// violation is about: its service, gone since it was resolved, or its category.
+func budgetReferenceError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Constraint == "budgets_service_id_fkey" {
+		return errors_custom.ErrServiceNotFound
+	}
+	return errors_custom.ErrCategoryNotFound
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"AggregationService/internal/domain/models/entity"
+	errors_custom "AggregationService/internal/errors"
+	"AggregationService/internal/infrastructure/database/go_postgres"
+	"AggregationService/internal/pkg/money"
+	"AggregationService/internal/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudgetRepository_CRUD(t *testing.T) {
+	client, err := go_postgres.NewTestClient()
+	assert.NoError(t, err)
+	repo := NewBudgetsRepository(client)
+	ctx := testContext()
+	userID := uuid.New()
+	category := "streaming"
+
+	service, err := NewServicesRepository(client).Create(ctx, newService("Budget "+uuid.NewString()))
+	assert.NoError(t, err)
+
+	created, err := repo.Create(ctx, &entity.Budget{
+		UserID:      userID,
+		ServiceID:   &service.ID,
+		ServiceName: &service.Name,
+		Category:    &category,
+		Period:      entity.BudgetPeriodMonth,
+		Amount:      money.FromMajor(1000),
+		Currency:    entity.DefaultCurrency,
+		Threshold:   80,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, created.ID)
+
+	created.Amount = money.FromMajor(1500)
+	_, err = repo.Update(ctx, created)
+	assert.NoError(t, err)
+
+	found, err := repo.GetByID(ctx, created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromMajor(1500), found.Amount)
+	assert.Equal(t, service.ID, *found.ServiceID)
+	assert.Equal(t, service.Name, *found.ServiceName)
+
+	// the budget follows its service through a rename
+	service.Name += " HD"
+	service.NormalizedName = utils.NormalizeServiceName(service.Name)
+	_, err = NewServicesRepository(client).Update(ctx, service)
+	assert.NoError(t, err)
+	found, err = repo.GetByID(ctx, created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, service.Name, *found.ServiceName)
+	assert.Equal(t, category, *found.Category)
+
+	unknown := "no such category"
+	found.Category = &unknown
+	_, err = repo.Update(ctx, found)
+	assert.ErrorIs(t, err, errors_custom.ErrCategoryNotFound)
+
+	budgets, err := repo.GetAll(ctx, &userID)
+	assert.NoError(t, err)
+	assert.Len(t, budgets, 1)
+
+	assert.NoError(t, repo.Delete(ctx, created.ID))
+	_, err = repo.GetByID(ctx, created.ID)
+	assert.ErrorIs(t, err, errors_custom.ErrBudgetNotFound)
+}
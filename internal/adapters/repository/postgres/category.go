@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/domain/ports/repository"
+	errors_custom "AggregationService/internal/errors"
+	"AggregationService/internal/infrastructure/database/go_postgres"
+	"context"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+const tableCategories = "categories"
+
+type categoriesRepository struct {
+	client *go_postgres.PostgresClient
+}
+
+func NewCategoriesRepository(client *go_postgres.PostgresClient) repository.ICategoryRepository {
+	return &categoriesRepository{client: client}
+}
+
+func (c *categoriesRepository) Create(ctx context.Context, category *entity.Category) (*entity.Category, error) {
+	const op = "repository.postgres.categories.Create"
+
+	query, args, err := c.client.Builder.
+		Insert(tableCategories).
+		Columns("code", "name", "created_at").
+		Values(category.Code, category.Name, category.CreatedAt).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	err = c.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, query, args...)
+		return err
+	})
+	if err != nil {
+		if isPgError(err, pgUniqueViolation) {
+			return nil, errors_custom.ErrCategoryAlreadyExists
+		}
+		return nil, fmt.Errorf("%s: to insert: %w", op, err)
+	}
+	return category, nil
+}
+
+func (c *categoriesRepository) GetAll(ctx context.Context) ([]*entity.Category, error) {
+	const op = "repository.postgres.categories.GetAll"
+
+	query, args, err := c.client.Builder.
+		Select("*").
+		From(tableCategories).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx)}).
+		OrderBy("code").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	categories := make([]*entity.Category, 0)
+	err = c.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.SelectContext(ctx, &categories, query, args...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: select: %w", op, err)
+	}
+	return categories, nil
+}
@@ -0,0 +1,285 @@
+package postgres
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	errors_custom "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// monthSeriesJoin expands every subscription into one row per calendar month
+// it is active inside the requested window. Both ends are clipped to the
+// window and a NULL end_date is treated as open.
+// Args: window start, window end, window end.
+const monthSeriesJoin = `CROSS JOIN LATERAL generate_series(
+	date_trunc('month', GREATEST(s.start_date, ?::date)),
+	date_trunc('month', LEAST(COALESCE(s.end_date, ?::date), ?::date)),
+	interval '1 month'
+) AS m(month)`
+
+// priceJoin picks the price in force in every month of the series: the latest
+// price period that starts no later than the month.
+const priceJoin = `CROSS JOIN LATERAL (
+	SELECT sp.price FROM subscription_prices sp
+	WHERE sp.subscription_id = s.id AND sp.effective_from <= m.month
+	ORDER BY sp.effective_from DESC
+	LIMIT 1
+) AS p`
+
+// liveAmount is what a subscription costs in one month of the series: the
+// price in force that month scaled by billing_factor for the billing period
+// and cost mode.
+// Args: amortized.
+const liveAmount = `p.price
+	* billing_factor(s.billing_period, s.billing_months, s.start_date, s.end_date, m.month::date, ?)`
+
+// rollupJoin reads the months of every subscription inside the requested
+// window from subscription_monthly_rollup, which already holds the monthly
+// amounts for both cost modes.
+// Args: window start, window end.
+const rollupJoin = `JOIN subscription_monthly_rollup m
+	ON m.subscription_id = s.id AND m.month BETWEEN ?::date AND ?::date`
+
+// currencyFactor converts a monthly amount into the requested currency with
+// the rates valid for that month. exchange_rate raises no_data_found when a
+// rate is missing.
+// Args: target currency, target currency.
+const currencyFactor = `CASE WHEN s.currency = ? THEN 1
+	ELSE exchange_rate(s.currency, m.month::date) / exchange_rate(?, m.month::date) END`
+
+// pgNoDataFound is the SQLSTATE raised by exchange_rate.
+const pgNoDataFound = "P0002"
+
+type costGroupColumn struct {
+	selectExpr string
+	groupExpr  string
+}
+
+// costGroupColumns maps every supported group_by key to its SQL expressions.
+var costGroupColumns = map[string]costGroupColumn{
+	entity.CostGroupByServiceName: {selectExpr: "s.service_name", groupExpr: "s.service_name"},
+	entity.CostGroupByUserID:      {selectExpr: "s.user_id", groupExpr: "s.user_id"},
+	entity.CostGroupByMonth:       {selectExpr: "m.month::date AS month", groupExpr: "m.month"},
+	entity.CostGroupByCategory:    {selectExpr: "s.category", groupExpr: "s.category"},
+}
+
+func (s *subscriptionsRepository) CalculateCost(ctx context.Context, filter *entity.CostFilter) (*entity.CostReport, error) {
+	const op = "repository.postgres.CalculateCost"
+
+	rollup, err := s.rollupCovers(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(filter.GroupBy) > 0 {
+		groups, err := s.calculateGroupedCost(ctx, filter, rollup)
+		if err != nil {
+			if isNoExchangeRate(err) {
+				return nil, errors_custom.ErrExchangeRateNotFound
+			}
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		return &entity.CostReport{Groups: groups}, nil
+	}
+
+	sq := s.client.Builder.
+		Select(
+			"s.id AS subscription_id",
+			"s.public_id",
+			"s.service_name",
+			"s.user_id",
+			"s.price",
+			"s.currency",
+			"s.billing_period",
+			"COUNT(m.month) AS months",
+		).
+		Column(costSum(filter, rollup)).
+		From(tableSubscriptions + " s")
+	sq = withCostWindow(sq, tenantID(ctx), filter, rollup).
+		GroupBy("s.id").
+		OrderBy("s.id")
+
+	query, args, err := sq.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	costs := make([]*entity.SubscriptionCost, 0)
+	err = s.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.SelectContext(ctx, &costs, query, args...)
+	})
+	if err != nil {
+		if isNoExchangeRate(err) {
+			return nil, errors_custom.ErrExchangeRateNotFound
+		}
+		return nil, fmt.Errorf("%s: to extract costs: %w", op, err)
+	}
+	return &entity.CostReport{Subscriptions: costs}, nil
+}
+
+func (s *subscriptionsRepository) calculateGroupedCost(ctx context.Context, filter *entity.CostFilter, rollup bool) ([]*entity.CostGroup, error) {
+	columns := make([]string, 0, len(filter.GroupBy)+2)
+	groupBy := make([]string, 0, len(filter.GroupBy))
+	for _, key := range filter.GroupBy {
+		column, ok := costGroupColumns[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown group_by key %q", key)
+		}
+		columns = append(columns, column.selectExpr)
+		groupBy = append(groupBy, column.groupExpr)
+	}
+	columns = append(columns, "COUNT(DISTINCT s.id) AS subscriptions")
+
+	sq := s.client.Builder.
+		Select(columns...).
+		Column(costSum(filter, rollup)).
+		From(tableSubscriptions + " s")
+	sq = withCostWindow(sq, tenantID(ctx), filter, rollup).
+		GroupBy(groupBy...).
+		OrderBy(groupBy...)
+
+	query, args, err := sq.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("to sql: %w", err)
+	}
+
+	groups := make([]*entity.CostGroup, 0)
+	err = s.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.SelectContext(ctx, &groups, query, args...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("to extract groups: %w", err)
+	}
+	return groups, nil
+}
+
+func (s *subscriptionsRepository) CostTimeSeries(ctx context.Context, filter *entity.CostFilter) ([]*entity.CostBucket, error) {
+	const op = "repository.postgres.CostTimeSeries"
+
+	rollup, err := s.rollupCovers(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	sq := s.client.Builder.
+		Select(
+			"m.month::date AS month",
+			"COUNT(s.id) AS subscriptions",
+		).
+		Column(costSum(filter, rollup)).
+		From(tableSubscriptions + " s")
+	sq = withCostWindow(sq, tenantID(ctx), filter, rollup).
+		GroupBy("m.month").
+		OrderBy("m.month")
+
+	query, args, err := sq.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	var active []*entity.CostBucket
+	err = s.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.SelectContext(ctx, &active, query, args...)
+	})
+	if err != nil {
+		if isNoExchangeRate(err) {
+			return nil, errors_custom.ErrExchangeRateNotFound
+		}
+		return nil, fmt.Errorf("%s: to extract buckets: %w", op, err)
+	}
+
+	byMonth := make(map[string]*entity.CostBucket, len(active))
+	for _, bucket := range active {
+		byMonth[utils.TimeToMonthYear(bucket.Month)] = bucket
+	}
+
+	months := utils.MonthRange(filter.StartDate, filter.EndDate)
+	buckets := make([]*entity.CostBucket, 0, len(months))
+	for _, month := range months {
+		if bucket, ok := byMonth[utils.TimeToMonthYear(month)]; ok {
+			bucket.Month = month
+			buckets = append(buckets, bucket)
+			continue
+		}
+		buckets = append(buckets, &entity.CostBucket{Month: month})
+	}
+	return buckets, nil
+}
+
+// withCostWindow joins the months of every subscription of the tenant inside
+// the window, from the rollup or from the month series with the price of every
+// month, and applies the filters shared by every cost query. Deleted
+// subscriptions and paused months do not count.
+func withCostWindow(sq squirrel.SelectBuilder, tenantID uuid.UUID, filter *entity.CostFilter, rollup bool) squirrel.SelectBuilder {
+	sq = sq.Where(squirrel.Eq{"s.tenant_id": tenantID, "s.deleted_at": nil})
+	if rollup {
+		sq = sq.JoinClause(rollupJoin, filter.StartDate, filter.EndDate)
+	} else {
+		sq = sq.
+			JoinClause(monthSeriesJoin, filter.StartDate, filter.EndDate, filter.EndDate).
+			JoinClause(priceJoin).
+			Where(pausedMonth).
+			Where(squirrel.LtOrEq{"s.start_date": filter.EndDate}).
+			Where(squirrel.Or{
+				squirrel.Eq{"s.end_date": nil},
+				squirrel.GtOrEq{"s.end_date": filter.StartDate},
+			})
+	}
+
+	if filter.ActiveAt != nil {
+		sq = sq.
+			Where(squirrel.Expr("s.start_date < date_trunc('month', ?::date) + interval '1 month'", *filter.ActiveAt)).
+			Where(squirrel.Or{
+				squirrel.Eq{"s.end_date": nil},
+				squirrel.Expr("s.end_date >= date_trunc('month', ?::date)", *filter.ActiveAt),
+			})
+	}
+
+	// the rollup keeps its own copy of the filtered columns, indexed for these filters
+	table := "s"
+	if rollup {
+		table = "m"
+	}
+	if filter.UserID != nil {
+		sq = sq.Where(squirrel.Eq{table + ".user_id": *filter.UserID})
+	}
+	if filter.ServiceID != nil {
+		sq = sq.Where(squirrel.Eq{"s.service_id": *filter.ServiceID})
+	}
+	if filter.ServiceName != nil {
+		sq = sq.Where(squirrel.ILike{table + ".service_name": "%" + *filter.ServiceName + "%"})
+	}
+	if filter.Category != nil {
+		sq = sq.Where(squirrel.Eq{"s.category": *filter.Category})
+	}
+	if filter.Tag != nil {
+		sq = sq.Where(squirrel.Expr("EXISTS (SELECT 1 FROM subscription_tags t WHERE t.subscription_id = s.id AND t.tag = ?)", *filter.Tag))
+	}
+	return sq
+}
+
+// costSum totals the monthly amounts converted into the filter currency,
+// rounded to whole minor units.
+func costSum(filter *entity.CostFilter, rollup bool) squirrel.Sqlizer {
+	if rollup {
+		amount := "m.charged"
+		if filter.Mode == entity.CostModeAmortized {
+			amount = "m.amortized"
+		}
+		return squirrel.Expr("ROUND(SUM("+amount+" * "+currencyFactor+"))::bigint AS cost", filter.Currency, filter.Currency)
+	}
+
+	amortized := filter.Mode == entity.CostModeAmortized
+	return squirrel.Expr("ROUND(SUM("+liveAmount+" * "+currencyFactor+"))::bigint AS cost", amortized, filter.Currency, filter.Currency)
+}
+
+func isNoExchangeRate(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pgNoDataFound
+}
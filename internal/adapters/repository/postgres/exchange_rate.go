@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/domain/ports/repository"
+	errors_custom "AggregationService/internal/errors"
+	"AggregationService/internal/infrastructure/database/go_postgres"
+	"context"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+const tableExchangeRates = "exchange_rates"
+
+type exchangeRatesRepository struct {
+	client *go_postgres.PostgresClient
+}
+
+func NewExchangeRatesRepository(client *go_postgres.PostgresClient) repository.IExchangeRateRepository {
+	return &exchangeRatesRepository{client: client}
+}
+
+// Upsert stores the rate for its currency and month, replacing the rate
+// already set for the same month.
+func (e *exchangeRatesRepository) Upsert(ctx context.Context, rate *entity.ExchangeRate) (*entity.ExchangeRate, error) {
+	const op = "repository.postgres.exchangeRates.Upsert"
+
+	sq := e.client.Builder.
+		Insert(tableExchangeRates).
+		Columns(
+			"currency",
+			"rate",
+			"valid_from",
+			"created_at",
+			"updated_at",
+		).
+		Values(
+			rate.Currency,
+			rate.Rate,
+			rate.ValidFrom,
+			rate.CreatedAt,
+			rate.UpdatedAt,
+		).
+		Suffix(`ON CONFLICT (tenant_id, currency, valid_from) DO UPDATE
+			SET rate = EXCLUDED.rate, updated_at = EXCLUDED.updated_at
+			RETURNING id, created_at, updated_at`)
+
+	query, args, err := sq.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	err = e.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.QueryRowxContext(ctx, query, args...).Scan(&rate.ID, &rate.CreatedAt, &rate.UpdatedAt)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: to scan: %w", op, err)
+	}
+	return rate, nil
+}
+
+func (e *exchangeRatesRepository) GetAll(ctx context.Context, currency *string) ([]*entity.ExchangeRate, error) {
+	const op = "repository.postgres.exchangeRates.GetAll"
+
+	sq := e.client.Builder.
+		Select("*").
+		From(tableExchangeRates).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx)}).
+		OrderBy("currency", "valid_from")
+	if currency != nil {
+		sq = sq.Where(squirrel.Eq{"currency": *currency})
+	}
+
+	query, args, err := sq.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	rates := make([]*entity.ExchangeRate, 0)
+	err = e.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.SelectContext(ctx, &rates, query, args...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: select: %w", op, err)
+	}
+	return rates, nil
+}
+
+func (e *exchangeRatesRepository) Delete(ctx context.Context, id int) error {
+	const op = "repository.postgres.exchangeRates.Delete"
+
+	sq := e.client.Builder.
+		Delete(tableExchangeRates).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "id": id})
+
+	query, args, err := sq.ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	var affectedRows int64
+	err = e.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		affectedRows, _ = res.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: to delete: %w", op, err)
+	}
+	if affectedRows == 0 {
+		return errors_custom.ErrExchangeRateNotFound
+	}
+	return nil
+}
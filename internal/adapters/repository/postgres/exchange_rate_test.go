@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/infrastructure/database/go_postgres"
+	"AggregationService/internal/pkg/money"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExchangeRateRepository_UpsertAndDelete(t *testing.T) {
+	client, err := go_postgres.NewTestClient()
+	assert.NoError(t, err)
+	repo := NewExchangeRatesRepository(client)
+	ctx := testContext()
+	validFrom := time.Date(2031, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	created, err := repo.Upsert(ctx, &entity.ExchangeRate{Currency: "USD", Rate: 90, ValidFrom: validFrom, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	assert.NoError(t, err)
+	assert.NotZero(t, created.ID)
+
+	replaced, err := repo.Upsert(ctx, &entity.ExchangeRate{Currency: "USD", Rate: 95, ValidFrom: validFrom, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	assert.NoError(t, err)
+	assert.Equal(t, created.ID, replaced.ID)
+
+	assert.NoError(t, repo.Delete(ctx, created.ID))
+	assert.Error(t, repo.Delete(ctx, created.ID))
+}
+
+func TestSubscriptionRepository_CalculateCost_Currency(t *testing.T) {
+	client, err := go_postgres.NewTestClient()
+	assert.NoError(t, err)
+	subs := NewSubscriptionsRepository(client)
+	rates := NewExchangeRatesRepository(client)
+	ctx := testContext()
+	userID := uuid.New()
+	month := time.Date(2032, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err = rates.Upsert(ctx, &entity.ExchangeRate{Currency: "USD", Rate: 100, ValidFrom: month, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	assert.NoError(t, err)
+	subs.Create(ctx, &entity.Subscription{ServiceName: "youtube", Price: money.FromMajor(10), Currency: "USD", BillingPeriod: entity.BillingPeriodMonth, BillingMonths: 1, UserID: userID, StartDate: month, EndDate: &month})
+
+	report, err := subs.CalculateCost(ctx, &entity.CostFilter{UserID: &userID, StartDate: month, EndDate: month, Currency: "RUB"})
+	assert.NoError(t, err)
+	assert.Len(t, report.Subscriptions, 1)
+	assert.Equal(t, money.FromMajor(1000), report.Subscriptions[0].Cost)
+
+	_, err = subs.CalculateCost(ctx, &entity.CostFilter{UserID: &userID, StartDate: month, EndDate: month, Currency: "KZT"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/domain/ports/repository"
+	"AggregationService/internal/infrastructure/database/go_postgres"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+const tableIdempotencyKeys = "idempotency_keys"
+
+type idempotencyRepository struct {
+	client *go_postgres.PostgresClient
+}
+
+func NewIdempotencyRepository(client *go_postgres.PostgresClient) repository.IIdempotencyRepository {
+	return &idempotencyRepository{client: client}
+}
+
+// Reserve stores record as a running request unless its key is already taken
+// in its scope, in which case the stored record is returned instead. A record
+// that has expired is replaced, and so is a running one whose lease has passed:
+// its request is gone without completing or releasing the key.
+func (i *idempotencyRepository) Reserve(ctx context.Context, record *entity.IdempotencyRecord) (*entity.IdempotencyRecord, error) {
+	const op = "repository.postgres.idempotency.Reserve"
+
+	query, args, err := i.client.Builder.
+		Insert(tableIdempotencyKeys).
+		Columns("key", "scope", "request_hash", "created_at", "expires_at", "locked_until").
+		Values(record.Key, record.Scope, record.RequestHash, record.CreatedAt, record.ExpiresAt, record.LockedUntil).
+		Suffix(`ON CONFLICT (tenant_id, scope, key) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash,
+			status_code = NULL,
+			header = NULL,
+			body = NULL,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at,
+			locked_until = EXCLUDED.locked_until
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+			OR (idempotency_keys.status_code IS NULL AND idempotency_keys.locked_until <= EXCLUDED.created_at)
+		RETURNING key`).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+	getQuery, getArgs, err := i.client.Builder.
+		Select("*").
+		From(tableIdempotencyKeys).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "scope": record.Scope, "key": record.Key}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	// the stored record may be released between the insert and the read, then
+	// the key is free again
+	for attempt := 0; ; attempt++ {
+		var key string
+		err = i.client.InTx(ctx, func(tx *sqlx.Tx) error {
+			return tx.QueryRowxContext(ctx, query, args...).Scan(&key)
+		})
+		if err == nil {
+			return nil, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: to insert: %w", op, err)
+		}
+
+		var stored entity.IdempotencyRecord
+		err = i.client.InTx(ctx, func(tx *sqlx.Tx) error {
+			return tx.GetContext(ctx, &stored, getQuery, getArgs...)
+		})
+		if err == nil {
+			return &stored, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) || attempt > 0 {
+			return nil, fmt.Errorf("%s: to get: %w", op, err)
+		}
+	}
+}
+
+// Complete stores the response of the request that reserved the key.
+func (i *idempotencyRepository) Complete(ctx context.Context, record *entity.IdempotencyRecord) error {
+	const op = "repository.postgres.idempotency.Complete"
+
+	query, args, err := i.client.Builder.
+		Update(tableIdempotencyKeys).
+		Set("status_code", record.StatusCode).
+		Set("header", record.Header).
+		Set("body", record.Body).
+		Set("locked_until", nil).
+		Where(squirrel.Eq{
+			"tenant_id":    tenantID(ctx),
+			"scope":        record.Scope,
+			"key":          record.Key,
+			"request_hash": record.RequestHash,
+		}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	err = i.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, query, args...)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("%s: to update: %w", op, err)
+	}
+	return nil
+}
+
+// Release frees a key whose request failed so that it can be retried.
+func (i *idempotencyRepository) Release(ctx context.Context, scope string, key string) error {
+	const op = "repository.postgres.idempotency.Release"
+
+	query, args, err := i.client.Builder.
+		Delete(tableIdempotencyKeys).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "scope": scope, "key": key}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	err = i.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, query, args...)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("%s: to delete: %w", op, err)
+	}
+	return nil
+}
+
+// PurgeExpired removes the keys expired by now, returning how many were
+// removed.
+func (i *idempotencyRepository) PurgeExpired(ctx context.Context, now time.Time) (int64, error) {
+	const op = "repository.postgres.idempotency.PurgeExpired"
+
+	query, args, err := i.client.Builder.
+		Delete(tableIdempotencyKeys).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx)}).
+		Where(squirrel.LtOrEq{"expires_at": now}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	var purged int64
+	err = i.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		purged, _ = res.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: to purge: %w", op, err)
+	}
+	return purged, nil
+}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/infrastructure/database/go_postgres"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyRepository_ReserveAndComplete(t *testing.T) {
+	client, err := go_postgres.NewTestClient()
+	assert.NoError(t, err)
+	repo := NewIdempotencyRepository(client)
+	ctx := testContext()
+	now := time.Now()
+	lockedUntil := now.Add(time.Minute)
+
+	record := &entity.IdempotencyRecord{
+		Key:         uuid.NewString(),
+		Scope:       "POST /subscriptions/",
+		RequestHash: "hash",
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(time.Hour),
+		LockedUntil: &lockedUntil,
+	}
+	stored, err := repo.Reserve(ctx, record)
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+
+	stored, err = repo.Reserve(ctx, record)
+	assert.NoError(t, err)
+	assert.False(t, stored.Completed())
+
+	status := http.StatusCreated
+	record.StatusCode = &status
+	record.Header = entity.ResponseHeader{"Content-Type": "application/json"}
+	record.Body = []byte(`{"id": 1}`)
+	assert.NoError(t, repo.Complete(ctx, record))
+
+	stored, err = repo.Reserve(ctx, record)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, *stored.StatusCode)
+	assert.Equal(t, "application/json", stored.Header["Content-Type"])
+	assert.Equal(t, record.Body, stored.Body)
+
+	assert.NoError(t, repo.Release(ctx, record.Scope, record.Key))
+	stored, err = repo.Reserve(ctx, record)
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+
+	purged, err := repo.PurgeExpired(ctx, now.Add(2*time.Hour))
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, purged, int64(1))
+}
+
+func TestIdempotencyRepository_TakeOverLapsedLease(t *testing.T) {
+	client, err := go_postgres.NewTestClient()
+	assert.NoError(t, err)
+	repo := NewIdempotencyRepository(client)
+	ctx := testContext()
+	now := time.Now()
+	lockedUntil := now.Add(time.Minute)
+
+	record := &entity.IdempotencyRecord{
+		Key:         uuid.NewString(),
+		Scope:       "POST /subscriptions/",
+		RequestHash: "hash",
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(time.Hour),
+		LockedUntil: &lockedUntil,
+	}
+	stored, err := repo.Reserve(ctx, record)
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+
+	// the first request died without completing the key, the retry after its
+	// lease takes the key over
+	retry := *record
+	retry.CreatedAt = now.Add(2 * time.Minute)
+	retry.ExpiresAt = retry.CreatedAt.Add(time.Hour)
+	retryLockedUntil := retry.CreatedAt.Add(time.Minute)
+	retry.LockedUntil = &retryLockedUntil
+
+	stored, err = repo.Reserve(ctx, &retry)
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+
+	// within the lease of the retry the key is still in use
+	stored, err = repo.Reserve(ctx, &retry)
+	assert.NoError(t, err)
+	assert.False(t, stored.Completed())
+
+	// a completed key is replayed after the lease
+	status := http.StatusCreated
+	retry.StatusCode = &status
+	assert.NoError(t, repo.Complete(ctx, &retry))
+	late := retry
+	late.CreatedAt = retry.CreatedAt.Add(10 * time.Minute)
+	stored, err = repo.Reserve(ctx, &late)
+	assert.NoError(t, err)
+	assert.True(t, stored.Completed())
+	assert.Nil(t, stored.LockedUntil)
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+const tableRollupState = "subscription_rollup_state"
+
+// RebuildRollup recomputes subscription_monthly_rollup for every subscription
+// of the tenant with open-ended subscriptions rolled up until the month of
+// horizon, and returns the number of rows written.
+func (s *subscriptionsRepository) RebuildRollup(ctx context.Context, horizon time.Time) (int64, error) {
+	const op = "repository.postgres.RebuildRollup"
+	var rows int64
+	err := s.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.GetContext(ctx, &rows, "SELECT rebuild_subscription_rollup($1)", horizon)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: to rebuild: %w", op, err)
+	}
+	return rows, nil
+}
+
+// refreshRollup recomputes the rollup rows of one subscription inside the
+// transaction that changed it.
+func refreshRollup(ctx context.Context, tx *sqlx.Tx, subscriptionID int) error {
+	if _, err := tx.ExecContext(ctx, "SELECT refresh_subscription_rollup($1)", subscriptionID); err != nil {
+		return fmt.Errorf("to refresh rollup: %w", err)
+	}
+	return nil
+}
+
+// rollupCovers reports whether subscription_monthly_rollup holds every month
+// of the filter window. Windows past the rollup horizon are computed live.
+func (s *subscriptionsRepository) rollupCovers(ctx context.Context, filter *entity.CostFilter) (bool, error) {
+	query, args, err := s.client.Builder.
+		Select().
+		Column(squirrel.Expr("horizon >= date_trunc('month', ?::date)", filter.EndDate)).
+		From(tableRollupState).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx)}).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("to sql: %w", err)
+	}
+
+	var covers bool
+	err = s.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.GetContext(ctx, &covers, query, args...)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("rollup horizon: %w", err)
+	}
+	return covers, nil
+}
@@ -0,0 +1,356 @@
+package postgres
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/domain/ports/repository"
+	errors_custom "AggregationService/internal/errors"
+	"AggregationService/internal/infrastructure/database/go_postgres"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"time"
+)
+
+const tableServices = "services"
+
+// pgUniqueViolation is the SQLSTATE raised when a service name is taken.
+const pgUniqueViolation = "23505"
+
+type servicesRepository struct {
+	client *go_postgres.PostgresClient
+}
+
+func NewServicesRepository(client *go_postgres.PostgresClient) repository.IServiceRepository {
+	return &servicesRepository{client: client}
+}
+
+func (r *servicesRepository) Create(ctx context.Context, service *entity.Service) (*entity.Service, error) {
+	const op = "repository.postgres.services.Create"
+
+	sq := r.client.Builder.
+		Insert(tableServices).
+		Columns("name", "normalized_name", "created_at", "updated_at").
+		Values(
+			service.Name,
+			service.NormalizedName,
+			service.CreatedAt,
+			service.UpdatedAt,
+		).
+		Suffix("RETURNING id")
+
+	query, args, err := sq.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+	err = r.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.QueryRowxContext(ctx, query, args...).Scan(&service.ID)
+	})
+	if err != nil {
+		if isPgError(err, pgUniqueViolation) {
+			return nil, errors_custom.ErrServiceAlreadyExists
+		}
+		return nil, fmt.Errorf("%s: to scan: %w", op, err)
+	}
+	return service, nil
+}
+
+func (r *servicesRepository) GetByID(ctx context.Context, id int) (*entity.Service, error) {
+	const op = "repository.postgres.services.GetByID"
+
+	var service *entity.Service
+	err := r.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		service, err = getService(ctx, tx, r.client.Builder, id)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, errors_custom.ErrServiceNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return service, nil
+}
+
+// GetAll lists the services, those whose normalized name contains key when it
+// is set.
+func (r *servicesRepository) GetAll(ctx context.Context, key *string) ([]*entity.Service, error) {
+	const op = "repository.postgres.services.GetAll"
+
+	sq := r.client.Builder.
+		Select("*").
+		From(tableServices).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx)}).
+		OrderBy("name")
+	if key != nil {
+		sq = sq.Where(squirrel.Like{"normalized_name": "%" + *key + "%"})
+	}
+
+	query, args, err := sq.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	services := make([]*entity.Service, 0)
+	err = r.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.SelectContext(ctx, &services, query, args...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: select: %w", op, err)
+	}
+	return services, nil
+}
+
+// Update renames a service together with the copies of its name kept on its
+// subscriptions and budgets and in the cost rollup. Each renamed subscription is changed
+// like by an update, see moveSubscriptions.
+func (r *servicesRepository) Update(ctx context.Context, service *entity.Service) (*entity.Service, error) {
+	const op = "repository.postgres.services.Update"
+
+	query, args, err := r.client.Builder.
+		Update(tableServices).
+		Set("name", service.Name).
+		Set("normalized_name", service.NormalizedName).
+		Set("updated_at", service.UpdatedAt).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "id": service.ID}).
+		Suffix("RETURNING created_at").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	rollupQuery, rollupArgs, err := r.client.Builder.
+		Update("subscription_monthly_rollup").
+		Set("service_name", service.Name).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx)}).
+		Where(squirrel.Expr("subscription_id IN (SELECT id FROM subscriptions WHERE service_id = ?)", service.ID)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	budgetsQuery, budgetsArgs, err := r.client.Builder.
+		Update(tableBudgets).
+		Set("service_name", service.Name).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "service_id": service.ID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	tx, err := r.client.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err = tx.QueryRowxContext(ctx, query, args...).Scan(&service.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors_custom.ErrServiceNotFound
+		}
+		if isPgError(err, pgUniqueViolation) {
+			return nil, errors_custom.ErrServiceAlreadyExists
+		}
+		return nil, fmt.Errorf("%s: to scan: %w", op, err)
+	}
+	if err = moveSubscriptions(ctx, tx, r.client.Builder, service.ID, service); err != nil {
+		return nil, fmt.Errorf("%s: to rename subscriptions: %w", op, err)
+	}
+	if _, err = tx.ExecContext(ctx, rollupQuery, rollupArgs...); err != nil {
+		return nil, fmt.Errorf("%s: to rename rollup: %w", op, err)
+	}
+	if _, err = tx.ExecContext(ctx, budgetsQuery, budgetsArgs...); err != nil {
+		return nil, fmt.Errorf("%s: to rename budgets: %w", op, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return service, nil
+}
+
+// Delete removes a service no subscription refers to.
+func (r *servicesRepository) Delete(ctx context.Context, id int) error {
+	const op = "repository.postgres.services.Delete"
+
+	query, args, err := r.client.Builder.
+		Delete(tableServices).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	var affectedRows int64
+	err = r.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		affectedRows, _ = res.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		if isPgError(err, pgForeignKeyViolation) {
+			return errors_custom.ErrServiceInUse
+		}
+		return fmt.Errorf("%s: to delete: %w", op, err)
+	}
+	if affectedRows == 0 {
+		return errors_custom.ErrServiceNotFound
+	}
+	return nil
+}
+
+func getService(ctx context.Context, db sqlx.QueryerContext, builder squirrel.StatementBuilderType, id int) (*entity.Service, error) {
+	query, args, err := builder.
+		Select("*").
+		From(tableServices).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("to sql: %w", err)
+	}
+
+	var service entity.Service
+	if err = sqlx.GetContext(ctx, db, &service, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors_custom.ErrServiceNotFound
+		}
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	return &service, nil
+}
+
+// moveSubscriptions points the subscriptions of the service with fromID at
+// service inside tx, the same service when it is renamed. Like any change of
+// a subscription, it bumps their versions and records an update of each in
+// the audit trail. Subscriptions that already have the service and its name
+// are left as they are.
+func moveSubscriptions(ctx context.Context, tx *sqlx.Tx, builder squirrel.StatementBuilderType, fromID int, service *entity.Service) error {
+	moved := squirrel.And{
+		squirrel.Eq{"tenant_id": tenantID(ctx), "service_id": fromID},
+		squirrel.Or{
+			squirrel.NotEq{"service_id": service.ID},
+			squirrel.NotEq{"service_name": service.Name},
+		},
+	}
+	query, args, err := builder.
+		Select("*").
+		From(tableSubscriptions).
+		Where(moved).
+		OrderBy("id").
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("to sql: %w", err)
+	}
+	var before []*entity.Subscription
+	if err = tx.SelectContext(ctx, &before, query, args...); err != nil {
+		return fmt.Errorf("to lock subscriptions: %w", err)
+	}
+	if len(before) == 0 {
+		return nil
+	}
+	if err = loadTags(ctx, tx, builder, before...); err != nil {
+		return err
+	}
+	if err = loadPauses(ctx, tx, builder, before...); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	query, args, err = builder.
+		Update(tableSubscriptions).
+		Set("service_id", service.ID).
+		Set("service_name", service.Name).
+		Set("updated_at", now).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(moved).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("to sql: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("to update subscriptions: %w", err)
+	}
+
+	for _, sub := range before {
+		after := *sub
+		after.ServiceID = service.ID
+		after.ServiceName = service.Name
+		after.UpdatedAt = now
+		after.Version++
+		if err = recordAudit(ctx, tx, builder, entity.AuditActionUpdate, &after, sub, &after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveService points a subscription at its catalog service inside tx: the
+// service with ServiceID when it is set, otherwise the service named
+// ServiceName or having it as an alias, like servicesRepository.Resolve
+// finds. An unknown name is added to the catalog. The subscription takes the
+// catalog spelling of the name. Resolving it in the transaction of the change
+// keeps a failed change from leaving a new service behind.
+func resolveService(ctx context.Context, tx *sqlx.Tx, builder squirrel.StatementBuilderType, subscription *entity.Subscription) error {
+	if subscription.ServiceID != 0 {
+		service, err := getService(ctx, tx, builder, subscription.ServiceID)
+		if err != nil {
+			return err
+		}
+		subscription.ServiceName = service.Name
+		return nil
+	}
+
+	query, args, err := builder.
+		Select("sv.id", "sv.name").
+		From(tableServices+" sv").
+		LeftJoin(tableServiceAliases+" a ON a.service_id = sv.id AND a.normalized_alias = normalize_service_name(?)",
+			subscription.ServiceName).
+		Where(squirrel.Eq{"sv.tenant_id": tenantID(ctx)}).
+		Where(squirrel.Or{
+			squirrel.Expr("sv.normalized_name = normalize_service_name(?)", subscription.ServiceName),
+			squirrel.NotEq{"a.id": nil},
+		}).
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("to sql: %w", err)
+	}
+	err = tx.QueryRowxContext(ctx, query, args...).Scan(&subscription.ServiceID, &subscription.ServiceName)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("to find service: %w", err)
+	}
+
+	// the no-op update makes RETURNING yield the existing row on conflict
+	query, args, err = builder.
+		Insert(tableServices).
+		Columns("name", "normalized_name").
+		Values(
+			squirrel.Expr("regexp_replace(btrim(?), '\\s+', ' ', 'g')", subscription.ServiceName),
+			squirrel.Expr("normalize_service_name(?)", subscription.ServiceName),
+		).
+		Suffix(`ON CONFLICT (tenant_id, normalized_name) DO UPDATE SET normalized_name = EXCLUDED.normalized_name
+			RETURNING id, name`).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("to sql: %w", err)
+	}
+	if err = tx.QueryRowxContext(ctx, query, args...).Scan(&subscription.ServiceID, &subscription.ServiceName); err != nil {
+		return fmt.Errorf("to resolve service: %w", err)
+	}
+	return nil
+}
+
+func isPgError(err error, code string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pq.ErrorCode(code)
+}
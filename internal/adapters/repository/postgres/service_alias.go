@@ -0,0 +1,260 @@
+package postgres
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	errors_custom "AggregationService/internal/errors"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+const tableServiceAliases = "service_aliases"
+
+// Resolve returns the service whose normalized name or one of whose aliases
+// equals key.
+func (r *servicesRepository) Resolve(ctx context.Context, key string) (*entity.Service, error) {
+	const op = "repository.postgres.services.Resolve"
+
+	query, args, err := r.client.Builder.
+		Select("sv.*").
+		From(tableServices+" sv").
+		LeftJoin(tableServiceAliases+" a ON a.service_id = sv.id AND a.normalized_alias = ?", key).
+		Where(squirrel.Eq{"sv.tenant_id": tenantID(ctx)}).
+		Where(squirrel.Or{
+			squirrel.Eq{"sv.normalized_name": key},
+			squirrel.NotEq{"a.id": nil},
+		}).
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	var service entity.Service
+	err = r.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.GetContext(ctx, &service, query, args...)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors_custom.ErrServiceNotFound
+		}
+		return nil, fmt.Errorf("%s: query error: %w", op, err)
+	}
+	return &service, nil
+}
+
+// AddAlias stores an alias of a service. An alias can not take the key of a
+// service or of another alias.
+func (r *servicesRepository) AddAlias(ctx context.Context, alias *entity.ServiceAlias) (*entity.ServiceAlias, error) {
+	const op = "repository.postgres.services.AddAlias"
+
+	free := r.client.Builder.
+		Select().
+		Column("?::int", alias.ServiceID).
+		Column("?", alias.Alias).
+		Column("?", alias.NormalizedAlias).
+		Column("?::timestamp", alias.CreatedAt).
+		Where(squirrel.Expr("NOT EXISTS (SELECT 1 FROM services WHERE tenant_id = ? AND normalized_name = ?)",
+			tenantID(ctx), alias.NormalizedAlias))
+
+	query, args, err := r.client.Builder.
+		Insert(tableServiceAliases).
+		Columns("service_id", "alias", "normalized_alias", "created_at").
+		Select(free).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	err = r.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.QueryRowxContext(ctx, query, args...).Scan(&alias.ID)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows), isPgError(err, pgUniqueViolation):
+			return nil, errors_custom.ErrServiceAlreadyExists
+		case isPgError(err, pgForeignKeyViolation):
+			return nil, errors_custom.ErrServiceNotFound
+		}
+		return nil, fmt.Errorf("%s: to scan: %w", op, err)
+	}
+	return alias, nil
+}
+
+func (r *servicesRepository) GetAliases(ctx context.Context, serviceID int) ([]*entity.ServiceAlias, error) {
+	const op = "repository.postgres.services.GetAliases"
+
+	query, args, err := r.client.Builder.
+		Select("*").
+		From(tableServiceAliases).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "service_id": serviceID}).
+		OrderBy("alias").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	aliases := make([]*entity.ServiceAlias, 0)
+	err = r.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.SelectContext(ctx, &aliases, query, args...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: select: %w", op, err)
+	}
+	return aliases, nil
+}
+
+func (r *servicesRepository) DeleteAlias(ctx context.Context, serviceID, aliasID int) error {
+	const op = "repository.postgres.services.DeleteAlias"
+
+	query, args, err := r.client.Builder.
+		Delete(tableServiceAliases).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "id": aliasID, "service_id": serviceID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	var affectedRows int64
+	err = r.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		affectedRows, _ = res.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: to delete: %w", op, err)
+	}
+	if affectedRows == 0 {
+		return errors_custom.ErrServiceAliasNotFound
+	}
+	return nil
+}
+
+// SuggestDuplicates returns the pairs of services whose normalized names have
+// a trigram similarity of at least minSimilarity, most similar first.
+func (r *servicesRepository) SuggestDuplicates(ctx context.Context, minSimilarity float64, limit int) ([]*entity.ServiceDuplicate, error) {
+	const op = "repository.postgres.services.SuggestDuplicates"
+
+	query, args, err := r.client.Builder.
+		Select(
+			"a.id AS service_id",
+			"a.name AS service_name",
+			"b.id AS duplicate_id",
+			"b.name AS duplicate_name",
+		).
+		Column("similarity(a.normalized_name, b.normalized_name) AS similarity").
+		From(tableServices+" a").
+		Join(tableServices+" b ON b.tenant_id = a.tenant_id AND a.id < b.id").
+		Where(squirrel.Eq{"a.tenant_id": tenantID(ctx)}).
+		Where(squirrel.Expr("similarity(a.normalized_name, b.normalized_name) >= ?", minSimilarity)).
+		OrderBy("similarity DESC", "a.id", "b.id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	duplicates := make([]*entity.ServiceDuplicate, 0)
+	err = r.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.SelectContext(ctx, &duplicates, query, args...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: select: %w", op, err)
+	}
+	return duplicates, nil
+}
+
+// Merge moves the subscriptions, budgets and aliases of the source service to
+// the target service, deletes the source and keeps its name as an alias of the
+// target. Each moved subscription is changed like by an update, see
+// moveSubscriptions.
+func (r *servicesRepository) Merge(ctx context.Context, targetID, sourceID int) (*entity.Service, error) {
+	const op = "repository.postgres.services.Merge"
+
+	lockQuery, lockArgs, err := r.client.Builder.
+		Select("*").
+		From(tableServices).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "id": []int{targetID, sourceID}}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	tx, err := r.client.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var locked []*entity.Service
+	if err = tx.SelectContext(ctx, &locked, lockQuery, lockArgs...); err != nil {
+		return nil, fmt.Errorf("%s: to lock services: %w", op, err)
+	}
+	var target, source *entity.Service
+	for _, service := range locked {
+		switch service.ID {
+		case targetID:
+			target = service
+		case sourceID:
+			source = service
+		}
+	}
+	if target == nil || source == nil {
+		return nil, errors_custom.ErrServiceNotFound
+	}
+
+	if err = moveSubscriptions(ctx, tx, r.client.Builder, source.ID, target); err != nil {
+		// a user subscribed to both services in the same months
+		if isPgError(err, pgExclusionViolation) {
+			return nil, errors_custom.ErrSubscriptionAlreadyFound
+		}
+		return nil, fmt.Errorf("%s: to move subscriptions: %w", op, err)
+	}
+
+	statements := []squirrel.Sqlizer{
+		r.client.Builder.
+			Update("subscription_monthly_rollup").
+			Set("service_name", target.Name).
+			Where(squirrel.Eq{"tenant_id": tenantID(ctx)}).
+			Where(squirrel.Expr("subscription_id IN (SELECT id FROM subscriptions WHERE service_id = ?)", target.ID)),
+		r.client.Builder.
+			Update(tableBudgets).
+			Set("service_id", target.ID).
+			Set("service_name", target.Name).
+			Where(squirrel.Eq{"tenant_id": tenantID(ctx), "service_id": source.ID}),
+		r.client.Builder.
+			Update(tableServiceAliases).
+			Set("service_id", target.ID).
+			Where(squirrel.Eq{"tenant_id": tenantID(ctx), "service_id": source.ID}),
+		r.client.Builder.
+			Delete(tableServices).
+			Where(squirrel.Eq{"tenant_id": tenantID(ctx), "id": source.ID}),
+		r.client.Builder.
+			Insert(tableServiceAliases).
+			Columns("service_id", "alias", "normalized_alias").
+			Values(target.ID, source.Name, source.NormalizedName).
+			Suffix("ON CONFLICT (tenant_id, normalized_alias) DO NOTHING"),
+	}
+	for _, statement := range statements {
+		query, args, err := statement.ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("%s: to sql: %w", op, err)
+		}
+		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+			return nil, fmt.Errorf("%s: to merge: %w", op, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return target, nil
+}
@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"AggregationService/internal/domain/models/entity"
+	errors_custom "AggregationService/internal/errors"
+	"AggregationService/internal/infrastructure/database/go_postgres"
+	"AggregationService/internal/pkg/money"
+	"AggregationService/internal/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newService(name string) *entity.Service {
+	return &entity.Service{
+		Name:           utils.TidyServiceName(name),
+		NormalizedName: utils.NormalizeServiceName(name),
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+}
+
+func TestServiceRepository_CRUD(t *testing.T) {
+	client, err := go_postgres.NewTestClient()
+	assert.NoError(t, err)
+	repo := NewServicesRepository(client)
+	ctx := testContext()
+	name := "Catalog " + uuid.NewString()
+
+	created, err := repo.Create(ctx, newService(name))
+	assert.NoError(t, err)
+	assert.NotZero(t, created.ID)
+
+	_, err = repo.Create(ctx, newService("  "+name+" "))
+	assert.ErrorIs(t, err, errors_custom.ErrServiceAlreadyExists)
+
+	created.Name = name + " HD"
+	created.NormalizedName = utils.NormalizeServiceName(created.Name)
+	created.UpdatedAt = time.Now()
+	_, err = repo.Update(ctx, created)
+	assert.NoError(t, err)
+
+	found, err := repo.GetByID(ctx, created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, name+" HD", found.Name)
+
+	assert.NoError(t, repo.Delete(ctx, created.ID))
+	_, err = repo.GetByID(ctx, created.ID)
+	assert.ErrorIs(t, err, errors_custom.ErrServiceNotFound)
+}
+
+func TestServiceRepository_ResolveOnSubscriptionCreate(t *testing.T) {
+	client, err := go_postgres.NewTestClient()
+	assert.NoError(t, err)
+	services := NewServicesRepository(client)
+	subs := NewSubscriptionsRepository(client)
+	ctx := testContext()
+	name := "Yandex Plus " + uuid.NewString()
+
+	newSub := func(serviceName string) *entity.Subscription {
+		return &entity.Subscription{
+			ServiceName:   serviceName,
+			Price:         money.FromMajor(299),
+			Currency:      entity.DefaultCurrency,
+			BillingPeriod: entity.BillingPeriodMonth,
+			BillingMonths: 1,
+			UserID:        uuid.New(),
+			StartDate:     time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC),
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
+		}
+	}
+
+	first, err := subs.Create(ctx, newSub(name))
+	assert.NoError(t, err)
+	second, err := subs.Create(ctx, newSub(" "+name+"  "))
+	assert.NoError(t, err)
+	assert.Equal(t, first.ServiceID, second.ServiceID)
+	assert.Equal(t, name, second.ServiceName)
+
+	renamedService := newService(name + " Multi")
+	renamedService.ID = first.ServiceID
+	_, err = services.Update(ctx, renamedService)
+	assert.NoError(t, err)
+	renamed, err := subs.GetByID(ctx, second.PublicID)
+	assert.NoError(t, err)
+	assert.Equal(t, name+" Multi", renamed.ServiceName)
+	assert.Equal(t, second.Version+1, renamed.Version, "a renamed subscription gets a new version")
+
+	action := entity.AuditActionUpdate
+	entries, err := NewAuditRepository(client).GetAll(ctx, &entity.AuditFilter{SubscriptionID: &second.ID, Action: &action, Limit: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Contains(t, string(entries[0].Before), name)
+		assert.Contains(t, string(entries[0].After), name+" Multi")
+	}
+
+	err = services.Delete(ctx, first.ServiceID)
+	assert.ErrorIs(t, err, errors_custom.ErrServiceInUse)
+
+	missing := newSub(name)
+	missing.ServiceID = -1
+	_, err = subs.Create(ctx, missing)
+	assert.ErrorIs(t, err, errors_custom.ErrServiceNotFound)
+
+	// a failed change leaves no service behind
+	failed := newSub("Okko " + uuid.NewString())
+	failed.Category = "no such category"
+	_, err = subs.Create(ctx, failed)
+	assert.ErrorIs(t, err, errors_custom.ErrCategoryNotFound)
+	_, err = services.Resolve(ctx, utils.NormalizeServiceName(failed.ServiceName))
+	assert.ErrorIs(t, err, errors_custom.ErrServiceNotFound)
+}
+
+func TestServiceRepository_AliasesAndMerge(t *testing.T) {
+	client, err := go_postgres.NewTestClient()
+	assert.NoError(t, err)
+	services := NewServicesRepository(client)
+	subs := NewSubscriptionsRepository(client)
+	ctx := testContext()
+	suffix := uuid.NewString()
+
+	target, err := services.Create(ctx, newService("Kinopoisk "+suffix))
+	assert.NoError(t, err)
+	source, err := services.Create(ctx, newService("Kinopoisk HD "+suffix))
+	assert.NoError(t, err)
+
+	aliasName := "Кинопоиск " + suffix
+	alias, err := services.AddAlias(ctx, &entity.ServiceAlias{
+		ServiceID:       source.ID,
+		Alias:           aliasName,
+		NormalizedAlias: utils.NormalizeServiceName(aliasName),
+		CreatedAt:       time.Now(),
+	})
+	assert.NoError(t, err)
+
+	resolved, err := services.Resolve(ctx, utils.NormalizeServiceName(aliasName))
+	assert.NoError(t, err)
+	assert.Equal(t, source.ID, resolved.ID)
+
+	_, err = services.AddAlias(ctx, &entity.ServiceAlias{
+		ServiceID:       source.ID,
+		Alias:           target.Name,
+		NormalizedAlias: target.NormalizedName,
+		CreatedAt:       time.Now(),
+	})
+	assert.ErrorIs(t, err, errors_custom.ErrServiceAlreadyExists)
+
+	sub, err := subs.Create(ctx, &entity.Subscription{
+		ServiceID:     source.ID,
+		Price:         money.FromMajor(299),
+		Currency:      entity.DefaultCurrency,
+		BillingPeriod: entity.BillingPeriodMonth,
+		BillingMonths: 1,
+		UserID:        uuid.New(),
+		StartDate:     time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC),
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	})
+	assert.NoError(t, err)
+
+	merged, err := services.Merge(ctx, target.ID, source.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, target.ID, merged.ID)
+
+	moved, err := subs.GetByID(ctx, sub.PublicID)
+	assert.NoError(t, err)
+	assert.Equal(t, target.ID, moved.ServiceID)
+	assert.Equal(t, target.Name, moved.ServiceName)
+	assert.Equal(t, sub.Version+1, moved.Version, "a moved subscription gets a new version")
+
+	action := entity.AuditActionUpdate
+	entries, err := NewAuditRepository(client).GetAll(ctx, &entity.AuditFilter{SubscriptionID: &sub.ID, Action: &action, Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	aliases, err := services.GetAliases(ctx, target.ID)
+	assert.NoError(t, err)
+	assert.Len(t, aliases, 2)
+
+	resolved, err = services.Resolve(ctx, source.NormalizedName)
+	assert.NoError(t, err)
+	assert.Equal(t, target.ID, resolved.ID)
+
+	assert.NoError(t, services.DeleteAlias(ctx, target.ID, alias.ID))
+	err = services.DeleteAlias(ctx, target.ID, alias.ID)
+	assert.ErrorIs(t, err, errors_custom.ErrServiceAliasNotFound)
+}
@@ -5,18 +5,24 @@ import (
 	"AggregationService/internal/domain/ports/repository"
 	errors_custom "AggregationService/internal/errors"
 	"AggregationService/internal/infrastructure/database/go_postgres"
+	"AggregationService/internal/pkg/tenant"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
-	_ "github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	"time"
 )
 
 const tableSubscriptions = "subscriptions"
 
+// pgExclusionViolation is raised by subscriptions_no_overlap when a user gets
+// two overlapping active subscriptions to one service.
+const pgExclusionViolation = "23P01"
+
 type subscriptionsRepository struct {
 	client *go_postgres.PostgresClient
 }
@@ -25,159 +31,573 @@ func NewSubscriptionsRepository(client *go_postgres.PostgresClient) repository.I
 	return &subscriptionsRepository{client: client}
 }
 
+// tenantID is the tenant the queries made with ctx are scoped to. Every query
+// of the repository filters by it, row-level security only backs that up.
+func tenantID(ctx context.Context) uuid.UUID {
+	id, _ := tenant.FromContext(ctx)
+	return id
+}
+
+// Create stores a subscription with its first price period and its tags. The
+// service is resolved from the catalog in the same transaction, see
+// resolveService.
 func (s *subscriptionsRepository) Create(ctx context.Context, subscription *entity.Subscription) (*entity.Subscription, error) {
 	const op = "repository.postgres.Create"
+
+	tx, err := s.client.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err = s.create(ctx, tx, subscription); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return subscription, nil
+}
+
+// create is Create within tx.
+func (s *subscriptionsRepository) create(ctx context.Context, tx *sqlx.Tx, subscription *entity.Subscription) error {
+	const op = "repository.postgres.Create"
+
+	err := resolveService(ctx, tx, s.client.Builder, subscription)
+	if err != nil {
+		if errors.Is(err, errors_custom.ErrServiceNotFound) {
+			return err
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if subscription.Category == "" {
+		subscription.Category = entity.DefaultCategory
+	}
+	if subscription.BillingDay == 0 {
+		subscription.BillingDay = entity.DefaultBillingDay
+	}
+	if subscription.PublicID == uuid.Nil {
+		if subscription.PublicID, err = uuid.NewV7(); err != nil {
+			return fmt.Errorf("%s: to generate public id: %w", op, err)
+		}
+	}
+
 	sq := s.client.Builder.
 		Insert(tableSubscriptions).
 		Columns(
+			"tenant_id",
+			"public_id",
+			"service_id",
 			"service_name",
 			"price",
+			"currency",
+			"billing_period",
+			"billing_months",
+			"billing_day",
+			"auto_renew",
 			"user_id",
 			"start_date",
 			"end_date",
+			"category",
+			"metadata",
 			"created_at",
 			"updated_at",
 		).
 		Values(
+			tenantID(ctx),
+			subscription.PublicID,
+			subscription.ServiceID,
 			subscription.ServiceName,
 			subscription.Price,
+			subscription.Currency,
+			subscription.BillingPeriod,
+			subscription.BillingMonths,
+			subscription.BillingDay,
+			subscription.AutoRenew,
 			subscription.UserID,
 			subscription.StartDate,
 			subscription.EndDate,
+			subscription.Category,
+			subscription.Metadata,
 			subscription.CreatedAt,
 			subscription.UpdatedAt,
 		).
-		Suffix(`RETURNING id, created_at`)
+		Suffix(`RETURNING id, created_at, version, status`)
 	query, args, err := sq.ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+		return fmt.Errorf("%s: to sql: %w", op, err)
 	}
 
-	var id int
+	var id, version int
 	var createdAt time.Time
-	if err = s.client.DB.QueryRowxContext(ctx, query, args...).Scan(&id, &createdAt); err != nil {
-		return nil, fmt.Errorf("%s: to scan: %w", op, err)
+	var status string
+	if err = tx.QueryRowxContext(ctx, query, args...).Scan(&id, &createdAt, &version, &status); err != nil {
+		if isPgError(err, pgForeignKeyViolation) {
+			return errors_custom.ErrCategoryNotFound
+		}
+		if isPgError(err, pgExclusionViolation) {
+			return s.overlapConflict(ctx, subscription)
+		}
+		return fmt.Errorf("%s: to scan: %w", op, err)
+	}
+	if err = saveTags(ctx, tx, s.client.Builder, id, subscription.Tags); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// the first price period starts with the subscription
+	priceQuery, priceArgs, err := s.client.Builder.
+		Insert(tableSubscriptionPrices).
+		Columns("subscription_id", "price", "effective_from").
+		Values(id, subscription.Price, squirrel.Expr("date_trunc('month', ?::date)::date", subscription.StartDate)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: to sql: %w", op, err)
 	}
+	if _, err = tx.ExecContext(ctx, priceQuery, priceArgs...); err != nil {
+		return fmt.Errorf("%s: to insert price: %w", op, err)
+	}
+	if err = refreshRollup(ctx, tx, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	subscription.ID = id
 	subscription.CreatedAt = createdAt
-	return subscription, nil
+	subscription.Version = version
+	subscription.Status = status
+	if err = recordAudit(ctx, tx, s.client.Builder, entity.AuditActionCreate, subscription, nil, subscription); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
 }
 
-func (s *subscriptionsRepository) GetByID(ctx context.Context, id int) (*entity.Subscription, error) {
-	const op = "repository.postgres.GetByID"
-	var sub entity.Subscription
+// GetByID returns a subscription that is not deleted by its public id, with
+// its tags and pauses.
+func (s *subscriptionsRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Subscription, error) {
+	return s.get(ctx, squirrel.Eq{"public_id": id})
+}
+
+// GetPublicID returns the public id of the subscription with the internal id,
+// deleted or not. It serves the integer ids the API still accepts.
+func (s *subscriptionsRepository) GetPublicID(ctx context.Context, id int) (uuid.UUID, error) {
+	const op = "repository.postgres.GetPublicID"
+	query, args, err := s.client.Builder.
+		Select("public_id").
+		From(tableSubscriptions).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "id": id}).
+		ToSql()
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	var publicID uuid.UUID
+	err = s.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.QueryRowxContext(ctx, query, args...).Scan(&publicID)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, errors_custom.ErrSubscriptionNotFound
+		}
+		return uuid.Nil, fmt.Errorf("%s: to scan: %w", op, err)
+	}
+	return publicID, nil
+}
+
+// get returns the subscription that is not deleted and matches where, with
+// its tags and pauses.
+func (s *subscriptionsRepository) get(ctx context.Context, where squirrel.Eq) (*entity.Subscription, error) {
+	const op = "repository.postgres.get"
+	var sub *entity.Subscription
+	err := s.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		sub, err = s.getTx(ctx, tx, where, false)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, errors_custom.ErrSubscriptionNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return sub, nil
+}
+
+// getTx is get within tx. With forUpdate the subscription stays locked until
+// tx ends, so it is the state a change made in tx starts from.
+func (s *subscriptionsRepository) getTx(ctx context.Context, tx *sqlx.Tx, where squirrel.Eq, forUpdate bool) (*entity.Subscription, error) {
+	where["tenant_id"] = tenantID(ctx)
+	where["deleted_at"] = nil
 	sq := s.client.Builder.
 		Select("*").
 		From(tableSubscriptions).
-		Where(squirrel.Eq{"id": id})
+		Where(where)
+	if forUpdate {
+		sq = sq.Suffix("FOR UPDATE")
+	}
 	query, args, err := sq.ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+		return nil, fmt.Errorf("to sql: %w", err)
 	}
 
-	if err = s.client.DB.GetContext(ctx, &sub, query, args...); err != nil {
+	var sub entity.Subscription
+	if err = tx.GetContext(ctx, &sub, query, args...); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errors_custom.ErrSubscriptionNotFound
 		}
-		return nil, fmt.Errorf("%s: query error: %w", op, err)
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	if err = loadTags(ctx, tx, s.client.Builder, &sub); err != nil {
+		return nil, err
+	}
+	if err = loadPauses(ctx, tx, s.client.Builder, &sub); err != nil {
+		return nil, err
 	}
 	return &sub, nil
 }
 
-func (s *subscriptionsRepository) GetAll(ctx context.Context, userID *uuid.UUID, serviceName *string, limit, offset int) ([]*entity.Subscription, error) {
+func (s *subscriptionsRepository) GetAll(ctx context.Context, filter *entity.SubscriptionFilter) ([]*entity.Subscription, error) {
 	const op = "repository.postgres.GetAll"
 	sq := s.client.Builder.
 		Select("*").
-		From(tableSubscriptions)
-	if userID != nil {
-		sq = sq.Where(squirrel.Eq{"user_id": userID})
+		From(tableSubscriptions).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx)})
+	if !filter.IncludeDeleted {
+		sq = sq.Where(squirrel.Eq{"deleted_at": nil})
+	}
+	if filter.UserID != nil {
+		sq = sq.Where(squirrel.Eq{"user_id": *filter.UserID})
 	}
-	if serviceName != nil {
-		sq = sq.Where(squirrel.ILike{"service_name": "%" + *serviceName + "%"})
+	if filter.ServiceID != nil {
+		sq = sq.Where(squirrel.Eq{"service_id": *filter.ServiceID})
 	}
-	sq = sq.Limit(uint64(limit)).Offset(uint64(offset))
+	if filter.ServiceName != nil {
+		sq = sq.Where(squirrel.ILike{"service_name": "%" + *filter.ServiceName + "%"})
+	}
+	if filter.Category != nil {
+		sq = sq.Where(squirrel.Eq{"category": *filter.Category})
+	}
+	if filter.Tag != nil {
+		sq = sq.Where(squirrel.Expr("EXISTS (SELECT 1 FROM subscription_tags t WHERE t.subscription_id = subscriptions.id AND t.tag = ?)", *filter.Tag))
+	}
+	if filter.Status != nil {
+		sq = sq.Where(statusCondition(*filter.Status))
+	}
+	if len(filter.Metadata) > 0 {
+		metadata, err := json.Marshal(filter.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("%s: to encode metadata: %w", op, err)
+		}
+		// containment is served by the GIN index on metadata
+		sq = sq.Where(squirrel.Expr("metadata @> ?::jsonb", string(metadata)))
+	}
+	sq = sq.Limit(uint64(filter.Limit)).Offset(uint64(filter.Offset))
 	query, args, err := sq.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("%s: to sql: %w", op, err)
 	}
 	var subs []*entity.Subscription
-	if err = s.client.DB.SelectContext(ctx, &subs, query, args...); err != nil {
-		return nil, fmt.Errorf("%s: select: %w", op, err)
-	}
-	if len(subs) == 0 {
-		return nil, errors_custom.ErrNoSubscriptionsFound
+	err = s.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		if err := tx.SelectContext(ctx, &subs, query, args...); err != nil {
+			return fmt.Errorf("select: %w", err)
+		}
+		if len(subs) == 0 {
+			return errors_custom.ErrNoSubscriptionsFound
+		}
+		if err := loadTags(ctx, tx, s.client.Builder, subs...); err != nil {
+			return err
+		}
+		return loadPauses(ctx, tx, s.client.Builder, subs...)
+	})
+	if err != nil {
+		if errors.Is(err, errors_custom.ErrNoSubscriptionsFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 	return subs, nil
 }
 
-func (s *subscriptionsRepository) Update(ctx context.Context, subscription *entity.Subscription) (*entity.Subscription, error) {
+// Update writes the mutable fields of a subscription. The price is not one of
+// them: it changes through price, stored like in AddPrice, so that the price
+// history is kept; price may be nil. The tags are replaced, the service is
+// resolved like in Create and the monthly rollup of the subscription is
+// refreshed in the same transaction. The update only applies to the version of
+// subscription, otherwise it fails with ErrVersionMismatch; on success
+// subscription gets the new version, once even with a new price.
+func (s *subscriptionsRepository) Update(ctx context.Context, subscription *entity.Subscription, price *entity.SubscriptionPrice) (*entity.Subscription, error) {
+	const op = "repository.postgres.Update"
+
+	tx, err := s.client.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err = s.update(ctx, tx, subscription); err != nil {
+		return nil, err
+	}
+	if price != nil {
+		if err = s.addPrice(ctx, tx, price, false); err != nil {
+			return nil, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return subscription, nil
+}
+
+// update is Update within tx.
+func (s *subscriptionsRepository) update(ctx context.Context, tx *sqlx.Tx, subscription *entity.Subscription) error {
 	const op = "repository.postgres.Update"
+
+	before, err := s.getTx(ctx, tx, squirrel.Eq{"id": subscription.ID}, true)
+	if err != nil {
+		if errors.Is(err, errors_custom.ErrSubscriptionNotFound) {
+			return err
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err = resolveService(ctx, tx, s.client.Builder, subscription); err != nil {
+		if errors.Is(err, errors_custom.ErrServiceNotFound) {
+			return err
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	sq := s.client.Builder.
 		Update(tableSubscriptions).
+		Set("service_id", subscription.ServiceID).
 		Set("service_name", subscription.ServiceName).
-		Set("price", subscription.Price).
+		Set("currency", subscription.Currency).
+		Set("billing_period", subscription.BillingPeriod).
+		Set("billing_months", subscription.BillingMonths).
+		Set("billing_day", subscription.BillingDay).
+		Set("auto_renew", subscription.AutoRenew).
 		Set("end_date", subscription.EndDate).
+		Set("category", subscription.Category).
+		Set("metadata", subscription.Metadata).
 		Set("updated_at", subscription.UpdatedAt).
-		Where(squirrel.Eq{"id": subscription.ID}).
-		Suffix("RETURNING updated_at")
+		Set("version", squirrel.Expr("version + 1")).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "id": subscription.ID, "deleted_at": nil, "version": subscription.Version}).
+		Suffix("RETURNING updated_at, version")
 
 	query, args, err := sq.ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+		return fmt.Errorf("%s: to sql: %w", op, err)
 	}
+
 	var updatedAt time.Time
-	if err = s.client.DB.QueryRowxContext(ctx, query, args...).Scan(&updatedAt); err != nil {
-		return nil, fmt.Errorf("%s: to scan: %w", op, err)
+	var version int
+	if err = tx.QueryRowxContext(ctx, query, args...).Scan(&updatedAt, &version); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return s.missingOrStale(ctx, tx, subscription.ID)
+		}
+		if isPgError(err, pgForeignKeyViolation) {
+			return errors_custom.ErrCategoryNotFound
+		}
+		if isPgError(err, pgExclusionViolation) {
+			return s.overlapConflict(ctx, subscription)
+		}
+		return fmt.Errorf("%s: to scan: %w", op, err)
 	}
-	return subscription, nil
+	if err = saveTags(ctx, tx, s.client.Builder, subscription.ID, subscription.Tags); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err = refreshRollup(ctx, tx, subscription.ID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	subscription.UpdatedAt = updatedAt
+	subscription.Version = version
+	if err = recordAudit(ctx, tx, s.client.Builder, entity.AuditActionUpdate, subscription, before, subscription); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
 }
 
-func (s *subscriptionsRepository) Delete(ctx context.Context, id int) error {
+// Delete marks a subscription deleted. It keeps its prices and rollup rows so
+// that Restore can bring it back, but cost reports leave it out. Like Update
+// it only applies to the given version of the subscription.
+func (s *subscriptionsRepository) Delete(ctx context.Context, id int, version int) error {
+	return s.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return s.delete(ctx, tx, id, version)
+	})
+}
+
+// delete is Delete within tx.
+func (s *subscriptionsRepository) delete(ctx context.Context, tx *sqlx.Tx, id int, version int) error {
 	const op = "repository.postgres.Delete"
+	before, err := s.getTx(ctx, tx, squirrel.Eq{"id": id}, true)
+	if err != nil {
+		if errors.Is(err, errors_custom.ErrSubscriptionNotFound) {
+			return err
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	sq := s.client.Builder.
-		Delete(tableSubscriptions).
-		Where(squirrel.Eq{"id": id})
+		Update(tableSubscriptions).
+		Set("deleted_at", squirrel.Expr("NOW()")).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "id": id, "deleted_at": nil, "version": version})
 	query, args, err := sq.ToSql()
 	if err != nil {
 		return fmt.Errorf("%s: to sql: %w", op, err)
 	}
 
-	res, err := s.client.DB.ExecContext(ctx, query, args...)
+	res, err := tx.ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("%s: to delete: %w", op, err)
 	}
 
 	affectedRows, _ := res.RowsAffected()
 	if affectedRows == 0 {
-		return errors_custom.ErrSubscriptionNotFound
+		return s.missingOrStale(ctx, tx, id)
+	}
+	if err = recordAudit(ctx, tx, s.client.Builder, entity.AuditActionDelete, before, before, nil); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
 	return nil
 }
 
-func (s *subscriptionsRepository) CalculateCost(ctx context.Context, userID *uuid.UUID, serviceName *string, startDate, endDate *time.Time) (int, error) {
-	const op = "repository.postgres.CalculateCost"
-	sq := s.client.Builder.
-		Select("COALESCE(SUM(price),0)").
+// overlapConflict finds the active subscription that sub overlaps after the
+// no-overlap constraint rejected it. It falls back to the bare
+// ErrSubscriptionAlreadyFound if the other subscription is gone by then.
+func (s *subscriptionsRepository) overlapConflict(ctx context.Context, sub *entity.Subscription) error {
+	const op = "repository.postgres.overlapConflict"
+	query, args, err := s.client.Builder.
+		Select("id", "public_id").
 		From(tableSubscriptions).
-		Where(squirrel.LtOrEq{"start_date": endDate}).
-		Where(squirrel.Or{
-			squirrel.Eq{"end_date": nil},
-			squirrel.GtOrEq{"end_date": startDate},
-		})
-	if userID != nil {
-		sq = sq.Where(squirrel.Eq{"user_id": *userID})
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "user_id": sub.UserID, "service_id": sub.ServiceID, "deleted_at": nil}).
+		Where(squirrel.NotEq{"id": sub.ID}).
+		Where("daterange(start_date, end_date, '[]') && daterange(?::date, ?::date, '[]')", sub.StartDate, sub.EndDate).
+		OrderBy("id").
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: to sql: %w", op, err)
 	}
-	if serviceName != nil {
-		sq = sq.Where(squirrel.ILike{"service_name": "%" + *serviceName + "%"})
+
+	var conflict errors_custom.SubscriptionConflictError
+	err = s.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.QueryRowxContext(ctx, query, args...).Scan(&conflict.ConflictingID, &conflict.ConflictingPublicID)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors_custom.ErrSubscriptionAlreadyFound
+		}
+		return fmt.Errorf("%s: to scan: %w", op, err)
 	}
-	query, args, err := sq.ToSql()
+	return &conflict
+}
+
+// missingOrStale explains why a write conditional on the version of a
+// subscription matched no row: the subscription is gone or was changed.
+func (s *subscriptionsRepository) missingOrStale(ctx context.Context, db sqlx.QueryerContext, id int) error {
+	const op = "repository.postgres.missingOrStale"
+	query, args, err := s.client.Builder.
+		Select("1").
+		From(tableSubscriptions).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "id": id, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	var exists int
+	if err = db.QueryRowxContext(ctx, query, args...).Scan(&exists); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors_custom.ErrSubscriptionNotFound
+		}
+		return fmt.Errorf("%s: to scan: %w", op, err)
+	}
+	return errors_custom.ErrVersionMismatch
+}
+
+// Restore brings back a deleted subscription by its public id. It fails with
+// ErrSubscriptionNotDeleted when the subscription is not deleted and with
+// SubscriptionConflictError when an active subscription took its place.
+func (s *subscriptionsRepository) Restore(ctx context.Context, id uuid.UUID) (*entity.Subscription, error) {
+	const op = "repository.postgres.Restore"
+
+	lockQuery, lockArgs, err := s.client.Builder.
+		Select("*").
+		From(tableSubscriptions).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "public_id": id}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+	query, args, err := s.client.Builder.
+		Update(tableSubscriptions).
+		Set("deleted_at", nil).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "public_id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	tx, err := s.client.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var deleted entity.Subscription
+	if err = tx.GetContext(ctx, &deleted, lockQuery, lockArgs...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors_custom.ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("%s: to lock: %w", op, err)
+	}
+	if deleted.DeletedAt == nil {
+		return nil, errors_custom.ErrSubscriptionNotDeleted
+	}
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		if isPgError(err, pgExclusionViolation) {
+			return nil, s.overlapConflict(ctx, &deleted)
+		}
+		return nil, fmt.Errorf("%s: to restore: %w", op, err)
+	}
+	restored, err := s.getTx(ctx, tx, squirrel.Eq{"public_id": id}, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err = recordAudit(ctx, tx, s.client.Builder, entity.AuditActionRestore, restored, nil, restored); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return restored, nil
+}
+
+// Purge removes the subscriptions of the tenant deleted before deletedBefore
+// together with their prices, tags and rollup rows, returning how many were
+// removed.
+func (s *subscriptionsRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	const op = "repository.postgres.Purge"
+	query, args, err := s.client.Builder.
+		Delete(tableSubscriptions).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx)}).
+		Where(squirrel.Lt{"deleted_at": deletedBefore}).
+		ToSql()
 	if err != nil {
 		return 0, fmt.Errorf("%s: to sql: %w", op, err)
 	}
 
-	var totalCost int
-	if err = s.client.DB.GetContext(ctx, &totalCost, query, args...); err != nil {
-		return 0, fmt.Errorf("%s: to extract total cost: %w", op, err)
+	var purged int64
+	err = s.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		purged, _ = res.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: to purge: %w", op, err)
 	}
-	return totalCost, nil
+	return purged, nil
 }
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	errors_custom "AggregationService/internal/errors"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// Batch runs items in order in one transaction and sets Err of the items that
+// fail. An atomic batch stops at the first failure and is rolled back with
+// ErrBatchRolledBack. Otherwise every item runs in a savepoint, so a failed
+// item is undone alone and the others are committed.
+func (s *subscriptionsRepository) Batch(ctx context.Context, items []*entity.SubscriptionBatchItem, atomic bool) error {
+	const op = "repository.postgres.Batch"
+
+	tx, err := s.client.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	for _, item := range items {
+		if !atomic {
+			if _, err = tx.ExecContext(ctx, "SAVEPOINT batch_item"); err != nil {
+				return fmt.Errorf("%s: savepoint: %w", op, err)
+			}
+		}
+
+		item.Err = s.batchItem(ctx, tx, item)
+		switch {
+		case item.Err != nil && atomic:
+			return errors_custom.ErrBatchRolledBack
+		case item.Err != nil:
+			_, err = tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT batch_item")
+		case !atomic:
+			_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT batch_item")
+		}
+		if err != nil {
+			return fmt.Errorf("%s: savepoint: %w", op, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return nil
+}
+
+// batchItem runs one item of a batch within tx.
+func (s *subscriptionsRepository) batchItem(ctx context.Context, tx *sqlx.Tx, item *entity.SubscriptionBatchItem) error {
+	const op = "repository.postgres.Batch"
+
+	switch item.Action {
+	case entity.BatchActionCreate:
+		return s.create(ctx, tx, item.Subscription)
+	case entity.BatchActionUpdate, entity.BatchActionDelete:
+		return s.batchChange(ctx, tx, item)
+	}
+	return fmt.Errorf("%s: unknown action %q", op, item.Action)
+}
+
+// batchChange runs an update or delete of a batch within tx. Its subscription
+// is read and locked here, so it sees the items before it.
+func (s *subscriptionsRepository) batchChange(ctx context.Context, tx *sqlx.Tx, item *entity.SubscriptionBatchItem) error {
+	const op = "repository.postgres.Batch"
+
+	sub, err := s.getTx(ctx, tx, squirrel.Eq{"public_id": item.ID}, true)
+	if err != nil {
+		if errors.Is(err, errors_custom.ErrSubscriptionNotFound) {
+			return err
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if item.Version != nil && *item.Version != sub.Version {
+		return errors_custom.ErrVersionMismatch
+	}
+	item.Subscription = sub
+	if item.Action == entity.BatchActionDelete {
+		return s.delete(ctx, tx, sub.ID, sub.Version)
+	}
+
+	if item.Price, err = item.Apply(sub); err != nil {
+		return err
+	}
+	if err = s.update(ctx, tx, sub); err != nil {
+		return err
+	}
+	if item.Price == nil {
+		return nil
+	}
+	if err = s.addPrice(ctx, tx, item.Price, false); err != nil {
+		return err
+	}
+	// the new price may be the current one of the subscription now
+	if item.Subscription, err = s.getTx(ctx, tx, squirrel.Eq{"id": sub.ID}, false); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
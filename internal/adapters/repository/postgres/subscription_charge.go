@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	"context"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// GetRenewing returns the auto-renewing subscriptions that may be charged
+// between filter.From and filter.To, with their pauses and price history so
+// that the charges can be worked out, see Subscription.ChargeDates.
+func (s *subscriptionsRepository) GetRenewing(ctx context.Context, filter *entity.ChargeFilter) ([]*entity.Subscription, error) {
+	const op = "repository.postgres.GetRenewing"
+	sq := s.client.Builder.
+		Select("*").
+		From(tableSubscriptions).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "deleted_at": nil, "auto_renew": true}).
+		Where(squirrel.LtOrEq{"start_date": filter.To}).
+		Where(squirrel.Or{
+			squirrel.Eq{"end_date": nil},
+			squirrel.Expr("end_date >= date_trunc('month', ?::date)", filter.From),
+		}).
+		OrderBy("user_id", "id")
+	if filter.UserID != nil {
+		sq = sq.Where(squirrel.Eq{"user_id": *filter.UserID})
+	}
+	query, args, err := sq.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	subs := make([]*entity.Subscription, 0)
+	err = s.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		if err := tx.SelectContext(ctx, &subs, query, args...); err != nil {
+			return fmt.Errorf("select: %w", err)
+		}
+		if err := loadPauses(ctx, tx, s.client.Builder, subs...); err != nil {
+			return err
+		}
+		return loadPrices(ctx, tx, s.client.Builder, subs...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return subs, nil
+}
@@ -0,0 +1,304 @@
+package postgres
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	errors_custom "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/audit"
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"time"
+)
+
+// tableSubscriptionImport is the temporary table an import is copied into
+// before it is checked and moved to subscriptions.
+const tableSubscriptionImport = "subscription_import"
+
+var importColumns = []string{
+	"line",
+	"public_id",
+	"service_id",
+	"service_name",
+	"price",
+	"currency",
+	"billing_period",
+	"billing_months",
+	"billing_day",
+	"auto_renew",
+	"user_id",
+	"start_date",
+	"end_date",
+	"category",
+	"created_at",
+}
+
+const createImportTable = `CREATE TEMP TABLE subscription_import (
+	line INT PRIMARY KEY,
+	public_id UUID NOT NULL,
+	service_id INT,
+	service_name VARCHAR(255) NOT NULL,
+	price BIGINT NOT NULL,
+	currency CHAR(3) NOT NULL,
+	billing_period VARCHAR(16) NOT NULL,
+	billing_months INT NOT NULL,
+	billing_day SMALLINT NOT NULL,
+	auto_renew BOOLEAN NOT NULL,
+	user_id UUID NOT NULL,
+	start_date DATE NOT NULL,
+	end_date DATE,
+	category VARCHAR(50) NOT NULL,
+	created_at TIMESTAMP NOT NULL
+) ON COMMIT DROP`
+
+// importServices adds the services of the rows not resolved by the caller to
+// the catalog, like resolveService does for one subscription.
+const importServices = `INSERT INTO services (name, normalized_name)
+SELECT DISTINCT ON (normalize_service_name(service_name))
+	regexp_replace(btrim(service_name), '\s+', ' ', 'g'), normalize_service_name(service_name)
+FROM subscription_import
+WHERE service_id IS NULL
+ORDER BY normalize_service_name(service_name), line
+ON CONFLICT (tenant_id, normalized_name) DO NOTHING`
+
+const resolveImportServices = `UPDATE subscription_import i
+SET service_id = s.id, service_name = s.name
+FROM services s
+WHERE i.service_id IS NULL AND s.tenant_id = $1 AND s.normalized_name = normalize_service_name(i.service_name)`
+
+// importOverlaps finds the rows that overlap an active subscription or an
+// earlier row, which subscriptions_no_overlap would reject.
+const importOverlaps = `SELECT i.line, s.id, s.public_id, j.line
+FROM subscription_import i
+LEFT JOIN LATERAL (
+	SELECT s.id, s.public_id FROM subscriptions s
+	WHERE s.tenant_id = $1 AND s.deleted_at IS NULL AND s.user_id = i.user_id AND s.service_id = i.service_id
+		AND daterange(s.start_date, s.end_date, '[]') && daterange(i.start_date, i.end_date, '[]')
+	ORDER BY s.id LIMIT 1
+) s ON TRUE
+LEFT JOIN LATERAL (
+	SELECT j.line FROM subscription_import j
+	WHERE j.line < i.line AND j.user_id = i.user_id AND j.service_id = i.service_id
+		AND daterange(j.start_date, j.end_date, '[]') && daterange(i.start_date, i.end_date, '[]')
+	ORDER BY j.line LIMIT 1
+) j ON TRUE
+WHERE s.id IS NOT NULL OR j.line IS NOT NULL
+ORDER BY i.line`
+
+const insertImport = `INSERT INTO subscriptions (tenant_id, public_id, service_id, service_name, price, currency,
+	billing_period, billing_months, billing_day, auto_renew, user_id, start_date, end_date, category, created_at, updated_at)
+SELECT $1, public_id, service_id, service_name, price, currency,
+	billing_period, billing_months, billing_day, auto_renew, user_id, start_date, end_date, category, created_at, created_at
+FROM subscription_import
+ORDER BY line
+RETURNING id`
+
+// auditImport records the creation of every imported subscription in the
+// audit trail with one statement. COPY can't write to subscription_audit, as
+// it has row level security, so the rows are passed as arrays.
+const auditImport = `INSERT INTO subscription_audit (subscription_id, subscription_public_id, user_id, action, after,
+	request_id, actor, created_at)
+SELECT s.id, s.public_id, s.user_id, $2, a.after::jsonb, $3, $4, $5
+FROM unnest($6::int[], $7::text[]) AS a(id, after)
+JOIN subscriptions s ON s.tenant_id = $1 AND s.id = a.id
+ORDER BY s.id`
+
+// Import stores the rows that next yields until it returns nil. The rows are
+// streamed into a temporary table with COPY and then moved to subscriptions
+// with their first price period in one statement each, within one
+// transaction, which also records them in the audit trail. Rows overlapping
+// an active subscription or an earlier row are rejected instead of failing
+// the import. A dry run reports the same but rolls back.
+func (s *subscriptionsRepository) Import(ctx context.Context, next func() (*entity.SubscriptionImportRow, error), dryRun bool) (*entity.SubscriptionImportReport, error) {
+	const op = "repository.postgres.Import"
+
+	tx, err := s.client.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, createImportTable); err != nil {
+		return nil, fmt.Errorf("%s: to create import table: %w", op, err)
+	}
+	if err = copyImport(ctx, tx, next); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err = tx.ExecContext(ctx, importServices); err != nil {
+		return nil, fmt.Errorf("%s: to add services: %w", op, err)
+	}
+	if _, err = tx.ExecContext(ctx, resolveImportServices, tenantID(ctx)); err != nil {
+		return nil, fmt.Errorf("%s: to resolve services: %w", op, err)
+	}
+
+	report := &entity.SubscriptionImportReport{}
+	if report.Rejected, err = rejectImportOverlaps(ctx, tx); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var ids []int
+	if err = tx.SelectContext(ctx, &ids, insertImport, tenantID(ctx)); err != nil {
+		if isPgError(err, pgForeignKeyViolation) {
+			return nil, errors_custom.ErrCategoryNotFound
+		}
+		// an overlap with a subscription the tenant can't see
+		if isPgError(err, pgExclusionViolation) {
+			return nil, errors_custom.ErrSubscriptionAlreadyFound
+		}
+		return nil, fmt.Errorf("%s: to insert: %w", op, err)
+	}
+	report.Imported = int64(len(ids))
+
+	// the first price period of every subscription starts with it
+	if _, err = tx.ExecContext(ctx, `INSERT INTO subscription_prices (subscription_id, price, effective_from)
+		SELECT id, price, date_trunc('month', start_date)::date FROM subscriptions WHERE tenant_id = $1 AND id = ANY($2)`,
+		tenantID(ctx), pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("%s: to insert prices: %w", op, err)
+	}
+	if _, err = tx.ExecContext(ctx, "SELECT refresh_subscription_rollup(id) FROM unnest($1::int[]) AS id", pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("%s: to refresh rollup: %w", op, err)
+	}
+
+	if dryRun {
+		return report, nil
+	}
+	if err = s.recordImportAudit(ctx, tx, ids); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return report, nil
+}
+
+// recordImportAudit records the creation of the imported subscriptions ids in
+// the audit trail within tx, with the same snapshots recordAudit makes.
+func (s *subscriptionsRepository) recordImportAudit(ctx context.Context, tx *sqlx.Tx, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	query, args, err := s.client.Builder.
+		Select("*").
+		From(tableSubscriptions).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx)}).
+		Where("id = ANY(?)", pq.Array(ids)).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("to sql: %w", err)
+	}
+	subs := make([]*entity.Subscription, 0, len(ids))
+	if err = tx.SelectContext(ctx, &subs, query, args...); err != nil {
+		return fmt.Errorf("to select imported: %w", err)
+	}
+
+	auditIDs := make([]int, 0, len(subs))
+	afters := make([]string, 0, len(subs))
+	for _, sub := range subs {
+		after, err := auditSnapshot(sub)
+		if err != nil {
+			return fmt.Errorf("to snapshot audit: %w", err)
+		}
+		auditIDs = append(auditIDs, sub.ID)
+		afters = append(afters, string(after))
+	}
+
+	meta := audit.FromContext(ctx)
+	if _, err = tx.ExecContext(ctx, auditImport, tenantID(ctx), entity.AuditActionCreate, meta.RequestID, meta.Actor,
+		time.Now(), pq.Array(auditIDs), pq.Array(afters)); err != nil {
+		return fmt.Errorf("to insert audit: %w", err)
+	}
+	return nil
+}
+
+// copyImport copies the rows of next into the import table.
+func copyImport(ctx context.Context, tx *sqlx.Tx, next func() (*entity.SubscriptionImportRow, error)) error {
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(tableSubscriptionImport, importColumns...))
+	if err != nil {
+		return fmt.Errorf("to prepare copy: %w", err)
+	}
+	defer stmt.Close()
+
+	for {
+		row, err := next()
+		if err != nil {
+			return fmt.Errorf("to read row: %w", err)
+		}
+		if row == nil {
+			break
+		}
+
+		sub := row.Subscription
+		if sub.Category == "" {
+			sub.Category = entity.DefaultCategory
+		}
+		if sub.BillingDay == 0 {
+			sub.BillingDay = entity.DefaultBillingDay
+		}
+		if sub.PublicID == uuid.Nil {
+			if sub.PublicID, err = uuid.NewV7(); err != nil {
+				return fmt.Errorf("to generate public id: %w", err)
+			}
+		}
+		serviceID := sql.NullInt64{Int64: int64(sub.ServiceID), Valid: sub.ServiceID != 0}
+		if _, err = stmt.ExecContext(ctx, row.Line, sub.PublicID, serviceID, sub.ServiceName, int64(sub.Price),
+			sub.Currency, sub.BillingPeriod, sub.BillingMonths, sub.BillingDay, sub.AutoRenew, sub.UserID,
+			sub.StartDate, sub.EndDate, sub.Category, sub.CreatedAt); err != nil {
+			return fmt.Errorf("to copy line %d: %w", row.Line, err)
+		}
+	}
+
+	if _, err = stmt.ExecContext(ctx); err != nil {
+		return fmt.Errorf("to copy: %w", err)
+	}
+	return nil
+}
+
+// rejectImportOverlaps removes the overlapping rows from the import table and
+// returns them.
+func rejectImportOverlaps(ctx context.Context, tx *sqlx.Tx) ([]*entity.SubscriptionImportRejection, error) {
+	rows, err := tx.QueryContext(ctx, importOverlaps, tenantID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("to find overlaps: %w", err)
+	}
+	defer rows.Close()
+
+	rejected := make([]*entity.SubscriptionImportRejection, 0)
+	lines := make([]int, 0)
+	for rows.Next() {
+		var line int
+		var conflictID sql.NullInt64
+		var conflictPublicID uuid.NullUUID
+		var conflictLine sql.NullInt64
+		if err = rows.Scan(&line, &conflictID, &conflictPublicID, &conflictLine); err != nil {
+			return nil, fmt.Errorf("to scan overlap: %w", err)
+		}
+
+		rejection := &entity.SubscriptionImportRejection{Line: line}
+		if conflictID.Valid {
+			rejection.Err = &errors_custom.SubscriptionConflictError{
+				ConflictingID:       int(conflictID.Int64),
+				ConflictingPublicID: conflictPublicID.UUID,
+			}
+		} else {
+			rejection.Err = fmt.Errorf("%w: overlaps line %d", errors_custom.ErrSubscriptionAlreadyFound, conflictLine.Int64)
+		}
+		rejected = append(rejected, rejection)
+		lines = append(lines, line)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("to find overlaps: %w", err)
+	}
+	rows.Close()
+
+	if len(lines) > 0 {
+		if _, err = tx.ExecContext(ctx, "DELETE FROM subscription_import WHERE line = ANY($1)", pq.Array(lines)); err != nil {
+			return nil, fmt.Errorf("to reject overlaps: %w", err)
+		}
+	}
+	return rejected, nil
+}
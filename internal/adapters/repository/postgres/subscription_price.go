@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	errors_custom "AggregationService/internal/errors"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const tableSubscriptionPrices = "subscription_prices"
+
+// pgForeignKeyViolation is the SQLSTATE raised when the subscription of a
+// price does not exist.
+const pgForeignKeyViolation = "23503"
+
+// syncPrice is the price a subscription is charged this month, or its first
+// price while it has not started yet. A price scheduled for a later month is
+// not current before that month.
+const syncPrice = `COALESCE(
+	(SELECT p.price FROM subscription_prices p
+		WHERE p.subscription_id = ? AND p.effective_from <= date_trunc('month', NOW())
+		ORDER BY p.effective_from DESC LIMIT 1),
+	(SELECT p.price FROM subscription_prices p
+		WHERE p.subscription_id = ? ORDER BY p.effective_from LIMIT 1))`
+
+// AddPrice stores the price of a subscription from price.EffectiveFrom on,
+// replacing a price that starts in the same month, and refreshes the current
+// price kept on the subscription itself, see syncPrice, and its monthly rollup.
+func (s *subscriptionsRepository) AddPrice(ctx context.Context, price *entity.SubscriptionPrice) (*entity.SubscriptionPrice, error) {
+	const op = "repository.postgres.AddPrice"
+
+	tx, err := s.client.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err = s.addPrice(ctx, tx, price, true); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return price, nil
+}
+
+// addPrice is AddPrice within tx. The version of the subscription is left to
+// the caller unless bumpVersion is set.
+func (s *subscriptionsRepository) addPrice(ctx context.Context, tx *sqlx.Tx, price *entity.SubscriptionPrice, bumpVersion bool) error {
+	const op = "repository.postgres.AddPrice"
+	// the foreign key sees neither tenants nor soft deletes, so the subscription is checked first
+	lockQuery, lockArgs, err := s.client.Builder.
+		Select("id", "public_id", "user_id").
+		From(tableSubscriptions).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "id": price.SubscriptionID, "deleted_at": nil}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: to sql: %w", op, err)
+	}
+	query, args, err := s.client.Builder.
+		Insert(tableSubscriptionPrices).
+		Columns("subscription_id", "price", "effective_from").
+		Values(price.SubscriptionID, price.Price, price.EffectiveFrom).
+		Suffix(`ON CONFLICT (subscription_id, effective_from) DO UPDATE SET price = EXCLUDED.price
+			RETURNING id, created_at`).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	sync := s.client.Builder.
+		Update(tableSubscriptions).
+		Set("price", squirrel.Expr(syncPrice, price.SubscriptionID, price.SubscriptionID)).
+		Set("updated_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "id": price.SubscriptionID})
+	if bumpVersion {
+		sync = sync.Set("version", squirrel.Expr("version + 1"))
+	}
+	syncQuery, syncArgs, err := sync.ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	var sub entity.Subscription
+	if err = tx.GetContext(ctx, &sub, lockQuery, lockArgs...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors_custom.ErrSubscriptionNotFound
+		}
+		return fmt.Errorf("%s: to lock: %w", op, err)
+	}
+
+	if err = tx.QueryRowxContext(ctx, query, args...).Scan(&price.ID, &price.CreatedAt); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pgForeignKeyViolation {
+			return errors_custom.ErrSubscriptionNotFound
+		}
+		return fmt.Errorf("%s: to scan: %w", op, err)
+	}
+	if _, err = tx.ExecContext(ctx, syncQuery, syncArgs...); err != nil {
+		return fmt.Errorf("%s: to sync price: %w", op, err)
+	}
+	if err = refreshRollup(ctx, tx, price.SubscriptionID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err = recordAudit(ctx, tx, s.client.Builder, entity.AuditActionPrice, &sub, nil, price); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *subscriptionsRepository) GetPrices(ctx context.Context, subscriptionID int) ([]*entity.SubscriptionPrice, error) {
+	const op = "repository.postgres.GetPrices"
+	query, args, err := s.client.Builder.
+		Select("id", "subscription_id", "price", "effective_from", "created_at").
+		From(tableSubscriptionPrices).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "subscription_id": subscriptionID}).
+		OrderBy("effective_from").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	prices := make([]*entity.SubscriptionPrice, 0)
+	err = s.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.SelectContext(ctx, &prices, query, args...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: select: %w", op, err)
+	}
+	return prices, nil
+}
+
+// loadPrices fills the price history of subs with one query.
+func loadPrices(ctx context.Context, db sqlx.QueryerContext, builder squirrel.StatementBuilderType, subs ...*entity.Subscription) error {
+	if len(subs) == 0 {
+		return nil
+	}
+	byID := make(map[int]*entity.Subscription, len(subs))
+	ids := make([]int, 0, len(subs))
+	for _, sub := range subs {
+		sub.Prices = make([]*entity.SubscriptionPrice, 0)
+		byID[sub.ID] = sub
+		ids = append(ids, sub.ID)
+	}
+
+	query, args, err := builder.
+		Select("id", "subscription_id", "price", "effective_from", "created_at").
+		From(tableSubscriptionPrices).
+		Where(squirrel.Eq{"subscription_id": ids}).
+		OrderBy("subscription_id", "effective_from").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("to sql: %w", err)
+	}
+
+	var prices []*entity.SubscriptionPrice
+	if err = sqlx.SelectContext(ctx, db, &prices, query, args...); err != nil {
+		return fmt.Errorf("to select prices: %w", err)
+	}
+	for _, price := range prices {
+		byID[price.SubscriptionID].Prices = append(byID[price.SubscriptionID].Prices, price)
+	}
+	return nil
+}
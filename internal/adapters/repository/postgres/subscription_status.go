@@ -0,0 +1,294 @@
+package postgres
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	errors_custom "AggregationService/internal/errors"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+const tableSubscriptionPauses = "subscription_pauses"
+
+// pausedMonth leaves out the months of the series in which a subscription is
+// paused.
+const pausedMonth = `NOT EXISTS (
+	SELECT 1 FROM subscription_pauses pp
+	WHERE pp.subscription_id = s.id AND pp.paused_from <= m.month
+		AND (pp.resumed_from IS NULL OR pp.resumed_from > m.month)
+)`
+
+// pauseAhead matches the paused subscriptions whose open pause starts after
+// the current month, which are still active until then.
+const pauseAhead = `EXISTS (
+	SELECT 1 FROM subscription_pauses sp
+	WHERE sp.subscription_id = subscriptions.id AND sp.resumed_from IS NULL
+		AND sp.paused_from > date_trunc('month', NOW())
+)`
+
+// statusCondition matches the subscriptions in status like
+// Subscription.StatusAt does now. Expired is not stored, it is an active
+// subscription whose last month has passed.
+func statusCondition(status string) squirrel.Sqlizer {
+	ended := squirrel.Expr("end_date < date_trunc('month', NOW())")
+	switch status {
+	case entity.SubscriptionStatusExpired:
+		return squirrel.And{squirrel.Eq{"status": entity.SubscriptionStatusActive}, ended}
+	case entity.SubscriptionStatusActive:
+		return squirrel.Or{
+			squirrel.And{
+				squirrel.Eq{"status": entity.SubscriptionStatusActive},
+				squirrel.Or{squirrel.Eq{"end_date": nil}, squirrel.Expr("end_date >= date_trunc('month', NOW())")},
+			},
+			squirrel.And{squirrel.Eq{"status": entity.SubscriptionStatusPaused}, squirrel.Expr(pauseAhead)},
+		}
+	case entity.SubscriptionStatusPaused:
+		return squirrel.And{squirrel.Eq{"status": status}, squirrel.Expr("NOT " + pauseAhead)}
+	default:
+		return squirrel.Eq{"status": status}
+	}
+}
+
+// Pause moves an active subscription to paused and opens a pause from
+// pause.PausedFrom; it reads as active until then, see
+// Subscription.StatusAt. Like Update it only applies to the given version of the
+// subscription. A pause that overlaps an earlier one fails with
+// ErrInvalidRequest.
+func (s *subscriptionsRepository) Pause(ctx context.Context, pause *entity.SubscriptionPause, version int) (*entity.Subscription, error) {
+	const op = "repository.postgres.Pause"
+	query, args, err := s.client.Builder.
+		Insert(tableSubscriptionPauses).
+		Columns("subscription_id", "paused_from").
+		Values(pause.SubscriptionID, pause.PausedFrom).
+		Suffix("RETURNING id, created_at").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	tx, err := s.client.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	before, err := s.getTx(ctx, tx, squirrel.Eq{"id": pause.SubscriptionID}, true)
+	if err != nil {
+		if errors.Is(err, errors_custom.ErrSubscriptionNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err = s.changeStatus(ctx, tx, pause.SubscriptionID, version, entity.SubscriptionStatusPaused, nil,
+		entity.SubscriptionStatusActive); err != nil {
+		return nil, err
+	}
+	if err = tx.QueryRowxContext(ctx, query, args...).Scan(&pause.ID, &pause.CreatedAt); err != nil {
+		if isPgError(err, pgExclusionViolation) {
+			return nil, errors_custom.ErrInvalidRequest
+		}
+		return nil, fmt.Errorf("%s: to scan: %w", op, err)
+	}
+	if err = refreshRollup(ctx, tx, pause.SubscriptionID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	after, err := s.getTx(ctx, tx, squirrel.Eq{"id": pause.SubscriptionID}, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err = recordAudit(ctx, tx, s.client.Builder, entity.AuditActionPause, after, before, after); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return after, nil
+}
+
+// Resume moves a paused subscription back to active and closes its open pause
+// before the month of from. A pause that would only start from then on is
+// dropped. Like Update it only applies to the given version.
+func (s *subscriptionsRepository) Resume(ctx context.Context, id int, from time.Time, version int) (*entity.Subscription, error) {
+	const op = "repository.postgres.Resume"
+	dropQuery, dropArgs, err := s.client.Builder.
+		Delete(tableSubscriptionPauses).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "subscription_id": id, "resumed_from": nil}).
+		Where(squirrel.GtOrEq{"paused_from": from}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+	closeQuery, closeArgs, err := s.client.Builder.
+		Update(tableSubscriptionPauses).
+		Set("resumed_from", from).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "subscription_id": id, "resumed_from": nil}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	tx, err := s.client.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	before, err := s.getTx(ctx, tx, squirrel.Eq{"id": id}, true)
+	if err != nil {
+		if errors.Is(err, errors_custom.ErrSubscriptionNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err = s.changeStatus(ctx, tx, id, version, entity.SubscriptionStatusActive, nil,
+		entity.SubscriptionStatusPaused); err != nil {
+		return nil, err
+	}
+	if _, err = tx.ExecContext(ctx, dropQuery, dropArgs...); err != nil {
+		return nil, fmt.Errorf("%s: to drop pause: %w", op, err)
+	}
+	if _, err = tx.ExecContext(ctx, closeQuery, closeArgs...); err != nil {
+		return nil, fmt.Errorf("%s: to close pause: %w", op, err)
+	}
+	if err = refreshRollup(ctx, tx, id); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	after, err := s.getTx(ctx, tx, squirrel.Eq{"id": id}, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err = recordAudit(ctx, tx, s.client.Builder, entity.AuditActionResume, after, before, after); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return after, nil
+}
+
+// Cancel moves an active or paused subscription to cancelled with endDate as
+// its last month. An open pause stays open, the months after endDate cost
+// nothing anyway. Like Update it only applies to the given version.
+func (s *subscriptionsRepository) Cancel(ctx context.Context, id int, endDate time.Time, version int) (*entity.Subscription, error) {
+	const op = "repository.postgres.Cancel"
+
+	tx, err := s.client.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	before, err := s.getTx(ctx, tx, squirrel.Eq{"id": id}, true)
+	if err != nil {
+		if errors.Is(err, errors_custom.ErrSubscriptionNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err = s.changeStatus(ctx, tx, id, version, entity.SubscriptionStatusCancelled, &endDate,
+		entity.SubscriptionStatusActive, entity.SubscriptionStatusPaused); err != nil {
+		return nil, err
+	}
+	if err = refreshRollup(ctx, tx, id); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	after, err := s.getTx(ctx, tx, squirrel.Eq{"id": id}, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err = recordAudit(ctx, tx, s.client.Builder, entity.AuditActionCancel, after, before, after); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return after, nil
+}
+
+func (s *subscriptionsRepository) GetPauses(ctx context.Context, subscriptionID int) ([]*entity.SubscriptionPause, error) {
+	const op = "repository.postgres.GetPauses"
+	query, args, err := s.client.Builder.
+		Select("id", "subscription_id", "paused_from", "resumed_from", "created_at").
+		From(tableSubscriptionPauses).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "subscription_id": subscriptionID}).
+		OrderBy("paused_from").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	pauses := make([]*entity.SubscriptionPause, 0)
+	err = s.client.InTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.SelectContext(ctx, &pauses, query, args...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: select: %w", op, err)
+	}
+	return pauses, nil
+}
+
+// changeStatus moves subscription id from one of the statuses from to status
+// inside tx, setting its end date when endDate is set. It bumps the version
+// and fails like Update when the subscription is not at version.
+func (s *subscriptionsRepository) changeStatus(ctx context.Context, tx *sqlx.Tx, id int, version int, status string, endDate *time.Time, from ...string) error {
+	const op = "repository.postgres.changeStatus"
+	sq := s.client.Builder.
+		Update(tableSubscriptions).
+		Set("status", status).
+		Set("updated_at", squirrel.Expr("NOW()")).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "id": id, "deleted_at": nil, "version": version, "status": from})
+	if endDate != nil {
+		sq = sq.Set("end_date", *endDate)
+	}
+	query, args, err := sq.ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: to update: %w", op, err)
+	}
+	affectedRows, _ := res.RowsAffected()
+	if affectedRows == 0 {
+		return s.missingOrStale(ctx, tx, id)
+	}
+	return nil
+}
+
+// loadPauses fills the pauses of subs with one query.
+func loadPauses(ctx context.Context, db sqlx.QueryerContext, builder squirrel.StatementBuilderType, subs ...*entity.Subscription) error {
+	if len(subs) == 0 {
+		return nil
+	}
+	byID := make(map[int]*entity.Subscription, len(subs))
+	ids := make([]int, 0, len(subs))
+	for _, sub := range subs {
+		sub.Pauses = make([]*entity.SubscriptionPause, 0)
+		byID[sub.ID] = sub
+		ids = append(ids, sub.ID)
+	}
+
+	query, args, err := builder.
+		Select("id", "subscription_id", "paused_from", "resumed_from", "created_at").
+		From(tableSubscriptionPauses).
+		Where(squirrel.Eq{"subscription_id": ids}).
+		OrderBy("subscription_id", "paused_from").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("to sql: %w", err)
+	}
+
+	var pauses []*entity.SubscriptionPause
+	if err = sqlx.SelectContext(ctx, db, &pauses, query, args...); err != nil {
+		return fmt.Errorf("to select pauses: %w", err)
+	}
+	for _, pause := range pauses {
+		byID[pause.SubscriptionID].Pauses = append(byID[pause.SubscriptionID].Pauses, pause)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	"context"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+const tableSubscriptionTags = "subscription_tags"
+
+// saveTags replaces the tags of a subscription inside tx.
+func saveTags(ctx context.Context, tx *sqlx.Tx, builder squirrel.StatementBuilderType, subscriptionID int, tags []string) error {
+	query, args, err := builder.
+		Delete(tableSubscriptionTags).
+		Where(squirrel.Eq{"tenant_id": tenantID(ctx), "subscription_id": subscriptionID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("to sql: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("to delete tags: %w", err)
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	sq := builder.
+		Insert(tableSubscriptionTags).
+		Columns("subscription_id", "tag")
+	for _, tag := range tags {
+		sq = sq.Values(subscriptionID, tag)
+	}
+	query, args, err = sq.ToSql()
+	if err != nil {
+		return fmt.Errorf("to sql: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("to insert tags: %w", err)
+	}
+	return nil
+}
+
+// loadTags fills the tags of subs with one query.
+func loadTags(ctx context.Context, db sqlx.QueryerContext, builder squirrel.StatementBuilderType, subs ...*entity.Subscription) error {
+	if len(subs) == 0 {
+		return nil
+	}
+	byID := make(map[int]*entity.Subscription, len(subs))
+	ids := make([]int, 0, len(subs))
+	for _, sub := range subs {
+		sub.Tags = make([]string, 0)
+		byID[sub.ID] = sub
+		ids = append(ids, sub.ID)
+	}
+
+	query, args, err := builder.
+		Select("subscription_id", "tag").
+		From(tableSubscriptionTags).
+		Where(squirrel.Eq{"subscription_id": ids}).
+		OrderBy("subscription_id", "tag").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("to sql: %w", err)
+	}
+
+	var rows []struct {
+		SubscriptionID int    `db:"subscription_id"`
+		Tag            string `db:"tag"`
+	}
+	if err = sqlx.SelectContext(ctx, db, &rows, query, args...); err != nil {
+		return fmt.Errorf("to select tags: %w", err)
+	}
+	for _, row := range rows {
+		byID[row.SubscriptionID].Tags = append(byID[row.SubscriptionID].Tags, row.Tag)
+	}
+	return nil
+}
@@ -7,11 +7,20 @@ import (
 	"time"
 
 	"AggregationService/internal/domain/models/entity"
+	errors_custom "AggregationService/internal/errors"
 	"AggregationService/internal/infrastructure/database/go_postgres"
+	"AggregationService/internal/pkg/money"
+	"AggregationService/internal/pkg/tenant"
+	"AggregationService/internal/pkg/utils"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
+// testContext scopes the queries of a test to the default tenant.
+func testContext() context.Context {
+	return tenant.ContextWithID(context.Background(), tenant.DefaultID)
+}
+
 func setupTestRepo(t *testing.T) repository.ISubscriptionRepository {
 	client, err := go_postgres.NewTestClient()
 	assert.NoError(t, err)
@@ -20,12 +29,14 @@ func setupTestRepo(t *testing.T) repository.ISubscriptionRepository {
 
 func TestSubscriptionRepository_Create(t *testing.T) {
 	repo := setupTestRepo(t)
-	ctx := context.Background()
+	ctx := testContext()
 	sub := &entity.Subscription{
-		ServiceName: "yandex",
-		Price:       299,
-		UserID:      uuid.New(),
-		StartDate:   time.Now(),
+		ServiceName:   "yandex",
+		Price:         money.FromMajor(299),
+		BillingPeriod: entity.BillingPeriodMonth,
+		BillingMonths: 1,
+		UserID:        uuid.New(),
+		StartDate:     time.Now(),
 	}
 
 	created, err := repo.Create(ctx, sub)
@@ -37,100 +48,938 @@ func TestSubscriptionRepository_Create(t *testing.T) {
 
 func TestSubscriptionRepository_GetByID(t *testing.T) {
 	repo := setupTestRepo(t)
-	ctx := context.Background()
+	ctx := testContext()
 	sub := &entity.Subscription{
-		ServiceName: "yandex",
-		Price:       299,
-		UserID:      uuid.New(),
-		StartDate:   time.Now(),
+		ServiceName:   "yandex",
+		Price:         money.FromMajor(299),
+		BillingPeriod: entity.BillingPeriodMonth,
+		BillingMonths: 1,
+		UserID:        uuid.New(),
+		StartDate:     time.Now(),
 	}
 	created, _ := repo.Create(ctx, sub)
 
-	found, err := repo.GetByID(ctx, created.ID)
+	found, err := repo.GetByID(ctx, created.PublicID)
 	assert.NoError(t, err)
 	assert.Equal(t, created.ID, found.ID)
 	assert.Equal(t, created.ServiceName, found.ServiceName)
 }
 
+func TestSubscriptionRepository_PublicID(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := testContext()
+	created, err := repo.Create(ctx, &entity.Subscription{
+		ServiceName:   "yandex",
+		Price:         money.FromMajor(299),
+		BillingPeriod: entity.BillingPeriodMonth,
+		BillingMonths: 1,
+		UserID:        uuid.New(),
+		StartDate:     time.Now(),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uuid.Version(7), created.PublicID.Version())
+
+	publicID, err := repo.GetPublicID(ctx, created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, created.PublicID, publicID)
+
+	// the integer id still resolves after the subscription is deleted
+	assert.NoError(t, repo.Delete(ctx, created.ID, created.Version))
+	publicID, err = repo.GetPublicID(ctx, created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, created.PublicID, publicID)
+
+	_, err = repo.GetByID(ctx, uuid.New())
+	assert.ErrorIs(t, err, errors_custom.ErrSubscriptionNotFound)
+}
+
 func TestSubscriptionRepository_Update(t *testing.T) {
 	repo := setupTestRepo(t)
-	ctx := context.Background()
+	ctx := testContext()
 	sub := &entity.Subscription{
-		ServiceName: "yandex",
-		Price:       299,
-		UserID:      uuid.New(),
-		StartDate:   time.Now(),
+		ServiceName:   "yandex",
+		Price:         money.FromMajor(299),
+		BillingPeriod: entity.BillingPeriodMonth,
+		BillingMonths: 1,
+		UserID:        uuid.New(),
+		StartDate:     time.Now(),
 	}
 	created, _ := repo.Create(ctx, sub)
 
 	created.ServiceName = "yandex plus"
-	created.Price = 399
+	stale := *created
 
-	updated, err := repo.Update(ctx, created)
+	updated, err := repo.Update(ctx, created, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, "yandex plus", updated.ServiceName)
-	assert.Equal(t, 399, updated.Price)
+	assert.Equal(t, stale.Version+1, updated.Version)
+
+	_, err = repo.Update(ctx, &stale, nil)
+	assert.ErrorIs(t, err, errors_custom.ErrVersionMismatch)
+	assert.ErrorIs(t, repo.Delete(ctx, stale.ID, stale.Version), errors_custom.ErrVersionMismatch)
+}
+
+func TestSubscriptionRepository_UpdateWithPrice(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := testContext()
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	created, err := repo.Create(ctx, &entity.Subscription{
+		ServiceName:   "yandex",
+		Price:         money.FromMajor(299),
+		BillingPeriod: entity.BillingPeriodMonth,
+		BillingMonths: 1,
+		UserID:        uuid.New(),
+		StartDate:     start,
+	})
+	assert.NoError(t, err)
+	version := created.Version
+
+	created.ServiceName = "yandex plus"
+	price := &entity.SubscriptionPrice{
+		SubscriptionID: created.ID,
+		Price:          money.FromMajor(399),
+		EffectiveFrom:  start.AddDate(0, 2, 0),
+	}
+	updated, err := repo.Update(ctx, created, price)
+	assert.NoError(t, err)
+	assert.Equal(t, version+1, updated.Version)
+	assert.NotZero(t, price.ID)
+
+	prices, err := repo.GetPrices(ctx, created.ID)
+	assert.NoError(t, err)
+	assert.Len(t, prices, 2)
 }
 
 func TestSubscriptionRepository_Delete(t *testing.T) {
 	repo := setupTestRepo(t)
-	ctx := context.Background()
+	ctx := testContext()
 	sub := &entity.Subscription{
-		ServiceName: "yandex",
-		Price:       299,
-		UserID:      uuid.New(),
-		StartDate:   time.Now(),
+		ServiceName:   "yandex",
+		Price:         money.FromMajor(299),
+		BillingPeriod: entity.BillingPeriodMonth,
+		BillingMonths: 1,
+		UserID:        uuid.New(),
+		StartDate:     time.Now(),
 	}
 	created, _ := repo.Create(ctx, sub)
 
-	err := repo.Delete(ctx, created.ID)
+	err := repo.Delete(ctx, created.ID, created.Version)
 	assert.NoError(t, err)
 
-	_, err = repo.GetByID(ctx, created.ID)
+	_, err = repo.GetByID(ctx, created.PublicID)
 	assert.Error(t, err)
+
+	err = repo.Delete(ctx, created.ID, created.Version)
+	assert.ErrorIs(t, err, errors_custom.ErrSubscriptionNotFound)
+}
+
+func TestSubscriptionRepository_RestoreAndPurge(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := testContext()
+	userID := uuid.New()
+	startDate := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	created, err := repo.Create(ctx, &entity.Subscription{
+		ServiceName:   "yandex",
+		Price:         money.FromMajor(299),
+		Currency:      entity.DefaultCurrency,
+		BillingPeriod: entity.BillingPeriodMonth,
+		BillingMonths: 1,
+		UserID:        userID,
+		StartDate:     startDate,
+		Tags:          []string{"fun"},
+	})
+	assert.NoError(t, err)
+
+	_, err = repo.Restore(ctx, created.PublicID)
+	assert.ErrorIs(t, err, errors_custom.ErrSubscriptionNotDeleted)
+
+	assert.NoError(t, repo.Delete(ctx, created.ID, created.Version))
+
+	costFilter := &entity.CostFilter{UserID: &userID, StartDate: startDate, EndDate: startDate, Currency: entity.DefaultCurrency}
+	report, err := repo.CalculateCost(ctx, costFilter)
+	assert.NoError(t, err)
+	assert.Empty(t, report.Subscriptions)
+
+	_, err = repo.GetAll(ctx, &entity.SubscriptionFilter{UserID: &userID, Limit: 10})
+	assert.ErrorIs(t, err, errors_custom.ErrNoSubscriptionsFound)
+	subs, err := repo.GetAll(ctx, &entity.SubscriptionFilter{UserID: &userID, IncludeDeleted: true, Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, subs, 1)
+	assert.NotNil(t, subs[0].DeletedAt)
+
+	restored, err := repo.Restore(ctx, created.PublicID)
+	assert.NoError(t, err)
+	assert.Nil(t, restored.DeletedAt)
+	assert.Equal(t, []string{"fun"}, restored.Tags)
+
+	report, err = repo.CalculateCost(ctx, costFilter)
+	assert.NoError(t, err)
+	assert.Len(t, report.Subscriptions, 1)
+
+	assert.NoError(t, repo.Delete(ctx, created.ID, restored.Version))
+	purged, err := repo.Purge(ctx, time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	_, err = repo.Restore(ctx, created.PublicID)
+	assert.ErrorIs(t, err, errors_custom.ErrSubscriptionNotDeleted, "purged %d", purged)
+
+	_, err = repo.Purge(ctx, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	_, err = repo.Restore(ctx, created.PublicID)
+	assert.ErrorIs(t, err, errors_custom.ErrSubscriptionNotFound)
 }
 
 func TestSubscriptionRepository_GetAll(t *testing.T) {
 	repo := setupTestRepo(t)
-	ctx := context.Background()
+	ctx := testContext()
 	userID := uuid.New()
 
 	sub1 := &entity.Subscription{
-		ServiceName: "yandex",
-		Price:       299,
-		UserID:      userID,
-		StartDate:   time.Now(),
+		ServiceName:   "yandex",
+		Price:         money.FromMajor(299),
+		BillingPeriod: entity.BillingPeriodMonth,
+		BillingMonths: 1,
+		UserID:        userID,
+		StartDate:     time.Now(),
 	}
 	sub2 := &entity.Subscription{
-		ServiceName: "yandex plus",
-		Price:       399,
-		UserID:      userID,
-		StartDate:   time.Now(),
+		ServiceName:   "yandex plus",
+		Price:         money.FromMajor(399),
+		BillingPeriod: entity.BillingPeriodMonth,
+		BillingMonths: 1,
+		UserID:        userID,
+		StartDate:     time.Now(),
 	}
 	repo.Create(ctx, sub1)
 	repo.Create(ctx, sub2)
 
-	subs, err := repo.GetAll(ctx, &userID, nil, 10, 0)
+	subs, err := repo.GetAll(ctx, &entity.SubscriptionFilter{UserID: &userID, Limit: 10})
 	assert.NoError(t, err)
 	assert.GreaterOrEqual(t, len(subs), 2)
 }
 
 func TestSubscriptionRepository_CalculateCost(t *testing.T) {
 	repo := setupTestRepo(t)
-	ctx := context.Background()
+	ctx := testContext()
+	userID := uuid.New()
+	startDate := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)
+	subEnd := time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	sub := &entity.Subscription{
+		ServiceName:   "yandex",
+		Price:         money.FromMajor(400),
+		BillingPeriod: entity.BillingPeriodMonth,
+		BillingMonths: 1,
+		UserID:        userID,
+		StartDate:     time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:       &subEnd,
+	}
+	repo.Create(ctx, sub)
+
+	report, err := repo.CalculateCost(ctx, &entity.CostFilter{UserID: &userID, StartDate: startDate, EndDate: endDate})
+	assert.NoError(t, err)
+	assert.Len(t, report.Subscriptions, 1)
+	assert.Equal(t, sub.PublicID, report.Subscriptions[0].PublicID)
+	assert.Equal(t, 12, report.Subscriptions[0].Months)
+	assert.Equal(t, money.FromMajor(4800), report.Subscriptions[0].Cost)
+
+	report, err = repo.CalculateCost(ctx, &entity.CostFilter{
+		UserID:    &userID,
+		StartDate: startDate,
+		EndDate:   endDate,
+		GroupBy:   []string{entity.CostGroupByMonth},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, report.Groups, 12)
+	assert.Equal(t, money.FromMajor(400), report.Groups[0].Cost)
+}
+
+func TestSubscriptionRepository_CostTimeSeries(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := testContext()
+	userID := uuid.New()
+	startDate := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+	subEnd := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	sub := &entity.Subscription{
+		ServiceName:   "yandex",
+		Price:         money.FromMajor(400),
+		BillingPeriod: entity.BillingPeriodMonth,
+		BillingMonths: 1,
+		UserID:        userID,
+		StartDate:     time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:       &subEnd,
+	}
+	repo.Create(ctx, sub)
+
+	buckets, err := repo.CostTimeSeries(ctx, &entity.CostFilter{UserID: &userID, StartDate: startDate, EndDate: endDate})
+	assert.NoError(t, err)
+	assert.Len(t, buckets, 6)
+	assert.Equal(t, money.FromMajor(400), buckets[2].Cost)
+	assert.Equal(t, 1, buckets[2].Subscriptions)
+	assert.Equal(t, money.FromMajor(0), buckets[3].Cost)
+}
+
+func TestSubscriptionRepository_CalculateCost_AnnualPlan(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := testContext()
 	userID := uuid.New()
-	startDate := time.Now().AddDate(0, -1, 0)
-	endDate := time.Now().AddDate(0, 1, 0)
+	startDate := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)
 
 	sub := &entity.Subscription{
-		ServiceName: "yandex",
-		Price:       451,
-		UserID:      userID,
-		StartDate:   time.Now(),
+		ServiceName:   "yandex",
+		Price:         money.FromMajor(1200),
+		Currency:      entity.DefaultCurrency,
+		BillingPeriod: entity.BillingPeriodYear,
+		BillingMonths: 12,
+		UserID:        userID,
+		StartDate:     time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
 	}
 	repo.Create(ctx, sub)
 
-	cost, err := repo.CalculateCost(ctx, &userID, nil, &startDate, &endDate)
+	filter := &entity.CostFilter{
+		UserID:    &userID,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Currency:  entity.DefaultCurrency,
+		Mode:      entity.CostModeCharged,
+		GroupBy:   []string{entity.CostGroupByMonth},
+	}
+	report, err := repo.CalculateCost(ctx, filter)
+	assert.NoError(t, err)
+	assert.Len(t, report.Groups, 12)
+	assert.Equal(t, money.FromMajor(1200), report.Groups[2].Cost)
+	assert.Equal(t, money.FromMajor(0), report.Groups[3].Cost)
+
+	filter.Mode = entity.CostModeAmortized
+	report, err = repo.CalculateCost(ctx, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromMajor(100), report.Groups[3].Cost)
+}
+
+func TestSubscriptionRepository_PriceHistory(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := testContext()
+	userID := uuid.New()
+
+	sub := &entity.Subscription{
+		ServiceName:   "yandex",
+		Price:         money.FromMajor(300),
+		Currency:      entity.DefaultCurrency,
+		BillingPeriod: entity.BillingPeriodMonth,
+		BillingMonths: 1,
+		UserID:        userID,
+		StartDate:     time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+	created, err := repo.Create(ctx, sub)
+	assert.NoError(t, err)
+
+	_, err = repo.AddPrice(ctx, &entity.SubscriptionPrice{
+		SubscriptionID: created.ID,
+		Price:          money.FromMajor(400),
+		EffectiveFrom:  time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+
+	prices, err := repo.GetPrices(ctx, created.ID)
+	assert.NoError(t, err)
+	assert.Len(t, prices, 2)
+
+	stored, err := repo.GetByID(ctx, created.PublicID)
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromMajor(400), stored.Price)
+
+	report, err := repo.CalculateCost(ctx, &entity.CostFilter{
+		UserID:    &userID,
+		StartDate: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC),
+		Currency:  entity.DefaultCurrency,
+		Mode:      entity.CostModeCharged,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, report.Subscriptions, 1)
+	assert.Equal(t, money.FromMajor(3*300+3*400), report.Subscriptions[0].Cost)
+
+	// a scheduled price is not the current one yet
+	_, err = repo.AddPrice(ctx, &entity.SubscriptionPrice{
+		SubscriptionID: created.ID,
+		Price:          money.FromMajor(500),
+		EffectiveFrom:  time.Date(time.Now().Year()+1, time.January, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+	stored, err = repo.GetByID(ctx, created.PublicID)
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromMajor(400), stored.Price)
+
+	// a deleted subscription takes no new prices
+	assert.NoError(t, repo.Delete(ctx, stored.ID, stored.Version))
+	_, err = repo.AddPrice(ctx, &entity.SubscriptionPrice{
+		SubscriptionID: created.ID,
+		Price:          money.FromMajor(600),
+		EffectiveFrom:  time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.ErrorIs(t, err, errors_custom.ErrSubscriptionNotFound)
+}
+
+func TestSubscriptionRepository_CalculateCost_ActiveAt(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := testContext()
+	userID := uuid.New()
+	now := time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC)
+	ended := time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)
+	later := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, sub := range []*entity.Subscription{
+		{ServiceName: "active", StartDate: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), EndDate: &later},
+		{ServiceName: "ended", StartDate: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), EndDate: &ended},
+		{ServiceName: "future", StartDate: time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		sub.Price = money.FromMajor(100)
+		sub.Currency = entity.DefaultCurrency
+		sub.BillingPeriod = entity.BillingPeriodMonth
+		sub.BillingMonths = 1
+		sub.UserID = userID
+		repo.Create(ctx, sub)
+	}
+
+	report, err := repo.CalculateCost(ctx, &entity.CostFilter{
+		UserID:    &userID,
+		StartDate: now,
+		EndDate:   now.AddDate(0, 11, 0),
+		Currency:  entity.DefaultCurrency,
+		Mode:      entity.CostModeCharged,
+		GroupBy:   []string{entity.CostGroupByServiceName},
+		ActiveAt:  &now,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, report.Groups, 1)
+	assert.Equal(t, "active", *report.Groups[0].ServiceName)
+	assert.Equal(t, money.FromMajor(600), report.Groups[0].Cost)
+}
+
+func TestSubscriptionRepository_Rollup(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := testContext()
+	userID := uuid.New()
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	sub, err := repo.Create(ctx, &entity.Subscription{
+		ServiceName:   "rollup",
+		Price:         money.FromMajor(100),
+		Currency:      entity.DefaultCurrency,
+		BillingPeriod: entity.BillingPeriodMonth,
+		BillingMonths: 1,
+		UserID:        userID,
+		StartDate:     start,
+	})
+	assert.NoError(t, err)
+
+	_, err = repo.AddPrice(ctx, &entity.SubscriptionPrice{
+		SubscriptionID: sub.ID,
+		Price:          money.FromMajor(200),
+		EffectiveFrom:  time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+
+	_, err = repo.RebuildRollup(ctx, time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name string
+		end  time.Time
+		want money.Amount
+	}{
+		{name: "inside the horizon", end: time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC), want: money.FromMajor(1800)},
+		{name: "past the horizon", end: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), want: money.FromMajor(2000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := repo.CalculateCost(ctx, &entity.CostFilter{
+				UserID:    &userID,
+				StartDate: start,
+				EndDate:   tt.end,
+				Currency:  entity.DefaultCurrency,
+				Mode:      entity.CostModeCharged,
+				GroupBy:   []string{entity.CostGroupByUserID},
+			})
+			assert.NoError(t, err)
+			assert.Len(t, report.Groups, 1)
+			assert.Equal(t, tt.want, report.Groups[0].Cost)
+		})
+	}
+}
+
+func TestSubscriptionRepository_CategoriesAndTags(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := testContext()
+	userID := uuid.New()
+	startDate := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	newSub := func(serviceName, category string, price int64, tags ...string) *entity.Subscription {
+		return &entity.Subscription{
+			ServiceName:   serviceName,
+			Price:         money.FromMajor(price),
+			Currency:      entity.DefaultCurrency,
+			BillingPeriod: entity.BillingPeriodMonth,
+			BillingMonths: 1,
+			UserID:        userID,
+			StartDate:     startDate,
+			Category:      category,
+			Tags:          tags,
+		}
+	}
+
+	music, err := repo.Create(ctx, newSub("spotify", "music", 200, "family", "fun"))
 	assert.NoError(t, err)
-	assert.GreaterOrEqual(t, cost, 451)
+	_, err = repo.Create(ctx, newSub("okko", "streaming", 300, "fun"))
+	assert.NoError(t, err)
+	_, err = repo.Create(ctx, newSub("dropbox", "", 500))
+	assert.NoError(t, err)
+
+	_, err = repo.Create(ctx, newSub("unknown", "no-such-category", 100))
+	assert.ErrorIs(t, err, errors_custom.ErrCategoryNotFound)
+
+	found, err := repo.GetByID(ctx, music.PublicID)
+	assert.NoError(t, err)
+	assert.Equal(t, "music", found.Category)
+	assert.Equal(t, []string{"family", "fun"}, found.Tags)
+
+	tag := "fun"
+	subs, err := repo.GetAll(ctx, &entity.SubscriptionFilter{UserID: &userID, Tag: &tag, Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, subs, 2)
+
+	category := entity.DefaultCategory
+	subs, err = repo.GetAll(ctx, &entity.SubscriptionFilter{UserID: &userID, Category: &category, Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, subs, 1)
+	assert.Empty(t, subs[0].Tags)
+
+	report, err := repo.CalculateCost(ctx, &entity.CostFilter{
+		UserID:    &userID,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Currency:  entity.DefaultCurrency,
+		Tag:       &tag,
+		GroupBy:   []string{entity.CostGroupByCategory},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, report.Groups, 2)
+	assert.Equal(t, "music", *report.Groups[0].Category)
+	assert.Equal(t, money.FromMajor(600), report.Groups[0].Cost)
+
+	found.Tags = []string{"work"}
+	found.Category = "cloud"
+	_, err = repo.Update(ctx, found, nil)
+	assert.NoError(t, err)
+	found, err = repo.GetByID(ctx, music.PublicID)
+	assert.NoError(t, err)
+	assert.Equal(t, "cloud", found.Category)
+	assert.Equal(t, []string{"work"}, found.Tags)
+}
+
+func TestSubscriptionRepository_NoOverlap(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := testContext()
+	userID := uuid.New()
+	newSub := func(start time.Time, end *time.Time) *entity.Subscription {
+		return &entity.Subscription{
+			ServiceName:   "overlap",
+			Price:         money.FromMajor(299),
+			Currency:      entity.DefaultCurrency,
+			BillingPeriod: entity.BillingPeriodMonth,
+			BillingMonths: 1,
+			UserID:        userID,
+			StartDate:     start,
+			EndDate:       end,
+		}
+	}
+	jan := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	jun := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+	jul := time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)
+
+	first, err := repo.Create(ctx, newSub(jan, &jun))
+	assert.NoError(t, err)
+
+	// the end month is paid, so a subscription starting in June overlaps
+	_, err = repo.Create(ctx, newSub(jun, nil))
+	var conflict *errors_custom.SubscriptionConflictError
+	if assert.ErrorAs(t, err, &conflict) {
+		assert.Equal(t, first.ID, conflict.ConflictingID)
+		assert.Equal(t, first.PublicID, conflict.ConflictingPublicID)
+	}
+	assert.ErrorIs(t, err, errors_custom.ErrSubscriptionAlreadyFound)
+
+	second, err := repo.Create(ctx, newSub(jul, nil))
+	assert.NoError(t, err)
+
+	first.EndDate = nil
+	_, err = repo.Update(ctx, first, nil)
+	assert.ErrorAs(t, err, &conflict)
+	assert.Equal(t, second.ID, conflict.ConflictingID)
+	assert.Equal(t, second.PublicID, conflict.ConflictingPublicID)
+
+	// deleted subscriptions don't block, but can't be restored over an active one
+	assert.NoError(t, repo.Delete(ctx, second.ID, second.Version))
+	third, err := repo.Create(ctx, newSub(jul, nil))
+	assert.NoError(t, err)
+	_, err = repo.Restore(ctx, second.PublicID)
+	assert.ErrorAs(t, err, &conflict)
+	assert.Equal(t, third.ID, conflict.ConflictingID)
+	assert.Equal(t, third.PublicID, conflict.ConflictingPublicID)
+}
+
+func TestSubscriptionRepository_PauseResumeCancel(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := testContext()
+	userID := uuid.New()
+	month := func(m time.Month) time.Time { return time.Date(2025, m, 1, 0, 0, 0, 0, time.UTC) }
+
+	sub, err := repo.Create(ctx, &entity.Subscription{
+		ServiceName:   "gym",
+		Price:         money.FromMajor(100),
+		Currency:      entity.DefaultCurrency,
+		BillingPeriod: entity.BillingPeriodMonth,
+		BillingMonths: 1,
+		UserID:        userID,
+		StartDate:     month(time.January),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.SubscriptionStatusActive, sub.Status)
+
+	paused, err := repo.Pause(ctx, &entity.SubscriptionPause{SubscriptionID: sub.ID, PausedFrom: month(time.March)}, sub.Version)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.SubscriptionStatusPaused, paused.Status)
+
+	_, err = repo.Resume(ctx, sub.ID, month(time.June), sub.Version)
+	assert.ErrorIs(t, err, errors_custom.ErrVersionMismatch)
+	resumed, err := repo.Resume(ctx, sub.ID, month(time.June), paused.Version)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.SubscriptionStatusActive, resumed.Status)
+
+	// a second pause can't start inside the first one
+	_, err = repo.Pause(ctx, &entity.SubscriptionPause{SubscriptionID: sub.ID, PausedFrom: month(time.May)}, resumed.Version)
+	assert.ErrorIs(t, err, errors_custom.ErrInvalidRequest)
+
+	// March to May are paused
+	report, err := repo.CalculateCost(ctx, &entity.CostFilter{
+		UserID:    &userID,
+		StartDate: month(time.January),
+		EndDate:   month(time.December),
+		Currency:  entity.DefaultCurrency,
+		Mode:      entity.CostModeCharged,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, report.Subscriptions, 1)
+	assert.Equal(t, 9, report.Subscriptions[0].Months)
+	assert.Equal(t, money.FromMajor(900), report.Subscriptions[0].Cost)
+
+	cancelled, err := repo.Cancel(ctx, sub.ID, month(time.August), resumed.Version)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.SubscriptionStatusCancelled, cancelled.Status)
+	assert.Equal(t, month(time.August), cancelled.EndDate.UTC())
+
+	pauses, err := repo.GetPauses(ctx, sub.ID)
+	assert.NoError(t, err)
+	assert.Len(t, pauses, 1)
+	assert.Equal(t, month(time.June), pauses[0].ResumedFrom.UTC())
+}
+
+func TestSubscriptionRepository_ScheduledPauseStatus(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := testContext()
+	userID := uuid.New()
+	now := time.Now().UTC()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	sub, err := repo.Create(ctx, &entity.Subscription{
+		ServiceName:   "gym",
+		Price:         money.FromMajor(100),
+		Currency:      entity.DefaultCurrency,
+		BillingPeriod: entity.BillingPeriodMonth,
+		BillingMonths: 1,
+		UserID:        userID,
+		StartDate:     thisMonth.AddDate(0, -2, 0),
+	})
+	assert.NoError(t, err)
+	_, err = repo.Pause(ctx, &entity.SubscriptionPause{SubscriptionID: sub.ID, PausedFrom: thisMonth.AddDate(0, 1, 0)}, sub.Version)
+	assert.NoError(t, err)
+
+	// the pause starts next month, until then the subscription is active
+	statuses := map[string]int{
+		entity.SubscriptionStatusActive: 1,
+		entity.SubscriptionStatusPaused: 0,
+	}
+	for status, want := range statuses {
+		subs, err := repo.GetAll(ctx, &entity.SubscriptionFilter{UserID: &userID, Status: &status, Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, subs, want, status)
+	}
+}
+
+func TestSubscriptionRepository_GetRenewing(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := testContext()
+	userID := uuid.New()
+	month := func(m time.Month) time.Time { return time.Date(2025, m, 1, 0, 0, 0, 0, time.UTC) }
+
+	renewing, err := repo.Create(ctx, &entity.Subscription{
+		ServiceName:   "gym",
+		Price:         money.FromMajor(100),
+		Currency:      entity.DefaultCurrency,
+		BillingPeriod: entity.BillingPeriodMonth,
+		BillingMonths: 1,
+		BillingDay:    20,
+		AutoRenew:     true,
+		UserID:        userID,
+		StartDate:     month(time.January),
+	})
+	assert.NoError(t, err)
+	_, err = repo.Create(ctx, &entity.Subscription{
+		ServiceName:   "cinema",
+		Price:         money.FromMajor(300),
+		Currency:      entity.DefaultCurrency,
+		BillingPeriod: entity.BillingPeriodMonth,
+		BillingMonths: 1,
+		UserID:        userID,
+		StartDate:     month(time.January),
+	})
+	assert.NoError(t, err)
+	_, err = repo.AddPrice(ctx, &entity.SubscriptionPrice{SubscriptionID: renewing.ID, Price: money.FromMajor(150), EffectiveFrom: month(time.April)})
+	assert.NoError(t, err)
+
+	subs, err := repo.GetRenewing(ctx, &entity.ChargeFilter{UserID: &userID, From: month(time.March), To: month(time.April).AddDate(0, 0, 29)})
+	assert.NoError(t, err)
+	assert.Len(t, subs, 1, "only auto-renewing subscriptions are charged")
+	assert.Equal(t, 20, subs[0].BillingDay)
+	assert.Len(t, subs[0].Prices, 2)
+
+	dates := subs[0].ChargeDates(month(time.March), month(time.April).AddDate(0, 0, 29))
+	assert.Equal(t, []time.Time{time.Date(2025, time.March, 20, 0, 0, 0, 0, time.UTC), time.Date(2025, time.April, 20, 0, 0, 0, 0, time.UTC)}, dates)
+	assert.Equal(t, money.FromMajor(150), subs[0].PriceAt(dates[1]))
+}
+
+func TestSubscriptionRepository_MetadataFilter(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := testContext()
+	userID := uuid.New()
+
+	for _, plan := range []string{"family", "solo"} {
+		_, err := repo.Create(ctx, &entity.Subscription{
+			ServiceName:   "metadata " + plan,
+			Price:         money.FromMajor(100),
+			Currency:      entity.DefaultCurrency,
+			BillingPeriod: entity.BillingPeriodMonth,
+			BillingMonths: 1,
+			UserID:        userID,
+			StartDate:     time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+			Metadata:      entity.Metadata{"plan": plan, "cost_center": "R&D"},
+		})
+		assert.NoError(t, err)
+	}
+
+	subs, err := repo.GetAll(ctx, &entity.SubscriptionFilter{
+		UserID:   &userID,
+		Metadata: map[string]string{"plan": "family", "cost_center": "R&D"},
+		Limit:    10,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, subs, 1)
+	assert.Equal(t, entity.Metadata{"plan": "family", "cost_center": "R&D"}, subs[0].Metadata)
+
+	_, err = repo.GetAll(ctx, &entity.SubscriptionFilter{UserID: &userID, Metadata: map[string]string{"plan": "team"}, Limit: 10})
+	assert.ErrorIs(t, err, errors_custom.ErrNoSubscriptionsFound)
+}
+
+func TestSubscriptionRepository_Batch(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := testContext()
+	userID := uuid.New()
+	newSub := func(service string) *entity.Subscription {
+		return &entity.Subscription{
+			ServiceName:   service,
+			Price:         money.FromMajor(299),
+			BillingPeriod: entity.BillingPeriodMonth,
+			BillingMonths: 1,
+			UserID:        userID,
+			StartDate:     time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+		}
+	}
+	existing, err := repo.Create(ctx, newSub("yandex"))
+	assert.NoError(t, err)
+
+	// the second create overlaps the existing subscription
+	items := []*entity.SubscriptionBatchItem{
+		{Action: entity.BatchActionCreate, Subscription: newSub("netflix")},
+		{Action: entity.BatchActionCreate, Subscription: newSub("yandex")},
+	}
+	assert.ErrorIs(t, repo.Batch(ctx, items, true), errors_custom.ErrBatchRolledBack)
+	assert.NoError(t, items[0].Err)
+	assert.ErrorIs(t, items[1].Err, errors_custom.ErrSubscriptionAlreadyFound)
+	_, err = repo.GetByID(ctx, items[0].Subscription.PublicID)
+	assert.ErrorIs(t, err, errors_custom.ErrSubscriptionNotFound)
+
+	items = []*entity.SubscriptionBatchItem{
+		{Action: entity.BatchActionCreate, Subscription: newSub("netflix")},
+		{Action: entity.BatchActionCreate, Subscription: newSub("yandex")},
+		{Action: entity.BatchActionDelete, ID: existing.PublicID},
+	}
+	assert.NoError(t, repo.Batch(ctx, items, false))
+	assert.NoError(t, items[0].Err)
+	assert.ErrorIs(t, items[1].Err, errors_custom.ErrSubscriptionAlreadyFound)
+	assert.NoError(t, items[2].Err)
+	_, err = repo.GetByID(ctx, items[0].Subscription.PublicID)
+	assert.NoError(t, err)
+	_, err = repo.GetByID(ctx, existing.PublicID)
+	assert.ErrorIs(t, err, errors_custom.ErrSubscriptionNotFound)
+}
+
+func TestSubscriptionRepository_BatchWithinTransaction(t *testing.T) {
+	client, err := go_postgres.NewTestClient()
+	assert.NoError(t, err)
+	repo := NewSubscriptionsRepository(client)
+	services := NewServicesRepository(client)
+	ctx := testContext()
+	suffix := uuid.NewString()
+	newSub := func(service string) *entity.Subscription {
+		return &entity.Subscription{
+			ServiceName:   service,
+			Price:         money.FromMajor(299),
+			BillingPeriod: entity.BillingPeriodMonth,
+			BillingMonths: 1,
+			UserID:        uuid.New(),
+			StartDate:     time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+		}
+	}
+	existing, err := repo.Create(ctx, newSub("Batch "+suffix))
+	assert.NoError(t, err)
+
+	// an update and a delete of the same subscription see each other
+	version := existing.Version
+	items := []*entity.SubscriptionBatchItem{
+		{Action: entity.BatchActionUpdate, ID: existing.PublicID, Version: &version,
+			Apply: func(sub *entity.Subscription) (*entity.SubscriptionPrice, error) {
+				sub.AutoRenew = !sub.AutoRenew
+				return &entity.SubscriptionPrice{SubscriptionID: sub.ID, Price: money.FromMajor(399), EffectiveFrom: sub.StartDate}, nil
+			}},
+		{Action: entity.BatchActionDelete, ID: existing.PublicID},
+	}
+	assert.NoError(t, repo.Batch(ctx, items, true))
+	assert.NoError(t, items[0].Err)
+	assert.NoError(t, items[1].Err)
+	assert.Equal(t, money.FromMajor(399), items[0].Subscription.Price)
+	assert.Equal(t, version+1, items[0].Subscription.Version)
+	_, err = repo.GetByID(ctx, existing.PublicID)
+	assert.ErrorIs(t, err, errors_custom.ErrSubscriptionNotFound)
+
+	// a rolled back batch adds no service
+	newService := "Batch New " + suffix
+	items = []*entity.SubscriptionBatchItem{
+		{Action: entity.BatchActionCreate, Subscription: newSub(newService)},
+		{Action: entity.BatchActionDelete, ID: existing.PublicID},
+	}
+	assert.ErrorIs(t, repo.Batch(ctx, items, true), errors_custom.ErrBatchRolledBack)
+	assert.ErrorIs(t, items[1].Err, errors_custom.ErrSubscriptionNotFound)
+	_, err = services.Resolve(ctx, utils.NormalizeServiceName(newService))
+	assert.ErrorIs(t, err, errors_custom.ErrServiceNotFound)
+
+	// names are resolved through the aliases of the catalog
+	service, err := services.Create(ctx, &entity.Service{
+		Name:           "Batch Aliased " + suffix,
+		NormalizedName: utils.NormalizeServiceName("Batch Aliased " + suffix),
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	})
+	assert.NoError(t, err)
+	aliasName := "Batch Alias " + suffix
+	_, err = services.AddAlias(ctx, &entity.ServiceAlias{
+		ServiceID:       service.ID,
+		Alias:           aliasName,
+		NormalizedAlias: utils.NormalizeServiceName(aliasName),
+		CreatedAt:       time.Now(),
+	})
+	assert.NoError(t, err)
+	items = []*entity.SubscriptionBatchItem{{Action: entity.BatchActionCreate, Subscription: newSub(aliasName)}}
+	assert.NoError(t, repo.Batch(ctx, items, true))
+	assert.Equal(t, service.ID, items[0].Subscription.ServiceID)
+	assert.Equal(t, service.Name, items[0].Subscription.ServiceName)
+}
+
+func TestSubscriptionRepository_Import(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := testContext()
+	userID := uuid.New()
+	startDate := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	existing, err := repo.Create(ctx, &entity.Subscription{
+		ServiceName:   "yandex",
+		Price:         money.FromMajor(299),
+		BillingPeriod: entity.BillingPeriodMonth,
+		BillingMonths: 1,
+		UserID:        userID,
+		StartDate:     startDate,
+	})
+	assert.NoError(t, err)
+
+	rows := func() func() (*entity.SubscriptionImportRow, error) {
+		subs := []*entity.SubscriptionImportRow{
+			{Line: 2, Subscription: &entity.Subscription{ServiceName: "Import Service " + userID.String()}},
+			{Line: 3, Subscription: &entity.Subscription{ServiceID: existing.ServiceID, ServiceName: existing.ServiceName}},
+			{Line: 4, Subscription: &entity.Subscription{ServiceName: "import service " + userID.String()}},
+		}
+		return func() (*entity.SubscriptionImportRow, error) {
+			if len(subs) == 0 {
+				return nil, nil
+			}
+			row := subs[0]
+			subs = subs[1:]
+			row.Subscription.Price = money.FromMajor(199)
+			row.Subscription.Currency = entity.DefaultCurrency
+			row.Subscription.BillingPeriod = entity.BillingPeriodMonth
+			row.Subscription.BillingMonths = 1
+			row.Subscription.AutoRenew = true
+			row.Subscription.UserID = userID
+			row.Subscription.StartDate = startDate
+			row.Subscription.CreatedAt = time.Now()
+			return row, nil
+		}
+	}
+
+	// line 3 overlaps the existing subscription, line 4 the new service of line 2
+	report, err := repo.Import(ctx, rows(), true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), report.Imported)
+	if assert.Len(t, report.Rejected, 2) {
+		assert.Equal(t, 3, report.Rejected[0].Line)
+		var conflict *errors_custom.SubscriptionConflictError
+		assert.ErrorAs(t, report.Rejected[0].Err, &conflict)
+		assert.Equal(t, existing.PublicID, conflict.ConflictingPublicID)
+		assert.Equal(t, 4, report.Rejected[1].Line)
+		assert.ErrorIs(t, report.Rejected[1].Err, errors_custom.ErrSubscriptionAlreadyFound)
+	}
+	subs, err := repo.GetAll(ctx, &entity.SubscriptionFilter{UserID: &userID, Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, subs, 1)
+
+	report, err = repo.Import(ctx, rows(), false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), report.Imported)
+	subs, err = repo.GetAll(ctx, &entity.SubscriptionFilter{UserID: &userID, Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, subs, 2)
+
+	// the imported subscription is in the audit trail like a created one
+	client, err := go_postgres.NewTestClient()
+	assert.NoError(t, err)
+	action := entity.AuditActionCreate
+	entries, err := NewAuditRepository(client).GetAll(ctx, &entity.AuditFilter{UserID: &userID, Action: &action, Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	for _, entry := range entries {
+		assert.Nil(t, entry.Before)
+		assert.NotNil(t, entry.After)
+	}
 }
@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/domain/ports/repository"
+	errors_custom "AggregationService/internal/errors"
+	"AggregationService/internal/infrastructure/database/go_postgres"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+)
+
+const (
+	tableTenants       = "tenants"
+	tableTenantAPIKeys = "tenant_api_keys"
+)
+
+// tenantRollupMonths is how far ahead the rollup of a new tenant reaches, the
+// same as the default of cmd/rollup.
+const tenantRollupMonths = 60
+
+// tenantsRepository reads the tenants themselves. Their tables have no
+// row-level security: the tenant of a request is looked up before it is known.
+type tenantsRepository struct {
+	client *go_postgres.PostgresClient
+}
+
+func NewTenantsRepository(client *go_postgres.PostgresClient) repository.ITenantRepository {
+	return &tenantsRepository{client: client}
+}
+
+// Create stores a tenant with its first API key, of which only the hash is
+// kept, and gives it the default categories and a rollup horizon.
+func (t *tenantsRepository) Create(ctx context.Context, tenant *entity.Tenant, keyHash string) (*entity.Tenant, error) {
+	const op = "repository.postgres.tenants.Create"
+
+	if tenant.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return nil, fmt.Errorf("%s: to generate id: %w", op, err)
+		}
+		tenant.ID = id
+	}
+
+	query, args, err := t.client.Builder.
+		Insert(tableTenants).
+		Columns("id", "name", "created_at").
+		Values(tenant.ID, tenant.Name, tenant.CreatedAt).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+	keyQuery, keyArgs, err := t.client.Builder.
+		Insert(tableTenantAPIKeys).
+		Columns("key_hash", "tenant_id", "created_at").
+		Values(keyHash, tenant.ID, tenant.CreatedAt).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	tx, err := t.client.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: begin tx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: to insert: %w", op, err)
+	}
+	if _, err = tx.ExecContext(ctx, keyQuery, keyArgs...); err != nil {
+		return nil, fmt.Errorf("%s: to insert key: %w", op, err)
+	}
+	if err = go_postgres.SetTenant(ctx, tx, tenant.ID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err = tx.ExecContext(ctx, "SELECT seed_tenant_categories()"); err != nil {
+		return nil, fmt.Errorf("%s: to seed categories: %w", op, err)
+	}
+	// without a horizon the cost endpoints would never read the rollup of the
+	// tenant, see rollupCovers
+	horizon := tenant.CreatedAt.AddDate(0, tenantRollupMonths, 0)
+	if _, err = tx.ExecContext(ctx, "SELECT rebuild_subscription_rollup($1)", horizon); err != nil {
+		return nil, fmt.Errorf("%s: to start rollup: %w", op, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return tenant, nil
+}
+
+// GetByKeyHash returns the tenant of an API key that is not revoked.
+func (t *tenantsRepository) GetByKeyHash(ctx context.Context, keyHash string) (*entity.Tenant, error) {
+	const op = "repository.postgres.tenants.GetByKeyHash"
+
+	query, args, err := t.client.Builder.
+		Select("t.id", "t.name", "t.created_at").
+		From(tableTenants + " t").
+		Join(tableTenantAPIKeys + " k ON k.tenant_id = t.id").
+		Where(squirrel.Eq{"k.key_hash": keyHash, "k.revoked_at": nil}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	var tenant entity.Tenant
+	if err = t.client.DB.GetContext(ctx, &tenant, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors_custom.ErrTenantNotFound
+		}
+		return nil, fmt.Errorf("%s: query error: %w", op, err)
+	}
+	return &tenant, nil
+}
+
+func (t *tenantsRepository) GetAll(ctx context.Context) ([]*entity.Tenant, error) {
+	const op = "repository.postgres.tenants.GetAll"
+
+	query, args, err := t.client.Builder.
+		Select("id", "name", "created_at").
+		From(tableTenants).
+		OrderBy("created_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: to sql: %w", op, err)
+	}
+
+	tenants := make([]*entity.Tenant, 0)
+	if err = t.client.DB.SelectContext(ctx, &tenants, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: select: %w", op, err)
+	}
+	return tenants, nil
+}
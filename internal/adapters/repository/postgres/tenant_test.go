@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"AggregationService/internal/domain/models/entity"
+	errors_custom "AggregationService/internal/errors"
+	"AggregationService/internal/infrastructure/database/go_postgres"
+	"AggregationService/internal/pkg/money"
+	"AggregationService/internal/pkg/tenant"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantRepository_CreateAndGetByKeyHash(t *testing.T) {
+	client, err := go_postgres.NewTestClient()
+	assert.NoError(t, err)
+	repo := NewTenantsRepository(client)
+	ctx := context.Background()
+
+	keyHash := randomKeyHash()
+	created, err := repo.Create(ctx, &entity.Tenant{Name: "acme", CreatedAt: time.Now()}, keyHash)
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, created.ID)
+
+	found, err := repo.GetByKeyHash(ctx, keyHash)
+	assert.NoError(t, err)
+	assert.Equal(t, created.ID, found.ID)
+
+	_, err = repo.GetByKeyHash(ctx, "unknown")
+	assert.ErrorIs(t, err, errors_custom.ErrTenantNotFound)
+
+	categories, err := NewCategoriesRepository(client).GetAll(tenant.ContextWithID(ctx, created.ID))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, categories)
+
+	subs := NewSubscriptionsRepository(client).(*subscriptionsRepository)
+	covers, err := subs.rollupCovers(tenant.ContextWithID(ctx, created.ID), &entity.CostFilter{EndDate: time.Now()})
+	assert.NoError(t, err)
+	assert.True(t, covers)
+}
+
+func TestTenantRepository_Isolation(t *testing.T) {
+	client, err := go_postgres.NewTestClient()
+	assert.NoError(t, err)
+	tenants := NewTenantsRepository(client)
+	subs := NewSubscriptionsRepository(client)
+
+	other, err := tenants.Create(context.Background(), &entity.Tenant{Name: "other", CreatedAt: time.Now()},
+		randomKeyHash())
+	assert.NoError(t, err)
+	otherCtx := tenant.ContextWithID(context.Background(), other.ID)
+
+	created, err := subs.Create(testContext(), &entity.Subscription{
+		ServiceName:   "yandex",
+		Price:         money.FromMajor(299),
+		BillingPeriod: entity.BillingPeriodMonth,
+		BillingMonths: 1,
+		UserID:        uuid.New(),
+		StartDate:     time.Now(),
+	})
+	assert.NoError(t, err)
+
+	_, err = subs.GetByID(otherCtx, created.PublicID)
+	assert.ErrorIs(t, err, errors_custom.ErrSubscriptionNotFound)
+	_, err = subs.GetPublicID(otherCtx, created.ID)
+	assert.ErrorIs(t, err, errors_custom.ErrSubscriptionNotFound)
+	assert.ErrorIs(t, subs.Delete(otherCtx, created.ID, created.Version), errors_custom.ErrSubscriptionNotFound)
+
+	// row-level security hides the rows even from a query without the tenant filter
+	var count int
+	err = client.InTx(otherCtx, func(tx *sqlx.Tx) error {
+		return tx.GetContext(otherCtx, &count, "SELECT COUNT(*) FROM subscriptions WHERE id = $1", created.ID)
+	})
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+
+	_, err = subs.GetByID(context.Background(), created.PublicID)
+	assert.ErrorIs(t, err, go_postgres.ErrNoTenant)
+}
+
+func randomKeyHash() string {
+	sum := sha256.Sum256([]byte(uuid.NewString()))
+	return hex.EncodeToString(sum[:])
+}
@@ -21,6 +21,13 @@ import (
 
 const environment = "ENV"
 
+const (
+	requestTimeout = 5 * time.Second
+	// bulkRequestTimeout is the timeout of the requests that stream a whole
+	// file or run many changes, they take longer than one change
+	bulkRequestTimeout = 30 * time.Second
+)
+
 type App struct {
 	httpServer *http.Server
 	provider   *Provider
@@ -28,8 +35,16 @@ type App struct {
 
 func New(ctx context.Context, cfg *config.Config, provider *Provider) *App {
 	subHandler := provider.Handler(ctx)
+	rateHandler := provider.ExchangeRateHandler(ctx)
+	budgetHandler := provider.BudgetHandler(ctx)
+	serviceHandler := provider.ServiceHandler(ctx)
+	categoryHandler := provider.CategoryHandler(ctx)
+	auditHandler := provider.AuditHandler(ctx)
+	idempotency := provider.IdempotencyHandler(ctx)
+	tenantHandler := provider.TenantHandler(ctx)
 
 	swaggerRouter := chi.NewRouter()
+	swaggerRouter.Use(middleware.Timeout(requestTimeout))
 	swaggerRouter.Get("/*", httpSwagger.Handler(
 		httpSwagger.URL("/swagger/doc.json"),
 	))
@@ -38,21 +53,89 @@ func New(ctx context.Context, cfg *config.Config, provider *Provider) *App {
 
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
+	r.Use(middleware2.AuditMW)
 	r.Use(middleware2.LoggerMW)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(5 * time.Second))
 	r.Use(middleware2.HeadersMiddleware)
 
 	r.Mount("/swagger", swaggerRouter)
 
-	r.Route("/subscriptions", func(r chi.Router) {
-		r.Post("/", subHandler.Create)
-		r.Get("/", subHandler.GetAll)
-		r.Get("/cost", subHandler.CalculateCost)
-		r.Route("/{id}", func(r chi.Router) {
-			r.Get("/", subHandler.GetByID)
-			r.Put("/", subHandler.Update)
-			r.Delete("/", subHandler.Delete)
+	// everything but the docs belongs to the tenant of the API key
+	r.Group(func(r chi.Router) {
+		r.Use(tenantHandler.Authenticate)
+
+		// the timeouts nest, so the bulk requests are kept out of the
+		// group with the short one
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Timeout(bulkRequestTimeout))
+
+			r.Post("/subscriptions/batch", subHandler.Batch)
+			r.Post("/subscriptions/import", subHandler.Import)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Timeout(requestTimeout))
+
+			r.Route("/subscriptions", func(r chi.Router) {
+				r.With(idempotency.Wrap).Post("/", subHandler.Create)
+				r.Get("/", subHandler.GetAll)
+				r.Get("/cost", subHandler.CalculateCost)
+				r.Get("/cost/timeseries", subHandler.CostTimeSeries)
+				r.Get("/forecast", subHandler.Forecast)
+				r.Get("/upcoming", subHandler.Upcoming)
+				r.Route("/{id}", func(r chi.Router) {
+					r.Get("/", subHandler.GetByID)
+					r.Put("/", subHandler.Update)
+					r.Delete("/", subHandler.Delete)
+					r.Post("/restore", subHandler.Restore)
+					r.Post("/pause", subHandler.Pause)
+					r.Post("/resume", subHandler.Resume)
+					r.Post("/cancel", subHandler.Cancel)
+					r.Get("/pauses", subHandler.GetPauses)
+					r.Get("/history", auditHandler.History)
+					r.Post("/prices", subHandler.AddPrice)
+					r.Get("/prices", subHandler.GetPrices)
+				})
+			})
+
+			r.Route("/services", func(r chi.Router) {
+				r.Post("/", serviceHandler.Create)
+				r.Get("/", serviceHandler.GetAll)
+				r.Get("/duplicates", serviceHandler.Duplicates)
+				r.Route("/{id}", func(r chi.Router) {
+					r.Get("/", serviceHandler.GetByID)
+					r.Put("/", serviceHandler.Update)
+					r.Delete("/", serviceHandler.Delete)
+					r.Post("/merge", serviceHandler.Merge)
+					r.Post("/aliases", serviceHandler.AddAlias)
+					r.Get("/aliases", serviceHandler.GetAliases)
+					r.Delete("/aliases/{aliasId}", serviceHandler.DeleteAlias)
+				})
+			})
+
+			r.Route("/categories", func(r chi.Router) {
+				r.Post("/", categoryHandler.Create)
+				r.Get("/", categoryHandler.GetAll)
+			})
+
+			r.Route("/budgets", func(r chi.Router) {
+				r.Post("/", budgetHandler.Create)
+				r.Get("/", budgetHandler.GetAll)
+				r.Route("/{id}", func(r chi.Router) {
+					r.Get("/", budgetHandler.GetByID)
+					r.Put("/", budgetHandler.Update)
+					r.Delete("/", budgetHandler.Delete)
+					r.Get("/status", budgetHandler.Status)
+				})
+			})
+
+			r.Get("/audit", auditHandler.GetAll)
+
+			r.Route("/exchange-rates", func(r chi.Router) {
+				r.Post("/", rateHandler.Create)
+				r.Get("/", rateHandler.GetAll)
+				r.Delete("/{id}", rateHandler.Delete)
+			})
 		})
 	})
 
@@ -1,11 +1,20 @@
 package app
 
 import (
+	"AggregationService/internal/adapters/events"
 	"AggregationService/internal/adapters/http/handlers"
 	"AggregationService/internal/adapters/repository/postgres"
 	"AggregationService/internal/converters"
+	portevents "AggregationService/internal/domain/ports/events"
 	"AggregationService/internal/domain/ports/repository"
+	"AggregationService/internal/domain/usecase/audit_usecase"
+	"AggregationService/internal/domain/usecase/budget_usecase"
+	"AggregationService/internal/domain/usecase/category_usecase"
+	"AggregationService/internal/domain/usecase/exchange_rate_usecase"
+	"AggregationService/internal/domain/usecase/idempotency_usecase"
+	"AggregationService/internal/domain/usecase/service_usecase"
 	"AggregationService/internal/domain/usecase/subscription_usecase"
+	"AggregationService/internal/domain/usecase/tenant_usecase"
 	"AggregationService/internal/infrastructure/database/go_postgres"
 	"AggregationService/internal/infrastructure/server"
 	"AggregationService/internal/pkg/validation"
@@ -21,6 +30,40 @@ type Provider struct {
 	handler      *handlers.SubscriptionHandler
 	usecase      subscription_usecase.ISubscriptionUseCase
 	validator    *validation.Validator
+
+	exchangeRateConverter *converters.ExchangeRateConverter
+	exchangeRateRepo      repository.IExchangeRateRepository
+	exchangeRateUseCase   exchange_rate_usecase.IExchangeRateUseCase
+	exchangeRateHandler   *handlers.ExchangeRateHandler
+
+	budgetConverter      *converters.BudgetConverter
+	budgetRepo           repository.IBudgetRepository
+	budgetAlertPublisher portevents.IBudgetAlertPublisher
+	budgetUseCase        budget_usecase.IBudgetUseCase
+	budgetHandler        *handlers.BudgetHandler
+
+	serviceConverter *converters.ServiceConverter
+	serviceRepo      repository.IServiceRepository
+	serviceUseCase   service_usecase.IServiceUseCase
+	serviceHandler   *handlers.ServiceHandler
+
+	categoryConverter *converters.CategoryConverter
+	categoryRepo      repository.ICategoryRepository
+	categoryUseCase   category_usecase.ICategoryUseCase
+	categoryHandler   *handlers.CategoryHandler
+
+	auditConverter *converters.AuditConverter
+	auditRepo      repository.IAuditRepository
+	auditUseCase   audit_usecase.IAuditUseCase
+	auditHandler   *handlers.AuditHandler
+
+	idempotencyRepo    repository.IIdempotencyRepository
+	idempotencyUseCase idempotency_usecase.IIdempotencyUseCase
+	idempotencyHandler *handlers.IdempotencyHandler
+
+	tenantRepo    repository.ITenantRepository
+	tenantUseCase tenant_usecase.ITenantUseCase
+	tenantHandler *handlers.TenantHandler
 }
 
 func NewAppProvider() *Provider {
@@ -60,8 +103,10 @@ func (p *Provider) UseCase(ctx context.Context) subscription_usecase.ISubscripti
 	if p.usecase == nil {
 		p.usecase = subscription_usecase.New(
 			p.SubscriptionRepo(ctx),
+			p.ServiceRepo(ctx),
 			p.Validator(),
 			p.Converter(),
+			p.BudgetUseCase(ctx),
 		)
 	}
 	return p.usecase
@@ -98,3 +143,215 @@ func (p *Provider) Validator() *validation.Validator {
 	}
 	return p.validator
 }
+
+func (p *Provider) ExchangeRateRepo(ctx context.Context) repository.IExchangeRateRepository {
+	if p.exchangeRateRepo == nil {
+		p.exchangeRateRepo = postgres.NewExchangeRatesRepository(p.PGClient(ctx))
+	}
+	return p.exchangeRateRepo
+}
+
+func (p *Provider) ExchangeRateUseCase(ctx context.Context) exchange_rate_usecase.IExchangeRateUseCase {
+	if p.exchangeRateUseCase == nil {
+		p.exchangeRateUseCase = exchange_rate_usecase.New(
+			p.ExchangeRateRepo(ctx),
+			p.Validator(),
+			p.ExchangeRateConverter(),
+		)
+	}
+	return p.exchangeRateUseCase
+}
+
+func (p *Provider) ExchangeRateHandler(ctx context.Context) *handlers.ExchangeRateHandler {
+	if p.exchangeRateHandler == nil {
+		p.exchangeRateHandler = handlers.NewExchangeRateHandler(p.ExchangeRateUseCase(ctx))
+	}
+	return p.exchangeRateHandler
+}
+
+func (p *Provider) ExchangeRateConverter() *converters.ExchangeRateConverter {
+	if p.exchangeRateConverter == nil {
+		p.exchangeRateConverter = converters.NewExchangeRateConverter()
+	}
+	return p.exchangeRateConverter
+}
+
+func (p *Provider) BudgetRepo(ctx context.Context) repository.IBudgetRepository {
+	if p.budgetRepo == nil {
+		p.budgetRepo = postgres.NewBudgetsRepository(p.PGClient(ctx))
+	}
+	return p.budgetRepo
+}
+
+func (p *Provider) BudgetAlertPublisher() portevents.IBudgetAlertPublisher {
+	if p.budgetAlertPublisher == nil {
+		p.budgetAlertPublisher = events.NewLogAlertPublisher()
+	}
+	return p.budgetAlertPublisher
+}
+
+func (p *Provider) BudgetUseCase(ctx context.Context) budget_usecase.IBudgetUseCase {
+	if p.budgetUseCase == nil {
+		p.budgetUseCase = budget_usecase.New(
+			p.BudgetRepo(ctx),
+			p.SubscriptionRepo(ctx),
+			p.ServiceRepo(ctx),
+			p.BudgetAlertPublisher(),
+			p.Validator(),
+			p.BudgetConverter(),
+		)
+	}
+	return p.budgetUseCase
+}
+
+func (p *Provider) BudgetHandler(ctx context.Context) *handlers.BudgetHandler {
+	if p.budgetHandler == nil {
+		p.budgetHandler = handlers.NewBudgetHandler(p.BudgetUseCase(ctx))
+	}
+	return p.budgetHandler
+}
+
+func (p *Provider) BudgetConverter() *converters.BudgetConverter {
+	if p.budgetConverter == nil {
+		p.budgetConverter = converters.NewBudgetConverter()
+	}
+	return p.budgetConverter
+}
+
+func (p *Provider) ServiceRepo(ctx context.Context) repository.IServiceRepository {
+	if p.serviceRepo == nil {
+		p.serviceRepo = postgres.NewServicesRepository(p.PGClient(ctx))
+	}
+	return p.serviceRepo
+}
+
+func (p *Provider) ServiceUseCase(ctx context.Context) service_usecase.IServiceUseCase {
+	if p.serviceUseCase == nil {
+		p.serviceUseCase = service_usecase.New(
+			p.ServiceRepo(ctx),
+			p.Validator(),
+			p.ServiceConverter(),
+		)
+	}
+	return p.serviceUseCase
+}
+
+func (p *Provider) ServiceHandler(ctx context.Context) *handlers.ServiceHandler {
+	if p.serviceHandler == nil {
+		p.serviceHandler = handlers.NewServiceHandler(p.ServiceUseCase(ctx))
+	}
+	return p.serviceHandler
+}
+
+func (p *Provider) ServiceConverter() *converters.ServiceConverter {
+	if p.serviceConverter == nil {
+		p.serviceConverter = converters.NewServiceConverter()
+	}
+	return p.serviceConverter
+}
+
+func (p *Provider) CategoryRepo(ctx context.Context) repository.ICategoryRepository {
+	if p.categoryRepo == nil {
+		p.categoryRepo = postgres.NewCategoriesRepository(p.PGClient(ctx))
+	}
+	return p.categoryRepo
+}
+
+func (p *Provider) CategoryUseCase(ctx context.Context) category_usecase.ICategoryUseCase {
+	if p.categoryUseCase == nil {
+		p.categoryUseCase = category_usecase.New(
+			p.CategoryRepo(ctx),
+			p.Validator(),
+			p.CategoryConverter(),
+		)
+	}
+	return p.categoryUseCase
+}
+
+func (p *Provider) CategoryHandler(ctx context.Context) *handlers.CategoryHandler {
+	if p.categoryHandler == nil {
+		p.categoryHandler = handlers.NewCategoryHandler(p.CategoryUseCase(ctx))
+	}
+	return p.categoryHandler
+}
+
+func (p *Provider) CategoryConverter() *converters.CategoryConverter {
+	if p.categoryConverter == nil {
+		p.categoryConverter = converters.NewCategoryConverter()
+	}
+	return p.categoryConverter
+}
+
+func (p *Provider) AuditRepo(ctx context.Context) repository.IAuditRepository {
+	if p.auditRepo == nil {
+		p.auditRepo = postgres.NewAuditRepository(p.PGClient(ctx))
+	}
+	return p.auditRepo
+}
+
+func (p *Provider) AuditUseCase(ctx context.Context) audit_usecase.IAuditUseCase {
+	if p.auditUseCase == nil {
+		p.auditUseCase = audit_usecase.New(
+			p.AuditRepo(ctx),
+			p.Validator(),
+			p.AuditConverter(),
+		)
+	}
+	return p.auditUseCase
+}
+
+func (p *Provider) AuditHandler(ctx context.Context) *handlers.AuditHandler {
+	if p.auditHandler == nil {
+		p.auditHandler = handlers.NewAuditHandler(p.AuditUseCase(ctx))
+	}
+	return p.auditHandler
+}
+
+func (p *Provider) AuditConverter() *converters.AuditConverter {
+	if p.auditConverter == nil {
+		p.auditConverter = converters.NewAuditConverter()
+	}
+	return p.auditConverter
+}
+
+func (p *Provider) IdempotencyRepo(ctx context.Context) repository.IIdempotencyRepository {
+	if p.idempotencyRepo == nil {
+		p.idempotencyRepo = postgres.NewIdempotencyRepository(p.PGClient(ctx))
+	}
+	return p.idempotencyRepo
+}
+
+func (p *Provider) IdempotencyUseCase(ctx context.Context) idempotency_usecase.IIdempotencyUseCase {
+	if p.idempotencyUseCase == nil {
+		p.idempotencyUseCase = idempotency_usecase.New(p.IdempotencyRepo(ctx), idempotency_usecase.DefaultTTL, idempotency_usecase.DefaultLease)
+	}
+	return p.idempotencyUseCase
+}
+
+func (p *Provider) IdempotencyHandler(ctx context.Context) *handlers.IdempotencyHandler {
+	if p.idempotencyHandler == nil {
+		p.idempotencyHandler = handlers.NewIdempotencyHandler(p.IdempotencyUseCase(ctx))
+	}
+	return p.idempotencyHandler
+}
+
+func (p *Provider) TenantRepo(ctx context.Context) repository.ITenantRepository {
+	if p.tenantRepo == nil {
+		p.tenantRepo = postgres.NewTenantsRepository(p.PGClient(ctx))
+	}
+	return p.tenantRepo
+}
+
+func (p *Provider) TenantUseCase(ctx context.Context) tenant_usecase.ITenantUseCase {
+	if p.tenantUseCase == nil {
+		p.tenantUseCase = tenant_usecase.New(p.TenantRepo(ctx), p.Validator())
+	}
+	return p.tenantUseCase
+}
+
+func (p *Provider) TenantHandler(ctx context.Context) *handlers.TenantHandler {
+	if p.tenantHandler == nil {
+		p.tenantHandler = handlers.NewTenantHandler(p.TenantUseCase(ctx))
+	}
+	return p.tenantHandler
+}
@@ -0,0 +1,63 @@
+package converters
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	"github.com/google/uuid"
+)
+
+// defaultAuditLimit is the page size of audit queries without a limit.
+const defaultAuditLimit = 100
+
+type AuditConverter struct {
+}
+
+func NewAuditConverter() *AuditConverter {
+	return &AuditConverter{}
+}
+
+func (c *AuditConverter) ToAuditFilter(req *dto.ListAuditRequest) *entity.AuditFilter {
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultAuditLimit
+	}
+	return &entity.AuditFilter{
+		SubscriptionID:       req.SubscriptionID,
+		SubscriptionPublicID: req.SubscriptionPublicID,
+		UserID:               req.UserID,
+		Action:               req.Action,
+		Actor:                req.Actor,
+		RequestID:            req.RequestID,
+		From:                 req.From,
+		To:                   req.To,
+		Limit:                limit,
+		Offset:               req.Offset,
+	}
+}
+
+func (c *AuditConverter) ToAuditEntryDTO(entry *entity.AuditEntry) *dto.AuditEntryResponse {
+	var publicID *uuid.UUID
+	if entry.SubscriptionPublicID != uuid.Nil {
+		publicID = &entry.SubscriptionPublicID
+	}
+	return &dto.AuditEntryResponse{
+		ID:                   entry.ID,
+		SubscriptionID:       entry.SubscriptionID,
+		SubscriptionPublicID: publicID,
+		UserID:               entry.UserID,
+		Action:               entry.Action,
+		Before:               entry.Before,
+		After:                entry.After,
+		RequestID:            entry.RequestID,
+		Actor:                entry.Actor,
+		CreatedAt:            entry.CreatedAt,
+	}
+}
+
+func (c *AuditConverter) ToAuditEntryDTOs(entries []*entity.AuditEntry) []*dto.AuditEntryResponse {
+	result := make([]*dto.AuditEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, c.ToAuditEntryDTO(entry))
+	}
+	return result
+}
@@ -0,0 +1,106 @@
+package converters
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/pkg/utils"
+	"math"
+)
+
+type BudgetConverter struct {
+}
+
+func NewBudgetConverter() *BudgetConverter {
+	return &BudgetConverter{}
+}
+
+func (c *BudgetConverter) ToBudgetEntity(req *dto.CreateBudgetRequest) *entity.Budget {
+	currency := req.Currency
+	if currency == "" {
+		currency = entity.DefaultCurrency
+	}
+	threshold := req.Threshold
+	if threshold == 0 {
+		threshold = entity.DefaultBudgetThreshold
+	}
+	return &entity.Budget{
+		UserID:      req.UserID,
+		ServiceID:   req.ServiceID,
+		ServiceName: req.ServiceName,
+		Category:    req.Category,
+		Period:      req.Period,
+		Amount:      req.Amount,
+		Currency:    currency,
+		Threshold:   threshold,
+	}
+}
+
+// ApplyUpdateToEntity applies the set fields of req to budget. A new service
+// id or name replaces the service of the budget, to be resolved from the
+// catalog.
+func (c *BudgetConverter) ApplyUpdateToEntity(budget *entity.Budget, req *dto.UpdateBudgetRequest) {
+	if req.ServiceID != nil {
+		budget.ServiceID = req.ServiceID
+		budget.ServiceName = nil
+	} else if req.ServiceName != nil {
+		budget.ServiceID = nil
+		budget.ServiceName = req.ServiceName
+	}
+	if req.Category != nil {
+		budget.Category = req.Category
+	}
+	if req.Period != nil {
+		budget.Period = *req.Period
+	}
+	if req.Amount != nil {
+		budget.Amount = *req.Amount
+	}
+	if req.Currency != nil {
+		budget.Currency = *req.Currency
+	}
+	if req.Threshold != nil {
+		budget.Threshold = *req.Threshold
+	}
+}
+
+func (c *BudgetConverter) ToBudgetDTO(budget *entity.Budget) *dto.BudgetResponse {
+	return &dto.BudgetResponse{
+		ID:          budget.ID,
+		UserID:      budget.UserID,
+		ServiceID:   budget.ServiceID,
+		ServiceName: budget.ServiceName,
+		Category:    budget.Category,
+		Period:      budget.Period,
+		Amount:      budget.Amount,
+		Currency:    budget.Currency,
+		Threshold:   budget.Threshold,
+		CreatedAt:   budget.CreatedAt,
+		UpdatedAt:   budget.UpdatedAt,
+	}
+}
+
+func (c *BudgetConverter) ToBudgetDTOs(budgets []*entity.Budget) []*dto.BudgetResponse {
+	result := make([]*dto.BudgetResponse, 0, len(budgets))
+	for _, budget := range budgets {
+		result = append(result, c.ToBudgetDTO(budget))
+	}
+	return result
+}
+
+func (c *BudgetConverter) ToBudgetStatusDTO(status *entity.BudgetStatus) *dto.BudgetStatusResponse {
+	resp := &dto.BudgetStatusResponse{
+		Budget:    c.ToBudgetDTO(status.Budget),
+		StartDate: utils.TimeToMonthYear(status.StartDate),
+		EndDate:   utils.TimeToMonthYear(status.EndDate),
+		Spent:     status.Spent,
+		Remaining: status.Budget.Amount - status.Spent,
+		Alerting:  status.Alerting(),
+	}
+	if resp.Remaining < 0 {
+		resp.Remaining = 0
+	}
+	if status.Budget.Amount > 0 {
+		resp.UsedPercent = math.Round(float64(status.Spent)*10000/float64(status.Budget.Amount)) / 100
+	}
+	return resp
+}
@@ -0,0 +1,37 @@
+package converters
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	"strings"
+)
+
+type CategoryConverter struct {
+}
+
+func NewCategoryConverter() *CategoryConverter {
+	return &CategoryConverter{}
+}
+
+func (c *CategoryConverter) ToCategoryEntity(req *dto.CreateCategoryRequest) *entity.Category {
+	return &entity.Category{
+		Code: normalizeCategory(req.Code),
+		Name: strings.TrimSpace(req.Name),
+	}
+}
+
+func (c *CategoryConverter) ToCategoryDTO(category *entity.Category) *dto.CategoryResponse {
+	return &dto.CategoryResponse{
+		Code:      category.Code,
+		Name:      category.Name,
+		CreatedAt: category.CreatedAt,
+	}
+}
+
+func (c *CategoryConverter) ToCategoryDTOs(categories []*entity.Category) []*dto.CategoryResponse {
+	result := make([]*dto.CategoryResponse, 0, len(categories))
+	for _, category := range categories {
+		result = append(result, c.ToCategoryDTO(category))
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+package converters
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/pkg/utils"
+)
+
+type ExchangeRateConverter struct {
+}
+
+func NewExchangeRateConverter() *ExchangeRateConverter {
+	return &ExchangeRateConverter{}
+}
+
+func (c *ExchangeRateConverter) ToExchangeRateEntity(req *dto.CreateExchangeRateRequest) *entity.ExchangeRate {
+	validFrom, _ := utils.ParseMonthYearToTime(req.ValidFrom)
+	return &entity.ExchangeRate{
+		Currency:  req.Currency,
+		Rate:      req.Rate,
+		ValidFrom: validFrom,
+	}
+}
+
+func (c *ExchangeRateConverter) ToExchangeRateDTO(rate *entity.ExchangeRate) *dto.ExchangeRateResponse {
+	return &dto.ExchangeRateResponse{
+		ID:        rate.ID,
+		Currency:  rate.Currency,
+		Rate:      rate.Rate,
+		ValidFrom: utils.TimeToMonthYear(rate.ValidFrom),
+		CreatedAt: rate.CreatedAt,
+		UpdatedAt: rate.UpdatedAt,
+	}
+}
+
+func (c *ExchangeRateConverter) ToExchangeRateDTOs(rates []*entity.ExchangeRate) []*dto.ExchangeRateResponse {
+	result := make([]*dto.ExchangeRateResponse, 0, len(rates))
+	for _, rate := range rates {
+		result = append(result, c.ToExchangeRateDTO(rate))
+	}
+	return result
+}
@@ -0,0 +1,77 @@
+package converters
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/pkg/utils"
+)
+
+type ServiceConverter struct {
+}
+
+func NewServiceConverter() *ServiceConverter {
+	return &ServiceConverter{}
+}
+
+func (c *ServiceConverter) ToServiceEntity(req *dto.CreateServiceRequest) *entity.Service {
+	return &entity.Service{
+		Name:           utils.TidyServiceName(req.Name),
+		NormalizedName: utils.NormalizeServiceName(req.Name),
+	}
+}
+
+func (c *ServiceConverter) ToServiceDTO(service *entity.Service) *dto.ServiceResponse {
+	return &dto.ServiceResponse{
+		ID:        service.ID,
+		Name:      service.Name,
+		CreatedAt: service.CreatedAt,
+		UpdatedAt: service.UpdatedAt,
+	}
+}
+
+func (c *ServiceConverter) ToServiceDTOs(services []*entity.Service) []*dto.ServiceResponse {
+	result := make([]*dto.ServiceResponse, 0, len(services))
+	for _, service := range services {
+		result = append(result, c.ToServiceDTO(service))
+	}
+	return result
+}
+
+func (c *ServiceConverter) ToServiceAliasEntity(serviceID int, req *dto.CreateServiceAliasRequest) *entity.ServiceAlias {
+	return &entity.ServiceAlias{
+		ServiceID:       serviceID,
+		Alias:           utils.TidyServiceName(req.Alias),
+		NormalizedAlias: utils.NormalizeServiceName(req.Alias),
+	}
+}
+
+func (c *ServiceConverter) ToServiceAliasDTO(alias *entity.ServiceAlias) *dto.ServiceAliasResponse {
+	return &dto.ServiceAliasResponse{
+		ID:        alias.ID,
+		ServiceID: alias.ServiceID,
+		Alias:     alias.Alias,
+		CreatedAt: alias.CreatedAt,
+	}
+}
+
+func (c *ServiceConverter) ToServiceAliasDTOs(aliases []*entity.ServiceAlias) []*dto.ServiceAliasResponse {
+	result := make([]*dto.ServiceAliasResponse, 0, len(aliases))
+	for _, alias := range aliases {
+		result = append(result, c.ToServiceAliasDTO(alias))
+	}
+	return result
+}
+
+func (c *ServiceConverter) ToServiceDuplicateDTOs(duplicates []*entity.ServiceDuplicate) []*dto.ServiceDuplicateResponse {
+	result := make([]*dto.ServiceDuplicateResponse, 0, len(duplicates))
+	for _, duplicate := range duplicates {
+		result = append(result, &dto.ServiceDuplicateResponse{
+			ServiceID:     duplicate.ServiceID,
+			ServiceName:   duplicate.ServiceName,
+			DuplicateID:   duplicate.DuplicateID,
+			DuplicateName: duplicate.DuplicateName,
+			Similarity:    duplicate.Similarity,
+		})
+	}
+	return result
+}
@@ -4,6 +4,9 @@ import (
 	"AggregationService/internal/domain/models/dto"
 	"AggregationService/internal/domain/models/entity"
 	"AggregationService/internal/pkg/utils"
+	"github.com/google/uuid"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -21,22 +24,63 @@ func (c *SubscriptionConverter) ToSubscriptionEntity(req *dto.CreateSubscription
 		ed, _ := utils.ParseMonthYearToTime(*req.EndDate)
 		endDate = &ed
 	}
+	currency := req.Currency
+	if currency == "" {
+		currency = entity.DefaultCurrency
+	}
+	billingPeriod := req.BillingPeriod
+	if billingPeriod == "" {
+		billingPeriod = entity.BillingPeriodMonth
+	}
+	var serviceID int
+	if req.ServiceID != nil {
+		serviceID = *req.ServiceID
+	}
+	category := normalizeCategory(req.Category)
+	if category == "" {
+		category = entity.DefaultCategory
+	}
+	billingDay := req.BillingDay
+	if billingDay == 0 {
+		billingDay = entity.DefaultBillingDay
+	}
+	autoRenew := true
+	if req.AutoRenew != nil {
+		autoRenew = *req.AutoRenew
+	}
 	return &entity.Subscription{
-		ServiceName: req.ServiceName,
-		Price:       req.Price,
-		UserID:      req.UserID,
-		StartDate:   startDate,
-		EndDate:     endDate,
+		ServiceID:     serviceID,
+		ServiceName:   req.ServiceName,
+		Price:         req.Price,
+		Currency:      currency,
+		BillingPeriod: billingPeriod,
+		BillingMonths: entity.BillingPeriodMonths(billingPeriod, req.BillingMonths),
+		BillingDay:    billingDay,
+		AutoRenew:     autoRenew,
+		UserID:        req.UserID,
+		StartDate:     startDate,
+		EndDate:       endDate,
+		Category:      category,
+		Tags:          utils.NormalizeTags(req.Tags),
+		Metadata:      toMetadata(req.Metadata),
+		Status:        entity.SubscriptionStatusActive,
 	}
 }
 
 func (c *SubscriptionConverter) ToSubscriptionDTO(sub *entity.Subscription) *dto.SubscriptionResponse {
 	return &dto.SubscriptionResponse{
-		ID:          sub.ID,
-		ServiceName: sub.ServiceName,
-		Price:       sub.Price,
-		UserID:      sub.UserID,
-		StartDate:   utils.TimeToMonthYear(sub.StartDate),
+		ID:            sub.ID,
+		PublicID:      sub.PublicID,
+		ServiceID:     sub.ServiceID,
+		ServiceName:   sub.ServiceName,
+		Price:         sub.Price,
+		Currency:      sub.Currency,
+		BillingPeriod: sub.BillingPeriod,
+		BillingMonths: sub.BillingMonths,
+		BillingDay:    sub.BillingDay,
+		AutoRenew:     sub.AutoRenew,
+		UserID:        sub.UserID,
+		StartDate:     utils.TimeToMonthYear(sub.StartDate),
 		EndDate: func() *string {
 			if sub.EndDate == nil {
 				return nil
@@ -44,22 +88,175 @@ func (c *SubscriptionConverter) ToSubscriptionDTO(sub *entity.Subscription) *dto
 			s := utils.TimeToMonthYear(*sub.EndDate)
 			return &s
 		}(),
+		NextChargeDate: func() *string {
+			if sub.DeletedAt != nil {
+				return nil
+			}
+			next := sub.NextChargeAt(time.Now())
+			if next == nil {
+				return nil
+			}
+			s := utils.TimeToDayMonthYear(*next)
+			return &s
+		}(),
+		Category: sub.Category,
+		Tags: func() []string {
+			if sub.Tags == nil {
+				return []string{}
+			}
+			return sub.Tags
+		}(),
+		Metadata: func() map[string]string {
+			if sub.Metadata == nil {
+				return map[string]string{}
+			}
+			return sub.Metadata
+		}(),
+		Status:    sub.StatusAt(time.Now()),
 		CreatedAt: sub.CreatedAt,
 		UpdatedAt: sub.UpdatedAt,
+		DeletedAt: sub.DeletedAt,
+		Version:   sub.Version,
 	}
 }
 
+// ApplyUpdateToEntity copies the fields set in req onto sub. A new service
+// name clears ServiceID so that the repository resolves the service by name.
 func (c *SubscriptionConverter) ApplyUpdateToEntity(sub *entity.Subscription, req *dto.UpdateSubscriptionRequest) {
-	if req.ServiceName != nil {
+	if req.ServiceID != nil {
+		sub.ServiceID = *req.ServiceID
+	} else if req.ServiceName != nil {
+		sub.ServiceID = 0
 		sub.ServiceName = *req.ServiceName
 	}
-	if req.Price != nil {
-		sub.Price = *req.Price
+	if req.Currency != nil {
+		sub.Currency = *req.Currency
+	}
+	if req.BillingPeriod != nil {
+		sub.BillingPeriod = *req.BillingPeriod
+	}
+	if req.BillingMonths != nil {
+		sub.BillingMonths = *req.BillingMonths
+	}
+	sub.BillingMonths = entity.BillingPeriodMonths(sub.BillingPeriod, sub.BillingMonths)
+	if req.BillingDay != nil {
+		sub.BillingDay = *req.BillingDay
+	}
+	if req.AutoRenew != nil {
+		sub.AutoRenew = *req.AutoRenew
 	}
 	if req.EndDate != nil {
 		ed, _ := utils.ParseMonthYearToTime(*req.EndDate)
 		sub.EndDate = &ed
 	}
+	if req.Category != nil {
+		sub.Category = normalizeCategory(*req.Category)
+	}
+	if req.Tags != nil {
+		sub.Tags = utils.NormalizeTags(*req.Tags)
+	}
+	if req.Metadata != nil {
+		sub.Metadata = toMetadata(*req.Metadata)
+	}
+}
+
+func (c *SubscriptionConverter) ToSubscriptionFilter(req *dto.ListSubscriptionsRequest) *entity.SubscriptionFilter {
+	return &entity.SubscriptionFilter{
+		UserID:         req.UserID,
+		ServiceID:      req.ServiceID,
+		ServiceName:    req.ServiceName,
+		Category:       normalizeCategoryFilter(req.Category),
+		Tag:            normalizeTagFilter(req.Tag),
+		Status:         req.Status,
+		Metadata:       req.Metadata,
+		IncludeDeleted: req.IncludeDeleted,
+		Limit:          req.Limit,
+		Offset:         req.Offset,
+	}
+}
+
+func (c *SubscriptionConverter) ToCostFilter(req *dto.CalculateCostRequest) *entity.CostFilter {
+	startDate, _ := utils.ParseMonthYearToTime(req.StartDate)
+	endDate, _ := utils.ParseMonthYearToTime(req.EndDate)
+	currency := req.Currency
+	if currency == "" {
+		currency = entity.DefaultCurrency
+	}
+	mode := req.Mode
+	if mode == "" {
+		mode = entity.CostModeCharged
+	}
+	return &entity.CostFilter{
+		UserID:      req.UserID,
+		ServiceID:   req.ServiceID,
+		ServiceName: req.ServiceName,
+		Category:    normalizeCategoryFilter(req.Category),
+		Tag:         normalizeTagFilter(req.Tag),
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Currency:    currency,
+		Mode:        mode,
+		GroupBy:     req.GroupBy,
+	}
+}
+
+func (c *SubscriptionConverter) ToCalculateCostResponse(report *entity.CostReport, filter *entity.CostFilter) *dto.CalculateCostResponse {
+	resp := &dto.CalculateCostResponse{Currency: filter.Currency, Mode: filter.Mode}
+	if report.Groups != nil {
+		resp.Groups = make([]*dto.CostGroupResponse, 0, len(report.Groups))
+		for _, group := range report.Groups {
+			resp.TotalCost += group.Cost
+			resp.Groups = append(resp.Groups, &dto.CostGroupResponse{
+				ServiceName: group.ServiceName,
+				Category:    group.Category,
+				UserID:      group.UserID,
+				Month: func() *string {
+					if group.Month == nil {
+						return nil
+					}
+					s := utils.TimeToMonthYear(*group.Month)
+					return &s
+				}(),
+				Subscriptions: group.Subscriptions,
+				Cost:          group.Cost,
+			})
+		}
+		return resp
+	}
+
+	resp.Subscriptions = make([]*dto.SubscriptionCostResponse, 0, len(report.Subscriptions))
+	for _, cost := range report.Subscriptions {
+		resp.TotalCost += cost.Cost
+		resp.Subscriptions = append(resp.Subscriptions, &dto.SubscriptionCostResponse{
+			SubscriptionID: cost.SubscriptionID,
+			PublicID:       cost.PublicID,
+			ServiceName:    cost.ServiceName,
+			UserID:         cost.UserID,
+			Price:          cost.Price,
+			Currency:       cost.Currency,
+			BillingPeriod:  cost.BillingPeriod,
+			Months:         cost.Months,
+			Cost:           cost.Cost,
+		})
+	}
+	return resp
+}
+
+func (c *SubscriptionConverter) ToCostTimeSeriesResponse(buckets []*entity.CostBucket, filter *entity.CostFilter) *dto.CostTimeSeriesResponse {
+	resp := &dto.CostTimeSeriesResponse{
+		Currency: filter.Currency,
+		Mode:     filter.Mode,
+		Buckets:  make([]*dto.CostBucketResponse, 0, len(buckets)),
+	}
+	for _, bucket := range buckets {
+		resp.TotalCost += bucket.Cost
+		resp.Buckets = append(resp.Buckets, &dto.CostBucketResponse{
+			Month:         utils.TimeToMonthYear(bucket.Month),
+			Cost:          bucket.Cost,
+			Subscriptions: bucket.Subscriptions,
+		})
+	}
+	return resp
 }
 
 func (c *SubscriptionConverter) ToSubscriptionDTOs(subs []*entity.Subscription) []*dto.SubscriptionResponse {
@@ -69,3 +266,212 @@ func (c *SubscriptionConverter) ToSubscriptionDTOs(subs []*entity.Subscription)
 	}
 	return result
 }
+
+func (c *SubscriptionConverter) ToSubscriptionPriceEntity(subscriptionID int, req *dto.CreateSubscriptionPriceRequest) *entity.SubscriptionPrice {
+	effectiveFrom, _ := utils.ParseMonthYearToTime(req.EffectiveFrom)
+	return &entity.SubscriptionPrice{
+		SubscriptionID: subscriptionID,
+		Price:          req.Price,
+		EffectiveFrom:  effectiveFrom,
+	}
+}
+
+func (c *SubscriptionConverter) ToSubscriptionPriceDTO(price *entity.SubscriptionPrice) *dto.SubscriptionPriceResponse {
+	return &dto.SubscriptionPriceResponse{
+		ID:             price.ID,
+		SubscriptionID: price.SubscriptionID,
+		Price:          price.Price,
+		EffectiveFrom:  utils.TimeToMonthYear(price.EffectiveFrom),
+		CreatedAt:      price.CreatedAt,
+	}
+}
+
+func (c *SubscriptionConverter) ToSubscriptionPriceDTOs(prices []*entity.SubscriptionPrice) []*dto.SubscriptionPriceResponse {
+	result := make([]*dto.SubscriptionPriceResponse, 0, len(prices))
+	for _, price := range prices {
+		result = append(result, c.ToSubscriptionPriceDTO(price))
+	}
+	return result
+}
+
+func (c *SubscriptionConverter) ToSubscriptionPauseDTO(pause *entity.SubscriptionPause) *dto.SubscriptionPauseResponse {
+	return &dto.SubscriptionPauseResponse{
+		ID:             pause.ID,
+		SubscriptionID: pause.SubscriptionID,
+		PausedFrom:     utils.TimeToMonthYear(pause.PausedFrom),
+		ResumedFrom: func() *string {
+			if pause.ResumedFrom == nil {
+				return nil
+			}
+			s := utils.TimeToMonthYear(*pause.ResumedFrom)
+			return &s
+		}(),
+		CreatedAt: pause.CreatedAt,
+	}
+}
+
+func (c *SubscriptionConverter) ToSubscriptionPauseDTOs(pauses []*entity.SubscriptionPause) []*dto.SubscriptionPauseResponse {
+	result := make([]*dto.SubscriptionPauseResponse, 0, len(pauses))
+	for _, pause := range pauses {
+		result = append(result, c.ToSubscriptionPauseDTO(pause))
+	}
+	return result
+}
+
+// ToForecastFilter turns a forecast into a cost filter over req.Months months
+// starting with the month of now, grouped by month and service and limited to
+// the subscriptions active in that month.
+func (c *SubscriptionConverter) ToForecastFilter(req *dto.ForecastRequest, now time.Time) *entity.CostFilter {
+	start := utils.MonthStart(now)
+	filter := c.ToCostFilter(&dto.CalculateCostRequest{
+		UserID:      req.UserID,
+		ServiceID:   req.ServiceID,
+		ServiceName: req.ServiceName,
+		Category:    req.Category,
+		Tag:         req.Tag,
+		StartDate:   utils.TimeToMonthYear(start),
+		EndDate:     utils.TimeToMonthYear(start.AddDate(0, req.Months-1, 0)),
+		Currency:    req.Currency,
+		Mode:        req.Mode,
+		GroupBy:     []string{entity.CostGroupByMonth, entity.CostGroupByServiceName},
+	})
+	filter.ActiveAt = &start
+	return filter
+}
+
+func (c *SubscriptionConverter) ToForecastResponse(groups []*entity.CostGroup, filter *entity.CostFilter) *dto.ForecastResponse {
+	resp := &dto.ForecastResponse{
+		Currency:  filter.Currency,
+		Mode:      filter.Mode,
+		StartDate: utils.TimeToMonthYear(filter.StartDate),
+		EndDate:   utils.TimeToMonthYear(filter.EndDate),
+	}
+
+	byMonth := make(map[string]*dto.ForecastMonthResponse)
+	for _, month := range utils.MonthRange(filter.StartDate, filter.EndDate) {
+		m := &dto.ForecastMonthResponse{
+			Month:    utils.TimeToMonthYear(month),
+			Services: make([]*dto.ForecastServiceResponse, 0),
+		}
+		byMonth[m.Month] = m
+		resp.Months = append(resp.Months, m)
+	}
+
+	byService := make(map[string]*dto.ForecastServiceResponse)
+	for _, group := range groups {
+		if group.Month == nil || group.ServiceName == nil {
+			continue
+		}
+		month, ok := byMonth[utils.TimeToMonthYear(*group.Month)]
+		if !ok {
+			continue
+		}
+		month.Cost += group.Cost
+		month.Services = append(month.Services, &dto.ForecastServiceResponse{
+			ServiceName: *group.ServiceName,
+			Cost:        group.Cost,
+		})
+
+		service, ok := byService[*group.ServiceName]
+		if !ok {
+			service = &dto.ForecastServiceResponse{ServiceName: *group.ServiceName}
+			byService[*group.ServiceName] = service
+			resp.Services = append(resp.Services, service)
+		}
+		service.Cost += group.Cost
+		resp.TotalCost += group.Cost
+	}
+
+	if resp.Services == nil {
+		resp.Services = make([]*dto.ForecastServiceResponse, 0)
+	}
+	sort.Slice(resp.Services, func(i, j int) bool {
+		return resp.Services[i].ServiceName < resp.Services[j].ServiceName
+	})
+	return resp
+}
+
+// normalizeCategory returns a category code trimmed and lower-cased.
+func normalizeCategory(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
+
+func normalizeCategoryFilter(code *string) *string {
+	if code == nil {
+		return nil
+	}
+	normalized := normalizeCategory(*code)
+	return &normalized
+}
+
+func normalizeTagFilter(tag *string) *string {
+	if tag == nil {
+		return nil
+	}
+	normalized := utils.NormalizeTag(*tag)
+	return &normalized
+}
+
+// ToChargeFilter selects the charges of req.Days days starting with the day
+// of now.
+func (c *SubscriptionConverter) ToChargeFilter(req *dto.UpcomingChargesRequest, now time.Time) *entity.ChargeFilter {
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return &entity.ChargeFilter{
+		UserID: req.UserID,
+		From:   from,
+		To:     from.AddDate(0, 0, req.Days-1),
+	}
+}
+
+// ToUpcomingChargesResponse groups charges by user, keeping their order.
+func (c *SubscriptionConverter) ToUpcomingChargesResponse(charges []*entity.Charge, filter *entity.ChargeFilter) *dto.UpcomingChargesResponse {
+	resp := &dto.UpcomingChargesResponse{
+		From:  utils.TimeToDayMonthYear(filter.From),
+		To:    utils.TimeToDayMonthYear(filter.To),
+		Users: make([]*dto.UserChargesResponse, 0),
+	}
+
+	byUser := make(map[uuid.UUID]*dto.UserChargesResponse)
+	for _, charge := range charges {
+		user, ok := byUser[charge.UserID]
+		if !ok {
+			user = &dto.UserChargesResponse{
+				UserID:  charge.UserID,
+				Totals:  make([]*dto.ChargeTotalResponse, 0),
+				Charges: make([]*dto.ChargeResponse, 0),
+			}
+			byUser[charge.UserID] = user
+			resp.Users = append(resp.Users, user)
+		}
+		user.Charges = append(user.Charges, &dto.ChargeResponse{
+			SubscriptionID:       charge.SubscriptionID,
+			SubscriptionPublicID: charge.SubscriptionPublicID,
+			ServiceName:          charge.ServiceName,
+			Date:                 utils.TimeToDayMonthYear(charge.Date),
+			Amount:               charge.Amount,
+			Currency:             charge.Currency,
+		})
+
+		var total *dto.ChargeTotalResponse
+		for _, t := range user.Totals {
+			if t.Currency == charge.Currency {
+				total = t
+			}
+		}
+		if total == nil {
+			total = &dto.ChargeTotalResponse{Currency: charge.Currency}
+			user.Totals = append(user.Totals, total)
+		}
+		total.Amount += charge.Amount
+	}
+	return resp
+}
+
+// toMetadata copies client metadata, so a missing object is stored empty.
+func toMetadata(metadata map[string]string) entity.Metadata {
+	result := make(entity.Metadata, len(metadata))
+	for key, value := range metadata {
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+// ListAuditRequest filters the audit trail. From and To bound the time of the
+// change, both inclusive. SubscriptionID is the deprecated integer id of the
+// subscription, SubscriptionPublicID its public id.
+type ListAuditRequest struct {
+	SubscriptionID       *int       `json:"subscription_id,omitempty" validate:"omitempty,min=1"`
+	SubscriptionPublicID *uuid.UUID `json:"subscription_public_id,omitempty"`
+	UserID               *uuid.UUID `json:"user_id,omitempty"`
+	Action               *string    `json:"action,omitempty" validate:"omitempty,oneof=create update delete restore price pause resume cancel"`
+	Actor                *string    `json:"actor,omitempty" validate:"omitempty,min=1,max=255"`
+	RequestID            *string    `json:"request_id,omitempty" validate:"omitempty,min=1,max=255"`
+	From                 *time.Time `json:"from,omitempty"`
+	To                   *time.Time `json:"to,omitempty"`
+	Limit                int        `json:"limit,omitempty" validate:"min=0,max=500"`
+	Offset               int        `json:"offset,omitempty" validate:"min=0"`
+}
+
+type AuditEntryResponse struct {
+	ID                   int64           `json:"id"`
+	SubscriptionID       int             `json:"subscription_id"`
+	SubscriptionPublicID *uuid.UUID      `json:"subscription_public_id,omitempty"`
+	UserID               uuid.UUID       `json:"user_id"`
+	Action               string          `json:"action"`
+	Before               json.RawMessage `json:"before,omitempty"`
+	After                json.RawMessage `json:"after,omitempty"`
+	RequestID            string          `json:"request_id,omitempty"`
+	Actor                string          `json:"actor"`
+	CreatedAt            time.Time       `json:"created_at"`
+}
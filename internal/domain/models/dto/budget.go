@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"AggregationService/internal/pkg/money"
+	"github.com/google/uuid"
+	"time"
+)
+
+type CreateBudgetRequest struct {
+	UserID      uuid.UUID    `json:"user_id" validate:"required,uuid4"`
+	ServiceID   *int         `json:"service_id,omitempty" validate:"omitempty,min=1"`
+	ServiceName *string      `json:"service_name,omitempty" validate:"omitempty,min=1,max=255"`
+	Category    *string      `json:"category,omitempty" validate:"omitempty,min=1,max=50"`
+	Period      string       `json:"period" validate:"required,oneof=month year"`
+	Amount      money.Amount `json:"amount" validate:"required,min=1"`
+	Currency    string       `json:"currency,omitempty" validate:"omitempty,iso4217"`
+	Threshold   int          `json:"threshold,omitempty" validate:"omitempty,min=1,max=1000"`
+}
+
+type UpdateBudgetRequest struct {
+	ServiceID   *int          `json:"service_id,omitempty" validate:"omitempty,min=1"`
+	ServiceName *string       `json:"service_name,omitempty" validate:"omitempty,min=1,max=255"`
+	Category    *string       `json:"category,omitempty" validate:"omitempty,min=1,max=50"`
+	Period      *string       `json:"period,omitempty" validate:"omitempty,oneof=month year"`
+	Amount      *money.Amount `json:"amount,omitempty" validate:"omitempty,min=1"`
+	Currency    *string       `json:"currency,omitempty" validate:"omitempty,iso4217"`
+	Threshold   *int          `json:"threshold,omitempty" validate:"omitempty,min=1,max=1000"`
+}
+
+type BudgetResponse struct {
+	ID          int          `json:"id"`
+	UserID      uuid.UUID    `json:"user_id"`
+	ServiceID   *int         `json:"service_id,omitempty"`
+	ServiceName *string      `json:"service_name,omitempty"`
+	Category    *string      `json:"category,omitempty"`
+	Period      string       `json:"period"`
+	Amount      money.Amount `json:"amount"`
+	Currency    string       `json:"currency"`
+	Threshold   int          `json:"threshold"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+type BudgetStatusResponse struct {
+	Budget      *BudgetResponse `json:"budget"`
+	StartDate   string          `json:"start_date"`
+	EndDate     string          `json:"end_date"`
+	Spent       money.Amount    `json:"spent"`
+	Remaining   money.Amount    `json:"remaining"`
+	UsedPercent float64         `json:"used_percent"`
+	Alerting    bool            `json:"alerting"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+type CreateCategoryRequest struct {
+	Code string `json:"code" validate:"required,min=1,max=50"`
+	Name string `json:"name" validate:"required,min=1,max=100"`
+}
+
+type CategoryResponse struct {
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
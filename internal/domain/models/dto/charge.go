@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"AggregationService/internal/pkg/money"
+	"github.com/google/uuid"
+)
+
+// UpcomingChargesRequest asks for the charges due in the next Days days,
+// today included.
+type UpcomingChargesRequest struct {
+	UserID *uuid.UUID `json:"user_id,omitempty"`
+	Days   int        `json:"days" validate:"required,min=1,max=366"`
+}
+
+type ChargeResponse struct {
+	SubscriptionID       int          `json:"subscription_id"`
+	SubscriptionPublicID uuid.UUID    `json:"subscription_public_id"`
+	ServiceName          string       `json:"service_name"`
+	Date                 string       `json:"date"`
+	Amount               money.Amount `json:"amount"`
+	Currency             string       `json:"currency"`
+}
+
+type ChargeTotalResponse struct {
+	Currency string       `json:"currency"`
+	Amount   money.Amount `json:"amount"`
+}
+
+// UserChargesResponse lists the upcoming charges of one user by date with
+// their sum in every currency charged.
+type UserChargesResponse struct {
+	UserID  uuid.UUID              `json:"user_id"`
+	Totals  []*ChargeTotalResponse `json:"totals"`
+	Charges []*ChargeResponse      `json:"charges"`
+}
+
+type UpcomingChargesResponse struct {
+	From  string                 `json:"from"`
+	To    string                 `json:"to"`
+	Users []*UserChargesResponse `json:"users"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+type CreateExchangeRateRequest struct {
+	Currency  string  `json:"currency" validate:"required,iso4217,ne=RUB"`
+	Rate      float64 `json:"rate" validate:"required,gt=0"`
+	ValidFrom string  `json:"valid_from" validate:"required,mmYYYY"`
+}
+
+type ExchangeRateResponse struct {
+	ID        int       `json:"id"`
+	Currency  string    `json:"currency"`
+	Rate      float64   `json:"rate"`
+	ValidFrom string    `json:"valid_from"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
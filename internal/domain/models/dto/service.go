@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+type CreateServiceRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=255"`
+}
+
+type UpdateServiceRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=255"`
+}
+
+type ServiceResponse struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateServiceAliasRequest struct {
+	Alias string `json:"alias" validate:"required,min=1,max=255"`
+}
+
+type ServiceAliasResponse struct {
+	ID        int       `json:"id"`
+	ServiceID int       `json:"service_id"`
+	Alias     string    `json:"alias"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ServiceDuplicatesRequest struct {
+	MinSimilarity float64 `json:"min_similarity" validate:"gt=0,lte=1"`
+	Limit         int     `json:"limit" validate:"min=1,max=500"`
+}
+
+type ServiceDuplicateResponse struct {
+	ServiceID     int     `json:"service_id"`
+	ServiceName   string  `json:"service_name"`
+	DuplicateID   int     `json:"duplicate_id"`
+	DuplicateName string  `json:"duplicate_name"`
+	Similarity    float64 `json:"similarity"`
+}
+
+// MergeServiceRequest names the service merged into the service of the URL.
+type MergeServiceRequest struct {
+	ServiceID int `json:"service_id" validate:"required,min=1"`
+}
@@ -1,48 +1,189 @@
 package dto
 
 import (
+	"AggregationService/internal/pkg/money"
 	"github.com/google/uuid"
 	"time"
 )
 
 type CreateSubscriptionRequest struct {
-	ServiceName string    `json:"service_name" validate:"required,min=1,max=255"`
-	Price       int       `json:"price" validate:"required,min=1"`
-	UserID      uuid.UUID `json:"user_id" validate:"required,uuid4"`
-	StartDate   string    `json:"start_date" validate:"required,mmYYYY"`
-	EndDate     *string   `json:"end_date,omitempty" validate:"omitempty,mmYYYY"`
+	ServiceID     *int              `json:"service_id,omitempty" validate:"omitempty,min=1"`
+	ServiceName   string            `json:"service_name,omitempty" validate:"required_without=ServiceID,omitempty,min=1,max=255"`
+	Price         money.Amount      `json:"price" validate:"required,min=1"`
+	Currency      string            `json:"currency,omitempty" validate:"omitempty,iso4217"`
+	BillingPeriod string            `json:"billing_period,omitempty" validate:"omitempty,oneof=week month quarter year custom"`
+	BillingMonths int               `json:"billing_months,omitempty" validate:"required_if=BillingPeriod custom,omitempty,min=1,max=120"`
+	BillingDay    int               `json:"billing_day,omitempty" validate:"omitempty,min=1,max=31"`
+	AutoRenew     *bool             `json:"auto_renew,omitempty"`
+	UserID        uuid.UUID         `json:"user_id" validate:"required,uuid4"`
+	StartDate     string            `json:"start_date" validate:"required,mmYYYY"`
+	EndDate       *string           `json:"end_date,omitempty" validate:"omitempty,mmYYYY"`
+	Category      string            `json:"category,omitempty" validate:"omitempty,max=50"`
+	Tags          []string          `json:"tags,omitempty" validate:"omitempty,max=20,dive,max=50"`
+	Metadata      map[string]string `json:"metadata,omitempty" validate:"omitempty,max=50,dive,keys,min=1,max=64,endkeys,max=512"`
 }
 
+// UpdateSubscriptionRequest changes the fields that are set. Tags and
+// Metadata, when set, replace all tags or metadata of the subscription, an
+// empty list or object removes them.
 type UpdateSubscriptionRequest struct {
-	ServiceName *string `json:"service_name,omitempty" validate:"omitempty,min=1,max=255"`
-	Price       *int    `json:"price,omitempty" validate:"omitempty,min=1"`
-	EndDate     *string `json:"end_date,omitempty" validate:"omitempty,mmYYYY"`
+	ServiceID     *int               `json:"service_id,omitempty" validate:"omitempty,min=1"`
+	ServiceName   *string            `json:"service_name,omitempty" validate:"omitempty,min=1,max=255"`
+	Price         *money.Amount      `json:"price,omitempty" validate:"omitempty,min=1"`
+	PriceFrom     *string            `json:"price_from,omitempty" validate:"omitempty,mmYYYY"`
+	Currency      *string            `json:"currency,omitempty" validate:"omitempty,iso4217"`
+	BillingPeriod *string            `json:"billing_period,omitempty" validate:"omitempty,oneof=week month quarter year custom"`
+	BillingMonths *int               `json:"billing_months,omitempty" validate:"omitempty,min=1,max=120"`
+	BillingDay    *int               `json:"billing_day,omitempty" validate:"omitempty,min=1,max=31"`
+	AutoRenew     *bool              `json:"auto_renew,omitempty"`
+	EndDate       *string            `json:"end_date,omitempty" validate:"omitempty,mmYYYY"`
+	Category      *string            `json:"category,omitempty" validate:"omitempty,min=1,max=50"`
+	Tags          *[]string          `json:"tags,omitempty" validate:"omitempty,max=20,dive,max=50"`
+	Metadata      *map[string]string `json:"metadata,omitempty" validate:"omitempty,max=50,dive,keys,min=1,max=64,endkeys,max=512"`
 }
 
 type CreateSubscriptionResponse struct {
-	ServiceName *string `json:"service_name,omitempty" validate:"omitempty,min=1,max=255"`
-	Price       *int    `json:"price,omitempty" validate:"omitempty,min=1"`
-	EndDate     *string `json:"end_date,omitempty" validate:"omitempty,mmYYYY"`
+	ServiceName *string       `json:"service_name,omitempty" validate:"omitempty,min=1,max=255"`
+	Price       *money.Amount `json:"price,omitempty" validate:"omitempty,min=1"`
+	EndDate     *string       `json:"end_date,omitempty" validate:"omitempty,mmYYYY"`
 }
 
+// SubscriptionResponse is addressed by PublicID. ID is the deprecated integer
+// id, still accepted by the routes during the deprecation window.
 type SubscriptionResponse struct {
-	ID          int       `json:"id"`
-	ServiceName string    `json:"service_name"`
-	Price       int       `json:"price"`
-	UserID      uuid.UUID `json:"user_id"`
-	StartDate   string    `json:"start_date"`
-	EndDate     *string   `json:"end_date,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID             int               `json:"id"`
+	PublicID       uuid.UUID         `json:"public_id"`
+	ServiceID      int               `json:"service_id"`
+	ServiceName    string            `json:"service_name"`
+	Price          money.Amount      `json:"price"`
+	Currency       string            `json:"currency"`
+	BillingPeriod  string            `json:"billing_period"`
+	BillingMonths  int               `json:"billing_months"`
+	BillingDay     int               `json:"billing_day"`
+	AutoRenew      bool              `json:"auto_renew"`
+	UserID         uuid.UUID         `json:"user_id"`
+	StartDate      string            `json:"start_date"`
+	EndDate        *string           `json:"end_date,omitempty"`
+	NextChargeDate *string           `json:"next_charge_date,omitempty"`
+	Category       string            `json:"category"`
+	Tags           []string          `json:"tags"`
+	Metadata       map[string]string `json:"metadata"`
+	Status         string            `json:"status"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	DeletedAt      *time.Time        `json:"deleted_at,omitempty"`
+	Version        int               `json:"version"`
+}
+
+// SubscriptionConflictResponse is the 409 body of a subscription that overlaps
+// an active subscription of the same user to the same service.
+type SubscriptionConflictResponse struct {
+	Error                           string     `json:"error"`
+	ConflictingSubscriptionID       int        `json:"conflicting_subscription_id,omitempty"`
+	ConflictingSubscriptionPublicID *uuid.UUID `json:"conflicting_subscription_public_id,omitempty"`
+}
+
+// ListSubscriptionsRequest filters the subscription list. service_name is
+// kept for compatibility and matches any service containing the text;
+// Metadata matches subscriptions having all of its keys with these values.
+type ListSubscriptionsRequest struct {
+	UserID         *uuid.UUID        `json:"user_id,omitempty"`
+	ServiceID      *int              `json:"service_id,omitempty"`
+	ServiceName    *string           `json:"service_name,omitempty"`
+	Category       *string           `json:"category,omitempty"`
+	Tag            *string           `json:"tag,omitempty"`
+	Status         *string           `json:"status,omitempty" validate:"omitempty,oneof=active paused cancelled expired"`
+	Metadata       map[string]string `json:"metadata,omitempty" validate:"omitempty,max=20,dive,keys,min=1,max=64,endkeys,max=512"`
+	IncludeDeleted bool              `json:"include_deleted,omitempty"`
+	Limit          int               `json:"limit,omitempty"`
+	Offset         int               `json:"offset,omitempty"`
 }
 
 type CalculateCostRequest struct {
 	UserID      *uuid.UUID `json:"user_id,omitempty"`
+	ServiceID   *int       `json:"service_id,omitempty" validate:"omitempty,min=1"`
 	ServiceName *string    `json:"service_name,omitempty" validate:"omitempty,min=1,max=255"`
+	Category    *string    `json:"category,omitempty" validate:"omitempty,min=1,max=50"`
+	Tag         *string    `json:"tag,omitempty" validate:"omitempty,min=1,max=50"`
 	StartDate   string     `json:"start_date" validate:"required,mmYYYY"`
 	EndDate     string     `json:"end_date" validate:"required,mmYYYY"`
+	Currency    string     `json:"currency,omitempty" validate:"omitempty,iso4217"`
+	Mode        string     `json:"mode,omitempty" validate:"omitempty,oneof=charged amortized"`
+	GroupBy     []string   `json:"group_by,omitempty" validate:"omitempty,unique,dive,oneof=service_name user_id month category"`
+}
+
+// SubscriptionCostResponse is the cost of the subscription with PublicID.
+// SubscriptionID is its deprecated integer id.
+type SubscriptionCostResponse struct {
+	SubscriptionID int          `json:"subscription_id"`
+	PublicID       uuid.UUID    `json:"public_id"`
+	ServiceName    string       `json:"service_name"`
+	UserID         uuid.UUID    `json:"user_id"`
+	Price          money.Amount `json:"price"`
+	Currency       string       `json:"currency"`
+	BillingPeriod  string       `json:"billing_period"`
+	Months         int          `json:"months"`
+	Cost           money.Amount `json:"cost"`
+}
+
+type CostGroupResponse struct {
+	ServiceName   *string      `json:"service_name,omitempty"`
+	Category      *string      `json:"category,omitempty"`
+	UserID        *uuid.UUID   `json:"user_id,omitempty"`
+	Month         *string      `json:"month,omitempty"`
+	Subscriptions int          `json:"subscriptions"`
+	Cost          money.Amount `json:"cost"`
 }
 
 type CalculateCostResponse struct {
-	TotalCost int `json:"total_cost"`
+	TotalCost     money.Amount                `json:"cost"`
+	Currency      string                      `json:"currency"`
+	Mode          string                      `json:"mode"`
+	Subscriptions []*SubscriptionCostResponse `json:"subscriptions,omitempty"`
+	Groups        []*CostGroupResponse        `json:"groups,omitempty"`
+}
+
+type CostBucketResponse struct {
+	Month         string       `json:"month"`
+	Cost          money.Amount `json:"cost"`
+	Subscriptions int          `json:"subscriptions"`
+}
+
+type CostTimeSeriesResponse struct {
+	TotalCost money.Amount          `json:"cost"`
+	Currency  string                `json:"currency"`
+	Mode      string                `json:"mode"`
+	Buckets   []*CostBucketResponse `json:"buckets"`
+}
+
+type ForecastRequest struct {
+	UserID      *uuid.UUID `json:"user_id,omitempty"`
+	ServiceID   *int       `json:"service_id,omitempty" validate:"omitempty,min=1"`
+	ServiceName *string    `json:"service_name,omitempty" validate:"omitempty,min=1,max=255"`
+	Category    *string    `json:"category,omitempty" validate:"omitempty,min=1,max=50"`
+	Tag         *string    `json:"tag,omitempty" validate:"omitempty,min=1,max=50"`
+	Months      int        `json:"months" validate:"required,min=1,max=60"`
+	Currency    string     `json:"currency,omitempty" validate:"omitempty,iso4217"`
+	Mode        string     `json:"mode,omitempty" validate:"omitempty,oneof=charged amortized"`
+}
+
+type ForecastServiceResponse struct {
+	ServiceName string       `json:"service_name"`
+	Cost        money.Amount `json:"cost"`
+}
+
+type ForecastMonthResponse struct {
+	Month    string                     `json:"month"`
+	Cost     money.Amount               `json:"cost"`
+	Services []*ForecastServiceResponse `json:"services"`
+}
+
+type ForecastResponse struct {
+	TotalCost money.Amount               `json:"cost"`
+	Currency  string                     `json:"currency"`
+	Mode      string                     `json:"mode"`
+	StartDate string                     `json:"start_date"`
+	EndDate   string                     `json:"end_date"`
+	Months    []*ForecastMonthResponse   `json:"months"`
+	Services  []*ForecastServiceResponse `json:"services"`
 }
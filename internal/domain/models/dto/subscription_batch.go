@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+)
+
+// SubscriptionBatchRequest is a list of operations run in one transaction. An
+// atomic batch is applied whole or not at all, otherwise every operation that
+// succeeds is kept.
+type SubscriptionBatchRequest struct {
+	Atomic     bool                         `json:"-"`
+	Operations []SubscriptionBatchOperation `json:"operations" validate:"required,min=1,max=100"`
+}
+
+// SubscriptionBatchOperation creates a subscription from Data, updates the
+// subscription ID with Data or deletes it. Data is a CreateSubscriptionRequest
+// or an UpdateSubscriptionRequest; Version works like If-Match.
+type SubscriptionBatchOperation struct {
+	Op      string          `json:"op" validate:"required,oneof=create update delete"`
+	ID      *uuid.UUID      `json:"id,omitempty" validate:"required_unless=Op create"`
+	Version *int            `json:"version,omitempty" validate:"omitempty,min=1"`
+	Data    json.RawMessage `json:"data,omitempty" validate:"required_unless=Op delete"`
+}
+
+type SubscriptionBatchResponse struct {
+	Atomic  bool                       `json:"atomic"`
+	Results []*SubscriptionBatchResult `json:"results"`
+}
+
+// SubscriptionBatchResult is the outcome of the operation at the same
+// position. Status is the HTTP status the operation would get on its own.
+// Err is the failure of the operation that Status and Error are made from.
+type SubscriptionBatchResult struct {
+	Op           string                `json:"op"`
+	Status       int                   `json:"status"`
+	ID           *uuid.UUID            `json:"id,omitempty"`
+	Error        string                `json:"error,omitempty"`
+	Subscription *SubscriptionResponse `json:"subscription,omitempty"`
+	Err          error                 `json:"-"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+// ImportSubscriptionsRequest describes the CSV file of an import. The file
+// starts with a header; Columns maps the fields service_name, price, user_id,
+// start_date and end_date to the header of the column holding them, by
+// default the field name itself. A dry run checks the file without storing
+// it.
+type ImportSubscriptionsRequest struct {
+	Delimiter string            `json:"delimiter,omitempty" validate:"omitempty,len=1"`
+	Columns   map[string]string `json:"columns,omitempty" validate:"omitempty,dive,keys,oneof=service_name price user_id start_date end_date,endkeys,min=1,max=255"`
+	DryRun    bool              `json:"dry_run,omitempty"`
+}
+
+// ImportSubscriptionsResponse counts the rows of an import file and the
+// subscriptions stored from them, or that would be stored in a dry run.
+type ImportSubscriptionsResponse struct {
+	DryRun   bool               `json:"dry_run"`
+	Rows     int                `json:"rows"`
+	Imported int64              `json:"imported"`
+	Errors   []*ImportLineError `json:"errors"`
+}
+
+// ImportLineError lists the problems of a line of an import file by field,
+// with "_error" holding those of the line as a whole.
+type ImportLineError struct {
+	Line   int                 `json:"line"`
+	Errors map[string][]string `json:"errors"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+// PauseSubscriptionRequest pauses a subscription from the month From, by
+// default from the next month.
+type PauseSubscriptionRequest struct {
+	From *string `json:"from,omitempty" validate:"omitempty,mmYYYY"`
+}
+
+// ResumeSubscriptionRequest resumes a paused subscription from the month From,
+// by default from the current month.
+type ResumeSubscriptionRequest struct {
+	From *string `json:"from,omitempty" validate:"omitempty,mmYYYY"`
+}
+
+// CancelSubscriptionRequest cancels a subscription after the month EndDate, by
+// default after the current month. An earlier end date is kept.
+type CancelSubscriptionRequest struct {
+	EndDate *string `json:"end_date,omitempty" validate:"omitempty,mmYYYY"`
+}
+
+type SubscriptionPauseResponse struct {
+	ID             int       `json:"id"`
+	SubscriptionID int       `json:"subscription_id"`
+	PausedFrom     string    `json:"paused_from"`
+	ResumedFrom    *string   `json:"resumed_from,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
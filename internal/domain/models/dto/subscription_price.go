@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"AggregationService/internal/pkg/money"
+	"time"
+)
+
+type CreateSubscriptionPriceRequest struct {
+	Price         money.Amount `json:"price" validate:"required,min=1"`
+	EffectiveFrom string       `json:"effective_from" validate:"required,mmYYYY"`
+}
+
+type SubscriptionPriceResponse struct {
+	ID             int          `json:"id"`
+	SubscriptionID int          `json:"subscription_id"`
+	Price          money.Amount `json:"price"`
+	EffectiveFrom  string       `json:"effective_from"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type CreateTenantRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=255"`
+}
+
+// CreateTenantResponse carries the API key of the new tenant. It is shown
+// only once, the service keeps just its hash.
+type CreateTenantResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	APIKey    string    `json:"api_key"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+	AuditActionPrice   = "price"
+	AuditActionPause   = "pause"
+	AuditActionResume  = "resume"
+	AuditActionCancel  = "cancel"
+)
+
+// AuditEntry records one change to a subscription. Before and After are JSON
+// snapshots of the subscription, or of the price for AuditActionPrice; Before
+// is empty for a creation. SubscriptionPublicID is nil for the entries of
+// subscriptions purged before public ids were introduced.
+type AuditEntry struct {
+	ID                   int64           `json:"id" db:"id"`
+	TenantID             uuid.UUID       `json:"-" db:"tenant_id"`
+	SubscriptionID       int             `json:"subscription_id" db:"subscription_id"`
+	SubscriptionPublicID uuid.UUID       `json:"subscription_public_id" db:"subscription_public_id"`
+	UserID               uuid.UUID       `json:"user_id" db:"user_id"`
+	Action               string          `json:"action" db:"action"`
+	Before               json.RawMessage `json:"before,omitempty" db:"before"`
+	After                json.RawMessage `json:"after,omitempty" db:"after"`
+	RequestID            string          `json:"request_id" db:"request_id"`
+	Actor                string          `json:"actor" db:"actor"`
+	CreatedAt            time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AuditFilter selects audit entries, newest first. From and To bound the
+// time of the change, both inclusive.
+type AuditFilter struct {
+	SubscriptionID       *int
+	SubscriptionPublicID *uuid.UUID
+	UserID               *uuid.UUID
+	Action               *string
+	Actor                *string
+	RequestID            *string
+	From                 *time.Time
+	To                   *time.Time
+	Limit                int
+	Offset               int
+}
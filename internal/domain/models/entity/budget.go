@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"AggregationService/internal/pkg/money"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	BudgetPeriodMonth = "month"
+	BudgetPeriodYear  = "year"
+)
+
+// DefaultBudgetThreshold alerts when the whole budget is spent.
+const DefaultBudgetThreshold = 100
+
+// Budget limits what a user spends in a calendar month or year, on the
+// catalog service ServiceID when it is set and on one category when Category
+// is set. ServiceName is the name of the service. Threshold is the percent of
+// Amount at which the budget raises an alert.
+type Budget struct {
+	ID          int          `json:"id" db:"id"`
+	TenantID    uuid.UUID    `json:"-" db:"tenant_id"`
+	UserID      uuid.UUID    `json:"user_id" db:"user_id"`
+	ServiceID   *int         `json:"service_id,omitempty" db:"service_id"`
+	ServiceName *string      `json:"service_name,omitempty" db:"service_name"`
+	Category    *string      `json:"category,omitempty" db:"category"`
+	Period      string       `json:"period" db:"period"`
+	Amount      money.Amount `json:"amount" db:"amount"`
+	Currency    string       `json:"currency" db:"currency"`
+	Threshold   int          `json:"threshold" db:"threshold"`
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// Window returns the first and the last month of the budget period containing now.
+func (b *Budget) Window(now time.Time) (time.Time, time.Time) {
+	if b.Period == BudgetPeriodYear {
+		start := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 11, 0)
+	}
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start
+}
+
+// AlertAt is the spend at which the budget raises an alert.
+func (b *Budget) AlertAt() money.Amount {
+	return b.Amount * money.Amount(b.Threshold) / 100
+}
+
+type BudgetStatus struct {
+	Budget    *Budget
+	StartDate time.Time
+	EndDate   time.Time
+	Spent     money.Amount
+}
+
+// Alerting reports whether the spend reached the alert threshold.
+func (s *BudgetStatus) Alerting() bool {
+	return s.Spent >= s.Budget.AlertAt()
+}
+
+// BudgetSnapshot is what a user had spent against each budget, by budget ID,
+// before a change to the user's subscriptions.
+type BudgetSnapshot struct {
+	UserID uuid.UUID
+	Spent  map[int]money.Amount
+}
+
+// BudgetAlert is emitted when a subscription change pushes the spend of a
+// budget over its threshold.
+type BudgetAlert struct {
+	BudgetID    int          `json:"budget_id"`
+	UserID      uuid.UUID    `json:"user_id"`
+	ServiceID   *int         `json:"service_id,omitempty"`
+	ServiceName *string      `json:"service_name,omitempty"`
+	Category    *string      `json:"category,omitempty"`
+	Period      string       `json:"period"`
+	StartDate   time.Time    `json:"start_date"`
+	Amount      money.Amount `json:"amount"`
+	Threshold   int          `json:"threshold"`
+	Spent       money.Amount `json:"spent"`
+	Currency    string       `json:"currency"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Category groups subscriptions by what they are for, like streaming or
+// cloud storage. Code is what subscriptions refer to.
+type Category struct {
+	Code      string    `json:"code" db:"code"`
+	TenantID  uuid.UUID `json:"-" db:"tenant_id"`
+	Name      string    `json:"name" db:"name"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,147 @@
+package entity
+
+import (
+	"AggregationService/internal/pkg/money"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Charge is one payment of a subscription on Date, at the price in force on
+// that day.
+type Charge struct {
+	SubscriptionID       int
+	SubscriptionPublicID uuid.UUID
+	ServiceName          string
+	UserID               uuid.UUID
+	Date                 time.Time
+	Amount               money.Amount
+	Currency             string
+}
+
+// ChargeFilter selects the charges made from the day From until the day To
+// inclusive, of one user when UserID is set.
+type ChargeFilter struct {
+	UserID *uuid.UUID
+	From   time.Time
+	To     time.Time
+}
+
+// NextChargeAt returns the first day on or after the day of t on which the
+// subscription is charged, or nil when it will not be charged again.
+func (s *Subscription) NextChargeAt(t time.Time) *time.Time {
+	var next *time.Time
+	s.walkCharges(day(t), func(charge time.Time) bool {
+		next = &charge
+		return false
+	})
+	return next
+}
+
+// ChargeDates returns the days from the day of from until the day of to
+// inclusive on which the subscription is charged.
+func (s *Subscription) ChargeDates(from, to time.Time) []time.Time {
+	last := day(to)
+	var dates []time.Time
+	s.walkCharges(day(from), func(charge time.Time) bool {
+		if charge.After(last) {
+			return false
+		}
+		dates = append(dates, charge)
+		return true
+	})
+	return dates
+}
+
+// PriceAt returns the price in force on the day t from the loaded Prices, or
+// Price when the price history is not loaded.
+func (s *Subscription) PriceAt(t time.Time) money.Amount {
+	price := s.Price
+	if len(s.Prices) == 0 {
+		return price
+	}
+	price = s.Prices[0].Price
+	for _, p := range s.Prices {
+		if p.EffectiveFrom.After(t) {
+			break
+		}
+		price = p.Price
+	}
+	return price
+}
+
+// walkCharges calls charge with the charge days of the subscription on or
+// after from in order, until charge returns false or no charge is left. Only
+// auto-renewing subscriptions are charged. Charges follow billing_factor:
+// every BillingMonths months from the start month on BillingDay, or on the
+// last day of a shorter month, and every 7 days from StartDate for weekly
+// billing. Paused months and the months after EndDate are not charged.
+func (s *Subscription) walkCharges(from time.Time, charge func(day time.Time) bool) {
+	if !s.AutoRenew {
+		return
+	}
+	start := day(s.StartDate)
+	month := monthOf(start)
+	if from.After(start) {
+		month = monthOf(from)
+	}
+	periodMonths := max(s.BillingMonths, 1)
+
+	for ; s.EndDate == nil || !month.After(*s.EndDate); month = month.AddDate(0, 1, 0) {
+		if pause := s.pauseAt(month); pause != nil {
+			if pause.ResumedFrom == nil {
+				return
+			}
+			continue
+		}
+		next := month.AddDate(0, 1, 0)
+
+		if s.BillingPeriod == BillingPeriodWeek {
+			first := month
+			for _, t := range []time.Time{from, start} {
+				if t.After(first) {
+					first = t
+				}
+			}
+			weeks := (int(first.Sub(start).Hours()/24) + 6) / 7
+			for d := start.AddDate(0, 0, 7*weeks); d.Before(next); d = d.AddDate(0, 0, 7) {
+				if !charge(d) {
+					return
+				}
+			}
+			continue
+		}
+
+		if monthsBetween(start, month)%periodMonths != 0 {
+			continue
+		}
+		d := month.AddDate(0, 0, min(max(s.BillingDay, 1), next.AddDate(0, 0, -1).Day())-1)
+		if d.Before(from) {
+			continue
+		}
+		if !charge(d) {
+			return
+		}
+	}
+}
+
+// pauseAt returns the pause the subscription is in during month, if any.
+func (s *Subscription) pauseAt(month time.Time) *SubscriptionPause {
+	for _, pause := range s.Pauses {
+		if !pause.PausedFrom.After(month) && (pause.ResumedFrom == nil || pause.ResumedFrom.After(month)) {
+			return pause
+		}
+	}
+	return nil
+}
+
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func monthOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func monthsBetween(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+}
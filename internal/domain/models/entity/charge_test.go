@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"AggregationService/internal/pkg/money"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestSubscription_ChargeDates(t *testing.T) {
+	end := date(2027, time.March, 1)
+	resumed := date(2027, time.March, 1)
+
+	tests := []struct {
+		name string
+		sub  Subscription
+		from time.Time
+		to   time.Time
+		want []time.Time
+	}{
+		{
+			name: "Monthly on billing day",
+			sub:  Subscription{BillingPeriod: BillingPeriodMonth, BillingMonths: 1, BillingDay: 15, AutoRenew: true, StartDate: date(2026, time.January, 1)},
+			from: date(2026, time.October, 17),
+			to:   date(2026, time.December, 31),
+			want: []time.Time{date(2026, time.November, 15), date(2026, time.December, 15)},
+		},
+		{
+			name: "Billing day past the end of a short month",
+			sub:  Subscription{BillingPeriod: BillingPeriodMonth, BillingMonths: 1, BillingDay: 31, AutoRenew: true, StartDate: date(2027, time.January, 1)},
+			from: date(2027, time.January, 1),
+			to:   date(2027, time.March, 31),
+			want: []time.Time{date(2027, time.January, 31), date(2027, time.February, 28), date(2027, time.March, 31)},
+		},
+		{
+			name: "Quarterly from the start month",
+			sub:  Subscription{BillingPeriod: BillingPeriodQuarter, BillingMonths: 3, BillingDay: 1, AutoRenew: true, StartDate: date(2026, time.February, 1)},
+			from: date(2026, time.October, 17),
+			to:   date(2027, time.June, 30),
+			want: []time.Time{date(2026, time.November, 1), date(2027, time.February, 1), date(2027, time.May, 1)},
+		},
+		{
+			name: "Weekly from the start date",
+			sub:  Subscription{BillingPeriod: BillingPeriodWeek, BillingMonths: 1, BillingDay: 20, AutoRenew: true, StartDate: date(2026, time.October, 1)},
+			from: date(2026, time.October, 17),
+			to:   date(2026, time.November, 5),
+			want: []time.Time{date(2026, time.October, 22), date(2026, time.October, 29), date(2026, time.November, 5)},
+		},
+		{
+			name: "No charges after the end month",
+			sub:  Subscription{BillingPeriod: BillingPeriodMonth, BillingMonths: 1, BillingDay: 10, AutoRenew: true, StartDate: date(2027, time.January, 1), EndDate: &end},
+			from: date(2027, time.January, 1),
+			to:   date(2027, time.December, 31),
+			want: []time.Time{date(2027, time.January, 10), date(2027, time.February, 10), date(2027, time.March, 10)},
+		},
+		{
+			name: "Paused months are skipped",
+			sub: Subscription{BillingPeriod: BillingPeriodMonth, BillingMonths: 1, BillingDay: 5, AutoRenew: true, StartDate: date(2027, time.January, 1),
+				Pauses: []*SubscriptionPause{{PausedFrom: date(2027, time.February, 1), ResumedFrom: &resumed}}},
+			from: date(2027, time.January, 1),
+			to:   date(2027, time.March, 31),
+			want: []time.Time{date(2027, time.January, 5), date(2027, time.March, 5)},
+		},
+		{
+			name: "Without auto-renewal",
+			sub:  Subscription{BillingPeriod: BillingPeriodMonth, BillingMonths: 1, BillingDay: 5, StartDate: date(2027, time.January, 1)},
+			from: date(2027, time.January, 1),
+			to:   date(2027, time.March, 31),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.sub.ChargeDates(tt.from, tt.to))
+		})
+	}
+}
+
+func TestSubscription_NextChargeAt(t *testing.T) {
+	sub := Subscription{BillingPeriod: BillingPeriodMonth, BillingMonths: 1, BillingDay: 17, AutoRenew: true, StartDate: date(2026, time.January, 1)}
+	assert.Equal(t, date(2026, time.October, 17), *sub.NextChargeAt(time.Date(2026, time.October, 17, 15, 30, 0, 0, time.UTC)))
+	assert.Equal(t, date(2026, time.November, 17), *sub.NextChargeAt(date(2026, time.October, 18)))
+
+	sub.Pauses = []*SubscriptionPause{{PausedFrom: date(2026, time.November, 1)}}
+	assert.Nil(t, sub.NextChargeAt(date(2026, time.October, 18)), "an open pause stops the charges")
+}
+
+func TestSubscription_PriceAt(t *testing.T) {
+	sub := Subscription{Price: money.FromMajor(399), Prices: []*SubscriptionPrice{
+		{Price: money.FromMajor(299), EffectiveFrom: date(2026, time.January, 1)},
+		{Price: money.FromMajor(399), EffectiveFrom: date(2026, time.December, 1)},
+	}}
+	assert.Equal(t, money.FromMajor(299), sub.PriceAt(date(2026, time.November, 30)))
+	assert.Equal(t, money.FromMajor(399), sub.PriceAt(date(2026, time.December, 1)))
+
+	sub.Prices = nil
+	assert.Equal(t, money.FromMajor(399), sub.PriceAt(date(2026, time.November, 30)))
+}
@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"AggregationService/internal/pkg/money"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	CostGroupByServiceName = "service_name"
+	CostGroupByUserID      = "user_id"
+	CostGroupByMonth       = "month"
+	CostGroupByCategory    = "category"
+)
+
+const (
+	// CostModeCharged counts a price in the months it is charged.
+	CostModeCharged = "charged"
+	// CostModeAmortized spreads a price evenly over the months of its period.
+	CostModeAmortized = "amortized"
+)
+
+type CostFilter struct {
+	UserID      *uuid.UUID
+	ServiceID   *int
+	ServiceName *string
+	Category    *string
+	Tag         *string
+	StartDate   time.Time
+	EndDate     time.Time
+	Currency    string
+	Mode        string
+	GroupBy     []string
+	// ActiveAt limits the costs to subscriptions active in the month of ActiveAt.
+	ActiveAt *time.Time
+}
+
+type SubscriptionCost struct {
+	SubscriptionID int          `json:"subscription_id" db:"subscription_id"`
+	PublicID       uuid.UUID    `json:"public_id" db:"public_id"`
+	ServiceName    string       `json:"service_name" db:"service_name"`
+	UserID         uuid.UUID    `json:"user_id" db:"user_id"`
+	Price          money.Amount `json:"price" db:"price"`
+	Currency       string       `json:"currency" db:"currency"`
+	BillingPeriod  string       `json:"billing_period" db:"billing_period"`
+	Months         int          `json:"months" db:"months"`
+	Cost           money.Amount `json:"cost" db:"cost"`
+}
+
+type CostGroup struct {
+	ServiceName   *string      `json:"service_name,omitempty" db:"service_name"`
+	Category      *string      `json:"category,omitempty" db:"category"`
+	UserID        *uuid.UUID   `json:"user_id,omitempty" db:"user_id"`
+	Month         *time.Time   `json:"month,omitempty" db:"month"`
+	Subscriptions int          `json:"subscriptions" db:"subscriptions"`
+	Cost          money.Amount `json:"cost" db:"cost"`
+}
+
+// CostReport holds either the per-subscription breakdown or, when the filter
+// asks for grouping, the per-group totals. Costs are in the filter's currency.
+type CostReport struct {
+	Subscriptions []*SubscriptionCost
+	Groups        []*CostGroup
+}
+
+type CostBucket struct {
+	Month         time.Time    `json:"month" db:"month"`
+	Cost          money.Amount `json:"cost" db:"cost"`
+	Subscriptions int          `json:"subscriptions" db:"subscriptions"`
+}
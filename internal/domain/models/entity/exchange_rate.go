@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// ExchangeRate is the price of one unit of Currency in DefaultCurrency,
+// valid from the first day of ValidFrom until the next rate for the currency.
+type ExchangeRate struct {
+	ID        int       `json:"id" db:"id"`
+	TenantID  uuid.UUID `json:"-" db:"tenant_id"`
+	Currency  string    `json:"currency" db:"currency"`
+	Rate      float64   `json:"rate" db:"rate"`
+	ValidFrom time.Time `json:"valid_from" db:"valid_from"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+// IdempotencyRecord is the response to the first request made with an
+// Idempotency-Key, replayed to the repeats of that request until ExpiresAt.
+// Scope is the method and path the key was used with, RequestHash the hash
+// of the request body. StatusCode is nil while the first request is running,
+// which holds the key until LockedUntil; after that the key is taken over by
+// the next request with it.
+type IdempotencyRecord struct {
+	Key         string         `db:"key"`
+	TenantID    uuid.UUID      `db:"tenant_id"`
+	Scope       string         `db:"scope"`
+	RequestHash string         `db:"request_hash"`
+	StatusCode  *int           `db:"status_code"`
+	Header      ResponseHeader `db:"header"`
+	Body        []byte         `db:"body"`
+	CreatedAt   time.Time      `db:"created_at"`
+	ExpiresAt   time.Time      `db:"expires_at"`
+	LockedUntil *time.Time     `db:"locked_until"`
+}
+
+// Completed reports whether the first request has finished and its response
+// can be replayed.
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != nil
+}
+
+// ResponseHeader keeps the replayed response headers, stored as a JSONB
+// object.
+type ResponseHeader map[string]string
+
+func (h ResponseHeader) Value() (driver.Value, error) {
+	return stringMapValue(h)
+}
+
+func (h *ResponseHeader) Scan(src any) error {
+	result, err := scanStringMap(src)
+	if err != nil {
+		return fmt.Errorf("decode response header: %w", err)
+	}
+	*h = result
+	return nil
+}
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Metadata holds the attributes clients attach to a subscription, such as an
+// account email or a cost center. It is stored as a JSONB object.
+type Metadata map[string]string
+
+// Value encodes the metadata as a JSON object, an empty one when nil.
+func (m Metadata) Value() (driver.Value, error) {
+	return stringMapValue(m)
+}
+
+func (m *Metadata) Scan(src any) error {
+	result, err := scanStringMap(src)
+	if err != nil {
+		return fmt.Errorf("decode metadata: %w", err)
+	}
+	*m = result
+	return nil
+}
+
+// stringMapValue encodes m as a JSON object, an empty one when nil.
+func stringMapValue(m map[string]string) (driver.Value, error) {
+	if m == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(m)
+}
+
+// scanStringMap decodes a JSON object of strings read from the database, an
+// empty map for NULL.
+func scanStringMap(src any) (map[string]string, error) {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		return map[string]string{}, nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return nil, fmt.Errorf("unsupported type %T", src)
+	}
+	result := map[string]string{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
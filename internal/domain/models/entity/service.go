@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Service is a catalog entry subscriptions refer to. Names are unique up to
+// case, whitespace and script, see utils.NormalizeServiceName.
+type Service struct {
+	ID             int       `json:"id" db:"id"`
+	TenantID       uuid.UUID `json:"-" db:"tenant_id"`
+	Name           string    `json:"name" db:"name"`
+	NormalizedName string    `json:"normalized_name" db:"normalized_name"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ServiceAlias is another name a service is known by. Aliases resolve to their
+// service when subscriptions are created or renamed.
+type ServiceAlias struct {
+	ID              int       `json:"id" db:"id"`
+	TenantID        uuid.UUID `json:"-" db:"tenant_id"`
+	ServiceID       int       `json:"service_id" db:"service_id"`
+	Alias           string    `json:"alias" db:"alias"`
+	NormalizedAlias string    `json:"normalized_alias" db:"normalized_alias"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+}
+
+// ServiceDuplicate is a pair of services whose names are probably the same
+// service, with the trigram similarity of their normalized names.
+type ServiceDuplicate struct {
+	ServiceID     int     `json:"service_id" db:"service_id"`
+	ServiceName   string  `json:"service_name" db:"service_name"`
+	DuplicateID   int     `json:"duplicate_id" db:"duplicate_id"`
+	DuplicateName string  `json:"duplicate_name" db:"duplicate_name"`
+	Similarity    float64 `json:"similarity" db:"similarity"`
+}
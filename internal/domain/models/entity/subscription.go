@@ -1,17 +1,119 @@
 package entity
 
 import (
+	"AggregationService/internal/pkg/money"
 	"github.com/google/uuid"
 	"time"
 )
 
+// DefaultCurrency is the currency of subscriptions created without one and the
+// base currency exchange rates are quoted against.
+const DefaultCurrency = "RUB"
+
+const (
+	BillingPeriodWeek    = "week"
+	BillingPeriodMonth   = "month"
+	BillingPeriodQuarter = "quarter"
+	BillingPeriodYear    = "year"
+	BillingPeriodCustom  = "custom"
+)
+
+// DefaultBillingDay is the day of the month subscriptions created without one
+// are charged on.
+const DefaultBillingDay = 1
+
+// DefaultCategory is the category of subscriptions created without one.
+const DefaultCategory = "other"
+
+// SubscriptionStatusExpired is never stored: an active subscription is
+// expired once the month of its end date has passed. A paused subscription is
+// stored paused from the request on but stays active until its pause starts.
+const (
+	SubscriptionStatusActive    = "active"
+	SubscriptionStatusPaused    = "paused"
+	SubscriptionStatusCancelled = "cancelled"
+	SubscriptionStatusExpired   = "expired"
+)
+
+// Subscription is addressed by PublicID, a UUIDv7, in the API. ID is the
+// internal serial id that related tables refer to.
 type Subscription struct {
-	ID          int        `json:"id" db:"id"`
-	ServiceName string     `json:"service_name" db:"service_name"`
-	Price       int        `json:"price" db:"price"`
-	UserID      uuid.UUID  `json:"user_id" db:"user_id"`
-	StartDate   time.Time  `json:"start_date" db:"start_date"`
-	EndDate     *time.Time `json:"end_date,omitempty" db:"end_date"`
-	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	ID            int                  `json:"id" db:"id"`
+	PublicID      uuid.UUID            `json:"public_id" db:"public_id"`
+	TenantID      uuid.UUID            `json:"-" db:"tenant_id"`
+	ServiceID     int                  `json:"service_id" db:"service_id"`
+	ServiceName   string               `json:"service_name" db:"service_name"`
+	Price         money.Amount         `json:"price" db:"price"`
+	Currency      string               `json:"currency" db:"currency"`
+	BillingPeriod string               `json:"billing_period" db:"billing_period"`
+	BillingMonths int                  `json:"billing_months" db:"billing_months"`
+	BillingDay    int                  `json:"billing_day" db:"billing_day"`
+	AutoRenew     bool                 `json:"auto_renew" db:"auto_renew"`
+	UserID        uuid.UUID            `json:"user_id" db:"user_id"`
+	StartDate     time.Time            `json:"start_date" db:"start_date"`
+	EndDate       *time.Time           `json:"end_date,omitempty" db:"end_date"`
+	Category      string               `json:"category" db:"category"`
+	Tags          []string             `json:"tags" db:"-"`
+	Metadata      Metadata             `json:"metadata" db:"metadata"`
+	Pauses        []*SubscriptionPause `json:"-" db:"-"`
+	Prices        []*SubscriptionPrice `json:"-" db:"-"`
+	Status        string               `json:"status" db:"status"`
+	CreatedAt     time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at" db:"updated_at"`
+	DeletedAt     *time.Time           `json:"deleted_at,omitempty" db:"deleted_at"`
+	Version       int                  `json:"version" db:"version"`
+}
+
+// SubscriptionFilter selects the subscriptions listed by GetAll. ServiceID
+// matches one service exactly, ServiceName any service containing the text.
+// Category, Tag and Status match exactly, see Subscription.StatusAt for the
+// expired status. Every pair of Metadata must be in the subscription metadata.
+// Deleted subscriptions are only listed with IncludeDeleted.
+type SubscriptionFilter struct {
+	UserID         *uuid.UUID
+	ServiceID      *int
+	ServiceName    *string
+	Category       *string
+	Tag            *string
+	Status         *string
+	Metadata       map[string]string
+	IncludeDeleted bool
+	Limit          int
+	Offset         int
+}
+
+// BillingPeriodMonths returns the length of a billing period in months. Weekly
+// billing is not month based and reports 1; custom periods take customMonths.
+func BillingPeriodMonths(period string, customMonths int) int {
+	switch period {
+	case BillingPeriodQuarter:
+		return 3
+	case BillingPeriodYear:
+		return 12
+	case BillingPeriodCustom:
+		return customMonths
+	default:
+		return 1
+	}
+}
+
+// StatusAt returns the status of the subscription at t: the stored status,
+// SubscriptionStatusExpired for an active subscription whose last month is
+// before the month of t, or SubscriptionStatusActive for a paused one whose
+// open pause in Pauses starts after the month of t.
+func (s *Subscription) StatusAt(t time.Time) string {
+	monthStart := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	switch s.Status {
+	case SubscriptionStatusActive:
+		if s.EndDate != nil && s.EndDate.Before(monthStart) {
+			return SubscriptionStatusExpired
+		}
+	case SubscriptionStatusPaused:
+		for _, pause := range s.Pauses {
+			if pause.ResumedFrom == nil && pause.PausedFrom.After(monthStart) {
+				return SubscriptionStatusActive
+			}
+		}
+	}
+	return s.Status
 }
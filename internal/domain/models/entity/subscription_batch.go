@@ -0,0 +1,27 @@
+package entity
+
+import "github.com/google/uuid"
+
+// Actions of a SubscriptionBatchItem.
+const (
+	BatchActionCreate = "create"
+	BatchActionUpdate = "update"
+	BatchActionDelete = "delete"
+)
+
+// SubscriptionBatchItem is one operation of a batch. Subscription is the
+// subscription to create. An update or delete names its subscription by the
+// public ID, at Version when it is set, and the batch reads it within its
+// transaction; Apply makes the changes of an update to it and returns the new
+// price period, if any. Once applied, Subscription is the subscription the
+// operation left and Price the price period it added. Err is the failure of
+// the operation.
+type SubscriptionBatchItem struct {
+	Action       string
+	ID           uuid.UUID
+	Version      *int
+	Apply        func(sub *Subscription) (*SubscriptionPrice, error)
+	Subscription *Subscription
+	Price        *SubscriptionPrice
+	Err          error
+}
@@ -0,0 +1,22 @@
+package entity
+
+// SubscriptionImportRow is a subscription read from line Line of an import
+// file.
+type SubscriptionImportRow struct {
+	Line         int
+	Subscription *Subscription
+}
+
+// SubscriptionImportRejection is a row of an import that was valid on its own
+// but was not stored, and why.
+type SubscriptionImportRejection struct {
+	Line int
+	Err  error
+}
+
+// SubscriptionImportReport tells how many rows an import stored, or would
+// store in a dry run, and which rows it rejected.
+type SubscriptionImportReport struct {
+	Imported int64
+	Rejected []*SubscriptionImportRejection
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// SubscriptionPause is a pause of a subscription from the first day of
+// PausedFrom until the month before ResumedFrom, or until it is resumed while
+// ResumedFrom is nil. Paused months cost nothing.
+type SubscriptionPause struct {
+	ID             int        `json:"id" db:"id"`
+	SubscriptionID int        `json:"subscription_id" db:"subscription_id"`
+	PausedFrom     time.Time  `json:"paused_from" db:"paused_from"`
+	ResumedFrom    *time.Time `json:"resumed_from,omitempty" db:"resumed_from"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"AggregationService/internal/pkg/money"
+	"time"
+)
+
+// SubscriptionPrice is the price of a subscription from the first day of
+// EffectiveFrom until the next price of the same subscription.
+type SubscriptionPrice struct {
+	ID             int          `json:"id" db:"id"`
+	SubscriptionID int          `json:"subscription_id" db:"subscription_id"`
+	Price          money.Amount `json:"price" db:"price"`
+	EffectiveFrom  time.Time    `json:"effective_from" db:"effective_from"`
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscription_StatusAt(t *testing.T) {
+	now := date(2026, time.October, 17)
+	ended := date(2026, time.September, 1)
+	thisMonth := date(2026, time.October, 1)
+	nextMonth := date(2026, time.November, 1)
+
+	tests := []struct {
+		name string
+		sub  Subscription
+		want string
+	}{
+		{name: "Active", sub: Subscription{Status: SubscriptionStatusActive}, want: SubscriptionStatusActive},
+		{name: "Ended last month", sub: Subscription{Status: SubscriptionStatusActive, EndDate: &ended}, want: SubscriptionStatusExpired},
+		{name: "Ends this month", sub: Subscription{Status: SubscriptionStatusActive, EndDate: &thisMonth}, want: SubscriptionStatusActive},
+		{
+			name: "Paused from this month",
+			sub:  Subscription{Status: SubscriptionStatusPaused, Pauses: []*SubscriptionPause{{PausedFrom: thisMonth}}},
+			want: SubscriptionStatusPaused,
+		},
+		{
+			name: "Pause starts next month",
+			sub:  Subscription{Status: SubscriptionStatusPaused, Pauses: []*SubscriptionPause{{PausedFrom: nextMonth}}},
+			want: SubscriptionStatusActive,
+		},
+		{
+			name: "Earlier pause is closed",
+			sub: Subscription{Status: SubscriptionStatusPaused, Pauses: []*SubscriptionPause{
+				{PausedFrom: date(2026, time.January, 1), ResumedFrom: &ended},
+				{PausedFrom: thisMonth},
+			}},
+			want: SubscriptionStatusPaused,
+		},
+		{name: "Cancelled", sub: Subscription{Status: SubscriptionStatusCancelled, EndDate: &ended}, want: SubscriptionStatusCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.sub.StatusAt(now))
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package events
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	"context"
+)
+
+//go:generate mockery --name=IBudgetAlertPublisher --output=./mocks --case=underscore
+type IBudgetAlertPublisher interface {
+	Publish(ctx context.Context, alert *entity.BudgetAlert) error
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	"context"
+)
+
+//go:generate mockery --name=IAuditRepository --output=./mocks --case=underscore
+type IAuditRepository interface {
+	Record(ctx context.Context, entry *entity.AuditEntry) error
+	GetAll(ctx context.Context, filter *entity.AuditFilter) ([]*entity.AuditEntry, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	"context"
+	"github.com/google/uuid"
+)
+
+//go:generate mockery --name=IBudgetRepository --output=./mocks --case=underscore
+type IBudgetRepository interface {
+	Create(ctx context.Context, budget *entity.Budget) (*entity.Budget, error)
+	GetByID(ctx context.Context, id int) (*entity.Budget, error)
+	GetAll(ctx context.Context, userID *uuid.UUID) ([]*entity.Budget, error)
+	Update(ctx context.Context, budget *entity.Budget) (*entity.Budget, error)
+	Delete(ctx context.Context, id int) error
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	"context"
+)
+
+//go:generate mockery --name=ICategoryRepository --output=./mocks --case=underscore
+type ICategoryRepository interface {
+	Create(ctx context.Context, category *entity.Category) (*entity.Category, error)
+	GetAll(ctx context.Context) ([]*entity.Category, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	"context"
+)
+
+//go:generate mockery --name=IExchangeRateRepository --output=./mocks --case=underscore
+type IExchangeRateRepository interface {
+	Upsert(ctx context.Context, rate *entity.ExchangeRate) (*entity.ExchangeRate, error)
+	GetAll(ctx context.Context, currency *string) ([]*entity.ExchangeRate, error)
+	Delete(ctx context.Context, id int) error
+}
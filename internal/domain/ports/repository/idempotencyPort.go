@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	"context"
+	"time"
+)
+
+//go:generate mockery --name=IIdempotencyRepository --output=./mocks --case=underscore
+type IIdempotencyRepository interface {
+	Reserve(ctx context.Context, record *entity.IdempotencyRecord) (*entity.IdempotencyRecord, error)
+	Complete(ctx context.Context, record *entity.IdempotencyRecord) error
+	Release(ctx context.Context, scope string, key string) error
+	PurgeExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -18,27 +18,137 @@ type ISubscriptionRepository struct {
 	mock.Mock
 }
 
-// CalculateCost provides a mock function with given fields: ctx, userID, serviceName, startDate, endDate
-func (_m *ISubscriptionRepository) CalculateCost(ctx context.Context, userID *uuid.UUID, serviceName *string, startDate *time.Time, endDate *time.Time) (int, error) {
-	ret := _m.Called(ctx, userID, serviceName, startDate, endDate)
+// AddPrice provides a mock function with given fields: ctx, price
+func (_m *ISubscriptionRepository) AddPrice(ctx context.Context, price *entity.SubscriptionPrice) (*entity.SubscriptionPrice, error) {
+	ret := _m.Called(ctx, price)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddPrice")
+	}
+
+	var r0 *entity.SubscriptionPrice
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.SubscriptionPrice) (*entity.SubscriptionPrice, error)); ok {
+		return rf(ctx, price)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.SubscriptionPrice) *entity.SubscriptionPrice); ok {
+		r0 = rf(ctx, price)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.SubscriptionPrice)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.SubscriptionPrice) error); ok {
+		r1 = rf(ctx, price)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Batch provides a mock function with given fields: ctx, items, atomic
+func (_m *ISubscriptionRepository) Batch(ctx context.Context, items []*entity.SubscriptionBatchItem, atomic bool) error {
+	ret := _m.Called(ctx, items, atomic)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Batch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*entity.SubscriptionBatchItem, bool) error); ok {
+		r0 = rf(ctx, items, atomic)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CalculateCost provides a mock function with given fields: ctx, filter
+func (_m *ISubscriptionRepository) CalculateCost(ctx context.Context, filter *entity.CostFilter) (*entity.CostReport, error) {
+	ret := _m.Called(ctx, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CalculateCost")
 	}
 
-	var r0 int
+	var r0 *entity.CostReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.CostFilter) (*entity.CostReport, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.CostFilter) *entity.CostReport); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.CostReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.CostFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Cancel provides a mock function with given fields: ctx, id, endDate, version
+func (_m *ISubscriptionRepository) Cancel(ctx context.Context, id int, endDate time.Time, version int) (*entity.Subscription, error) {
+	ret := _m.Called(ctx, id, endDate, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Cancel")
+	}
+
+	var r0 *entity.Subscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, int) (*entity.Subscription, error)); ok {
+		return rf(ctx, id, endDate, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, int) *entity.Subscription); ok {
+		r0 = rf(ctx, id, endDate, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Subscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time, int) error); ok {
+		r1 = rf(ctx, id, endDate, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CostTimeSeries provides a mock function with given fields: ctx, filter
+func (_m *ISubscriptionRepository) CostTimeSeries(ctx context.Context, filter *entity.CostFilter) ([]*entity.CostBucket, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CostTimeSeries")
+	}
+
+	var r0 []*entity.CostBucket
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *uuid.UUID, *string, *time.Time, *time.Time) (int, error)); ok {
-		return rf(ctx, userID, serviceName, startDate, endDate)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.CostFilter) ([]*entity.CostBucket, error)); ok {
+		return rf(ctx, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *uuid.UUID, *string, *time.Time, *time.Time) int); ok {
-		r0 = rf(ctx, userID, serviceName, startDate, endDate)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.CostFilter) []*entity.CostBucket); ok {
+		r0 = rf(ctx, filter)
 	} else {
-		r0 = ret.Get(0).(int)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.CostBucket)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *uuid.UUID, *string, *time.Time, *time.Time) error); ok {
-		r1 = rf(ctx, userID, serviceName, startDate, endDate)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.CostFilter) error); ok {
+		r1 = rf(ctx, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -76,17 +186,17 @@ func (_m *ISubscriptionRepository) Create(ctx context.Context, subscription *ent
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: ctx, id
-func (_m *ISubscriptionRepository) Delete(ctx context.Context, id int) error {
-	ret := _m.Called(ctx, id)
+// Delete provides a mock function with given fields: ctx, id, version
+func (_m *ISubscriptionRepository) Delete(ctx context.Context, id int, version int) error {
+	ret := _m.Called(ctx, id, version)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Delete")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, id, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -94,9 +204,9 @@ func (_m *ISubscriptionRepository) Delete(ctx context.Context, id int) error {
 	return r0
 }
 
-// GetAll provides a mock function with given fields: ctx, userID, serviceName, limit, offset
-func (_m *ISubscriptionRepository) GetAll(ctx context.Context, userID *uuid.UUID, serviceName *string, limit int, offset int) ([]*entity.Subscription, error) {
-	ret := _m.Called(ctx, userID, serviceName, limit, offset)
+// GetAll provides a mock function with given fields: ctx, filter
+func (_m *ISubscriptionRepository) GetAll(ctx context.Context, filter *entity.SubscriptionFilter) ([]*entity.Subscription, error) {
+	ret := _m.Called(ctx, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAll")
@@ -104,19 +214,19 @@ func (_m *ISubscriptionRepository) GetAll(ctx context.Context, userID *uuid.UUID
 
 	var r0 []*entity.Subscription
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *uuid.UUID, *string, int, int) ([]*entity.Subscription, error)); ok {
-		return rf(ctx, userID, serviceName, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.SubscriptionFilter) ([]*entity.Subscription, error)); ok {
+		return rf(ctx, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *uuid.UUID, *string, int, int) []*entity.Subscription); ok {
-		r0 = rf(ctx, userID, serviceName, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.SubscriptionFilter) []*entity.Subscription); ok {
+		r0 = rf(ctx, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*entity.Subscription)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *uuid.UUID, *string, int, int) error); ok {
-		r1 = rf(ctx, userID, serviceName, limit, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.SubscriptionFilter) error); ok {
+		r1 = rf(ctx, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -125,7 +235,7 @@ func (_m *ISubscriptionRepository) GetAll(ctx context.Context, userID *uuid.UUID
 }
 
 // GetByID provides a mock function with given fields: ctx, id
-func (_m *ISubscriptionRepository) GetByID(ctx context.Context, id int) (*entity.Subscription, error) {
+func (_m *ISubscriptionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Subscription, error) {
 	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
@@ -134,10 +244,10 @@ func (_m *ISubscriptionRepository) GetByID(ctx context.Context, id int) (*entity
 
 	var r0 *entity.Subscription
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) (*entity.Subscription, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entity.Subscription, error)); ok {
 		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) *entity.Subscription); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entity.Subscription); ok {
 		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
@@ -145,6 +255,94 @@ func (_m *ISubscriptionRepository) GetByID(ctx context.Context, id int) (*entity
 		}
 	}
 
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPauses provides a mock function with given fields: ctx, subscriptionID
+func (_m *ISubscriptionRepository) GetPauses(ctx context.Context, subscriptionID int) ([]*entity.SubscriptionPause, error) {
+	ret := _m.Called(ctx, subscriptionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPauses")
+	}
+
+	var r0 []*entity.SubscriptionPause
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]*entity.SubscriptionPause, error)); ok {
+		return rf(ctx, subscriptionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*entity.SubscriptionPause); ok {
+		r0 = rf(ctx, subscriptionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.SubscriptionPause)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, subscriptionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPrices provides a mock function with given fields: ctx, subscriptionID
+func (_m *ISubscriptionRepository) GetPrices(ctx context.Context, subscriptionID int) ([]*entity.SubscriptionPrice, error) {
+	ret := _m.Called(ctx, subscriptionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPrices")
+	}
+
+	var r0 []*entity.SubscriptionPrice
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]*entity.SubscriptionPrice, error)); ok {
+		return rf(ctx, subscriptionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*entity.SubscriptionPrice); ok {
+		r0 = rf(ctx, subscriptionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.SubscriptionPrice)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, subscriptionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPublicID provides a mock function with given fields: ctx, id
+func (_m *ISubscriptionRepository) GetPublicID(ctx context.Context, id int) (uuid.UUID, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPublicID")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (uuid.UUID, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) uuid.UUID); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(uuid.UUID)
+	}
+
 	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
 		r1 = rf(ctx, id)
 	} else {
@@ -154,9 +352,215 @@ func (_m *ISubscriptionRepository) GetByID(ctx context.Context, id int) (*entity
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: ctx, subscription
-func (_m *ISubscriptionRepository) Update(ctx context.Context, subscription *entity.Subscription) (*entity.Subscription, error) {
-	ret := _m.Called(ctx, subscription)
+// GetRenewing provides a mock function with given fields: ctx, filter
+func (_m *ISubscriptionRepository) GetRenewing(ctx context.Context, filter *entity.ChargeFilter) ([]*entity.Subscription, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRenewing")
+	}
+
+	var r0 []*entity.Subscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ChargeFilter) ([]*entity.Subscription, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ChargeFilter) []*entity.Subscription); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Subscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.ChargeFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Import provides a mock function with given fields: ctx, next, dryRun
+func (_m *ISubscriptionRepository) Import(ctx context.Context, next func() (*entity.SubscriptionImportRow, error), dryRun bool) (*entity.SubscriptionImportReport, error) {
+	ret := _m.Called(ctx, next, dryRun)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Import")
+	}
+
+	var r0 *entity.SubscriptionImportReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, func() (*entity.SubscriptionImportRow, error), bool) (*entity.SubscriptionImportReport, error)); ok {
+		return rf(ctx, next, dryRun)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, func() (*entity.SubscriptionImportRow, error), bool) *entity.SubscriptionImportReport); ok {
+		r0 = rf(ctx, next, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.SubscriptionImportReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, func() (*entity.SubscriptionImportRow, error), bool) error); ok {
+		r1 = rf(ctx, next, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Pause provides a mock function with given fields: ctx, pause, version
+func (_m *ISubscriptionRepository) Pause(ctx context.Context, pause *entity.SubscriptionPause, version int) (*entity.Subscription, error) {
+	ret := _m.Called(ctx, pause, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Pause")
+	}
+
+	var r0 *entity.Subscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.SubscriptionPause, int) (*entity.Subscription, error)); ok {
+		return rf(ctx, pause, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.SubscriptionPause, int) *entity.Subscription); ok {
+		r0 = rf(ctx, pause, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Subscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.SubscriptionPause, int) error); ok {
+		r1 = rf(ctx, pause, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Purge provides a mock function with given fields: ctx, deletedBefore
+func (_m *ISubscriptionRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ret := _m.Called(ctx, deletedBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Purge")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, deletedBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, deletedBefore)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, deletedBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RebuildRollup provides a mock function with given fields: ctx, horizon
+func (_m *ISubscriptionRepository) RebuildRollup(ctx context.Context, horizon time.Time) (int64, error) {
+	ret := _m.Called(ctx, horizon)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RebuildRollup")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, horizon)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, horizon)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, horizon)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *ISubscriptionRepository) Restore(ctx context.Context, id uuid.UUID) (*entity.Subscription, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 *entity.Subscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entity.Subscription, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entity.Subscription); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Subscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Resume provides a mock function with given fields: ctx, id, from, version
+func (_m *ISubscriptionRepository) Resume(ctx context.Context, id int, from time.Time, version int) (*entity.Subscription, error) {
+	ret := _m.Called(ctx, id, from, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resume")
+	}
+
+	var r0 *entity.Subscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, int) (*entity.Subscription, error)); ok {
+		return rf(ctx, id, from, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, int) *entity.Subscription); ok {
+		r0 = rf(ctx, id, from, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Subscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time, int) error); ok {
+		r1 = rf(ctx, id, from, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, subscription, price
+func (_m *ISubscriptionRepository) Update(ctx context.Context, subscription *entity.Subscription, price *entity.SubscriptionPrice) (*entity.Subscription, error) {
+	ret := _m.Called(ctx, subscription, price)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Update")
@@ -164,19 +568,19 @@ func (_m *ISubscriptionRepository) Update(ctx context.Context, subscription *ent
 
 	var r0 *entity.Subscription
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *entity.Subscription) (*entity.Subscription, error)); ok {
-		return rf(ctx, subscription)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Subscription, *entity.SubscriptionPrice) (*entity.Subscription, error)); ok {
+		return rf(ctx, subscription, price)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *entity.Subscription) *entity.Subscription); ok {
-		r0 = rf(ctx, subscription)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Subscription, *entity.SubscriptionPrice) *entity.Subscription); ok {
+		r0 = rf(ctx, subscription, price)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.Subscription)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *entity.Subscription) error); ok {
-		r1 = rf(ctx, subscription)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Subscription, *entity.SubscriptionPrice) error); ok {
+		r1 = rf(ctx, subscription, price)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	"context"
+)
+
+//go:generate mockery --name=IServiceRepository --output=./mocks --case=underscore
+type IServiceRepository interface {
+	Create(ctx context.Context, service *entity.Service) (*entity.Service, error)
+	GetByID(ctx context.Context, id int) (*entity.Service, error)
+	GetAll(ctx context.Context, key *string) ([]*entity.Service, error)
+	Update(ctx context.Context, service *entity.Service) (*entity.Service, error)
+	Delete(ctx context.Context, id int) error
+	Resolve(ctx context.Context, key string) (*entity.Service, error)
+	AddAlias(ctx context.Context, alias *entity.ServiceAlias) (*entity.ServiceAlias, error)
+	GetAliases(ctx context.Context, serviceID int) ([]*entity.ServiceAlias, error)
+	DeleteAlias(ctx context.Context, serviceID, aliasID int) error
+	SuggestDuplicates(ctx context.Context, minSimilarity float64, limit int) ([]*entity.ServiceDuplicate, error)
+	Merge(ctx context.Context, targetID, sourceID int) (*entity.Service, error)
+}
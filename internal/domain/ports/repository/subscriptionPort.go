@@ -10,9 +10,23 @@ import (
 //go:generate mockery --name=ISubscriptionRepository --output=./mocks --case=underscore
 type ISubscriptionRepository interface {
 	Create(ctx context.Context, subscription *entity.Subscription) (*entity.Subscription, error)
-	GetByID(ctx context.Context, id int) (*entity.Subscription, error)
-	GetAll(ctx context.Context, userID *uuid.UUID, serviceName *string, limit, offset int) ([]*entity.Subscription, error)
-	Update(ctx context.Context, subscription *entity.Subscription) (*entity.Subscription, error)
-	Delete(ctx context.Context, id int) error
-	CalculateCost(ctx context.Context, userID *uuid.UUID, serviceName *string, startDate, endDate *time.Time) (int, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Subscription, error)
+	GetPublicID(ctx context.Context, id int) (uuid.UUID, error)
+	GetAll(ctx context.Context, filter *entity.SubscriptionFilter) ([]*entity.Subscription, error)
+	Update(ctx context.Context, subscription *entity.Subscription, price *entity.SubscriptionPrice) (*entity.Subscription, error)
+	Delete(ctx context.Context, id int, version int) error
+	Batch(ctx context.Context, items []*entity.SubscriptionBatchItem, atomic bool) error
+	Import(ctx context.Context, next func() (*entity.SubscriptionImportRow, error), dryRun bool) (*entity.SubscriptionImportReport, error)
+	Restore(ctx context.Context, id uuid.UUID) (*entity.Subscription, error)
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+	Pause(ctx context.Context, pause *entity.SubscriptionPause, version int) (*entity.Subscription, error)
+	Resume(ctx context.Context, id int, from time.Time, version int) (*entity.Subscription, error)
+	Cancel(ctx context.Context, id int, endDate time.Time, version int) (*entity.Subscription, error)
+	GetPauses(ctx context.Context, subscriptionID int) ([]*entity.SubscriptionPause, error)
+	AddPrice(ctx context.Context, price *entity.SubscriptionPrice) (*entity.SubscriptionPrice, error)
+	GetPrices(ctx context.Context, subscriptionID int) ([]*entity.SubscriptionPrice, error)
+	GetRenewing(ctx context.Context, filter *entity.ChargeFilter) ([]*entity.Subscription, error)
+	CalculateCost(ctx context.Context, filter *entity.CostFilter) (*entity.CostReport, error)
+	CostTimeSeries(ctx context.Context, filter *entity.CostFilter) ([]*entity.CostBucket, error)
+	RebuildRollup(ctx context.Context, horizon time.Time) (int64, error)
 }
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	"context"
+)
+
+//go:generate mockery --name=ITenantRepository --output=./mocks --case=underscore
+type ITenantRepository interface {
+	Create(ctx context.Context, tenant *entity.Tenant, keyHash string) (*entity.Tenant, error)
+	GetByKeyHash(ctx context.Context, keyHash string) (*entity.Tenant, error)
+	GetAll(ctx context.Context) ([]*entity.Tenant, error)
+}
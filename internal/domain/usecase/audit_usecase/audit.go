@@ -0,0 +1,37 @@
+package audit_usecase
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"context"
+	"fmt"
+	"time"
+)
+
+// GetAll lists the audit trail, newest change first.
+func (u *auditUseCase) GetAll(ctx context.Context, req *dto.ListAuditRequest) ([]*dto.AuditEntryResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to get audit entries: %+v", req))
+
+	if err := u.validator.Validate(req); err != nil {
+		log.Error(fmt.Sprintf("invalid input: %v", custom_err.ErrInvalidRequest))
+		return nil, custom_err.ErrInvalidRequest
+	}
+	if req.From != nil && req.To != nil && req.To.Before(*req.From) {
+		log.Error(fmt.Sprintf("invalid audit period: %v", custom_err.ErrInvalidRequest))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	entries, err := u.auditRepository.GetAll(ctx, u.converter.ToAuditFilter(req))
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get audit entries: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success getting audit entries: %d", len(entries)))
+	return u.converter.ToAuditEntryDTOs(entries), nil
+}
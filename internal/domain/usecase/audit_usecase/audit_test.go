@@ -0,0 +1,105 @@
+package audit_usecase
+
+import (
+	"AggregationService/internal/converters"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/domain/ports/repository/mocks"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/validation"
+)
+
+func Test_GetAllAudit(t *testing.T) {
+	t.Parallel()
+
+	subscriptionID := 1
+	action := "update"
+	unknownAction := "rename"
+	from := time.Date(2025, time.November, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	tests := []struct {
+		name       string
+		input      dto.ListAuditRequest
+		setupMocks func(repo *mocks.IAuditRepository)
+		wantCount  int
+		wantErr    error
+	}{
+		{
+			name:  "Default limit",
+			input: dto.ListAuditRequest{SubscriptionID: &subscriptionID},
+			setupMocks: func(repo *mocks.IAuditRepository) {
+				repo.On("GetAll", mock.Anything, mock.MatchedBy(func(filter *entity.AuditFilter) bool {
+					return *filter.SubscriptionID == 1 && filter.Limit == 100
+				})).Return([]*entity.AuditEntry{
+					{ID: 2, SubscriptionID: 1, Action: entity.AuditActionUpdate},
+					{ID: 1, SubscriptionID: 1, Action: entity.AuditActionCreate},
+				}, nil)
+			},
+			wantCount: 2,
+		},
+		{
+			name:  "Filtered period",
+			input: dto.ListAuditRequest{Action: &action, From: &from, To: &to, Limit: 10},
+			setupMocks: func(repo *mocks.IAuditRepository) {
+				repo.On("GetAll", mock.Anything, mock.MatchedBy(func(filter *entity.AuditFilter) bool {
+					return *filter.Action == action && filter.From.Equal(from) && filter.To.Equal(to) && filter.Limit == 10
+				})).Return([]*entity.AuditEntry{}, nil)
+			},
+			wantCount: 0,
+		},
+		{
+			name:       "Unknown action",
+			input:      dto.ListAuditRequest{Action: &unknownAction},
+			setupMocks: func(repo *mocks.IAuditRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name:       "Period ends before it starts",
+			input:      dto.ListAuditRequest{From: &to, To: &from},
+			setupMocks: func(repo *mocks.IAuditRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name:       "Limit too large",
+			input:      dto.ListAuditRequest{Limit: 1000},
+			setupMocks: func(repo *mocks.IAuditRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name:  "Repository error",
+			input: dto.ListAuditRequest{},
+			setupMocks: func(repo *mocks.IAuditRepository) {
+				repo.On("GetAll", mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+			},
+			wantErr: custom_err.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewIAuditRepository(t)
+			validator, _ := validation.New()
+			useCase := New(mockRepo, validator, converters.NewAuditConverter())
+
+			tt.setupMocks(mockRepo)
+
+			entries, err := useCase.GetAll(context.Background(), &tt.input)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, entries, tt.wantCount)
+			}
+		})
+	}
+}
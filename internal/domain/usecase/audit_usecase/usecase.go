@@ -0,0 +1,31 @@
+package audit_usecase
+
+import (
+	"AggregationService/internal/converters"
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/ports/repository"
+	"AggregationService/internal/pkg/validation"
+	"context"
+)
+
+type IAuditUseCase interface {
+	GetAll(ctx context.Context, req *dto.ListAuditRequest) ([]*dto.AuditEntryResponse, error)
+}
+
+type auditUseCase struct {
+	auditRepository repository.IAuditRepository
+	validator       *validation.Validator
+	converter       *converters.AuditConverter
+}
+
+func New(
+	auditRepository repository.IAuditRepository,
+	validator *validation.Validator,
+	converter *converters.AuditConverter,
+) IAuditUseCase {
+	return &auditUseCase{
+		auditRepository: auditRepository,
+		validator:       validator,
+		converter:       converter,
+	}
+}
@@ -0,0 +1,73 @@
+package budget_usecase
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/pkg/money"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Snapshot records the current spend of every budget of the user so that
+// AlertCrossed can tell which budgets a subscription change pushed over.
+func (u *budgetUseCase) Snapshot(ctx context.Context, userID uuid.UUID) (*entity.BudgetSnapshot, error) {
+	budgets, err := u.budgetRepository.GetAll(ctx, &userID)
+	if err != nil {
+		return nil, fmt.Errorf("get budgets: %w", err)
+	}
+
+	snapshot := &entity.BudgetSnapshot{UserID: userID, Spent: make(map[int]money.Amount, len(budgets))}
+	now := time.Now()
+	for _, budget := range budgets {
+		status, err := u.status(ctx, budget, now)
+		if err != nil {
+			return nil, fmt.Errorf("budget %d status: %w", budget.ID, err)
+		}
+		snapshot.Spent[budget.ID] = status.Spent
+	}
+	return snapshot, nil
+}
+
+// AlertCrossed publishes an alert for every budget in the snapshot that was
+// below its threshold then and has reached it now.
+func (u *budgetUseCase) AlertCrossed(ctx context.Context, before *entity.BudgetSnapshot) error {
+	budgets, err := u.budgetRepository.GetAll(ctx, &before.UserID)
+	if err != nil {
+		return fmt.Errorf("get budgets: %w", err)
+	}
+
+	now := time.Now()
+	for _, budget := range budgets {
+		spentBefore, ok := before.Spent[budget.ID]
+		if !ok || spentBefore >= budget.AlertAt() {
+			continue
+		}
+		status, err := u.status(ctx, budget, now)
+		if err != nil {
+			return fmt.Errorf("budget %d status: %w", budget.ID, err)
+		}
+		if !status.Alerting() {
+			continue
+		}
+
+		alert := &entity.BudgetAlert{
+			BudgetID:    budget.ID,
+			UserID:      budget.UserID,
+			ServiceID:   budget.ServiceID,
+			ServiceName: budget.ServiceName,
+			Category:    budget.Category,
+			Period:      budget.Period,
+			StartDate:   status.StartDate,
+			Amount:      budget.Amount,
+			Threshold:   budget.Threshold,
+			Spent:       status.Spent,
+			Currency:    budget.Currency,
+			CreatedAt:   now,
+		}
+		if err = u.alertPublisher.Publish(ctx, alert); err != nil {
+			return fmt.Errorf("publish alert for budget %d: %w", budget.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package budget_usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/pkg/money"
+)
+
+func Test_AlertCrossed(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	netflix, spotify := 2, 3
+	budgets := []*entity.Budget{
+		{ID: 1, UserID: userID, Period: "month", Amount: money.FromMajor(1000), Currency: "RUB", Threshold: 100},
+		{ID: 2, UserID: userID, ServiceID: &netflix, Period: "month", Amount: money.FromMajor(500), Currency: "RUB", Threshold: 80},
+		{ID: 3, UserID: userID, ServiceID: &spotify, Period: "month", Amount: money.FromMajor(300), Currency: "RUB", Threshold: 100},
+	}
+	isBudget := func(serviceID *int) interface{} {
+		return mock.MatchedBy(func(f *entity.CostFilter) bool {
+			if serviceID == nil {
+				return f.ServiceID == nil
+			}
+			return f.ServiceID != nil && *f.ServiceID == *serviceID
+		})
+	}
+
+	useCase, deps := newTestUseCase(t)
+	deps.budgets.On("GetAll", mock.Anything, &userID).Return(budgets, nil)
+	deps.subscriptions.On("CalculateCost", mock.Anything, isBudget(nil)).Return(costOf(money.FromMajor(1100)), nil)
+	deps.subscriptions.On("CalculateCost", mock.Anything, isBudget(&netflix)).Return(costOf(money.FromMajor(390)), nil)
+	deps.publisher.On("Publish", mock.Anything, mock.MatchedBy(func(a *entity.BudgetAlert) bool {
+		return a.BudgetID == 1 && a.Spent == money.FromMajor(1100) && a.UserID == userID
+	})).Return(nil).Once()
+
+	// budget 1 crosses 1000, budget 2 stays below 80% of 500 and budget 3
+	// was already over its limit before the change
+	before := &entity.BudgetSnapshot{
+		UserID: userID,
+		Spent: map[int]money.Amount{
+			1: money.FromMajor(900),
+			2: money.FromMajor(300),
+			3: money.FromMajor(350),
+		},
+	}
+	err := useCase.AlertCrossed(context.Background(), before)
+	assert.NoError(t, err)
+}
+
+func Test_Snapshot(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	useCase, deps := newTestUseCase(t)
+	deps.budgets.On("GetAll", mock.Anything, &userID).Return([]*entity.Budget{
+		{ID: 7, UserID: userID, Period: "year", Amount: money.FromMajor(5000), Currency: "RUB", Threshold: 100},
+	}, nil)
+	deps.subscriptions.On("CalculateCost", mock.Anything, mock.Anything).Return(costOf(money.FromMajor(1200)), nil)
+
+	snapshot, err := useCase.Snapshot(context.Background(), userID)
+	assert.NoError(t, err)
+	assert.Equal(t, userID, snapshot.UserID)
+	assert.Equal(t, map[int]money.Amount{7: money.FromMajor(1200)}, snapshot.Spent)
+}
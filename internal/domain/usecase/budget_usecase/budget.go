@@ -0,0 +1,249 @@
+package budget_usecase
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"AggregationService/internal/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+func (u *budgetUseCase) Create(ctx context.Context, req *dto.CreateBudgetRequest) (*dto.BudgetResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to create budget: %+v", req))
+
+	if err := u.validator.Validate(req); err != nil {
+		log.Error(fmt.Sprintf("invalid input: %v", custom_err.ErrInvalidRequest))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	budget := u.converter.ToBudgetEntity(req)
+	budget.CreatedAt = time.Now()
+	budget.UpdatedAt = budget.CreatedAt
+
+	if err := u.resolveService(ctx, budget); err != nil {
+		log.Error(fmt.Sprintf("failed to resolve budget service: %v", err))
+		return nil, err
+	}
+
+	created, err := u.budgetRepository.Create(ctx, budget)
+	if err != nil {
+		if errors.Is(err, custom_err.ErrServiceNotFound) {
+			log.Error(fmt.Sprintf("unknown service: %v", err))
+			return nil, custom_err.ErrServiceNotFound
+		}
+		if errors.Is(err, custom_err.ErrCategoryNotFound) {
+			log.Error(fmt.Sprintf("unknown category: %v", err))
+			return nil, custom_err.ErrCategoryNotFound
+		}
+		log.Error(fmt.Sprintf("failed to create budget: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success creating budget: id=%d", created.ID))
+	return u.converter.ToBudgetDTO(created), nil
+}
+
+func (u *budgetUseCase) GetByID(ctx context.Context, id int) (*dto.BudgetResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to get budget by id: %d", id))
+
+	budget, err := u.getBudget(ctx, id)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get budget by id: %v", err))
+		return nil, err
+	}
+
+	log.Debug(fmt.Sprintf("success get budget by id: %d", id))
+	return u.converter.ToBudgetDTO(budget), nil
+}
+
+func (u *budgetUseCase) GetAll(ctx context.Context, userID *uuid.UUID) ([]*dto.BudgetResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+
+	budgets, err := u.budgetRepository.GetAll(ctx, userID)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get budgets: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success getting budgets: %d", len(budgets)))
+	return u.converter.ToBudgetDTOs(budgets), nil
+}
+
+func (u *budgetUseCase) Update(ctx context.Context, id int, req *dto.UpdateBudgetRequest) (*dto.BudgetResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to update budget: id=%d", id))
+
+	if err := u.validator.Validate(req); err != nil {
+		log.Error(fmt.Sprintf("invalid input: %v", custom_err.ErrInvalidRequest))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	budget, err := u.getBudget(ctx, id)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get budget for update: %v", err))
+		return nil, err
+	}
+
+	u.converter.ApplyUpdateToEntity(budget, req)
+	budget.UpdatedAt = time.Now()
+
+	if req.ServiceID != nil || req.ServiceName != nil {
+		if err = u.resolveService(ctx, budget); err != nil {
+			log.Error(fmt.Sprintf("failed to resolve budget service: %v", err))
+			return nil, err
+		}
+	}
+
+	updated, err := u.budgetRepository.Update(ctx, budget)
+	if err != nil {
+		if errors.Is(err, custom_err.ErrBudgetNotFound) {
+			log.Error(fmt.Sprintf("failed to update budget: %v", err))
+			return nil, custom_err.ErrBudgetNotFound
+		}
+		if errors.Is(err, custom_err.ErrServiceNotFound) {
+			log.Error(fmt.Sprintf("unknown service: %v", err))
+			return nil, custom_err.ErrServiceNotFound
+		}
+		if errors.Is(err, custom_err.ErrCategoryNotFound) {
+			log.Error(fmt.Sprintf("unknown category: %v", err))
+			return nil, custom_err.ErrCategoryNotFound
+		}
+		log.Error(fmt.Sprintf("failed to update budget: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success update budget: id=%d", id))
+	return u.converter.ToBudgetDTO(updated), nil
+}
+
+func (u *budgetUseCase) Delete(ctx context.Context, id int) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to delete budget: id=%d", id))
+
+	if err := u.budgetRepository.Delete(ctx, id); err != nil {
+		if errors.Is(err, custom_err.ErrBudgetNotFound) {
+			log.Error(fmt.Sprintf("failed to delete budget: %v", err))
+			return custom_err.ErrBudgetNotFound
+		}
+		log.Error(fmt.Sprintf("failed to delete budget: %v", err))
+		return custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success delete budget: id=%d", id))
+	return nil
+}
+
+// Status evaluates the budget against the cost of the user's subscriptions in
+// the current budget period.
+func (u *budgetUseCase) Status(ctx context.Context, id int) (*dto.BudgetStatusResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to get budget status: id=%d", id))
+
+	budget, err := u.getBudget(ctx, id)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get budget for status: %v", err))
+		return nil, err
+	}
+
+	status, err := u.status(ctx, budget, time.Now())
+	if err != nil {
+		if errors.Is(err, custom_err.ErrExchangeRateNotFound) {
+			log.Error(fmt.Sprintf("failed to convert budget spend: %v", err))
+			return nil, custom_err.ErrExchangeRateNotFound
+		}
+		log.Error(fmt.Sprintf("failed to get budget status: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success get budget status: id=%d spent=%s", id, status.Spent))
+	return u.converter.ToBudgetStatusDTO(status), nil
+}
+
+func (u *budgetUseCase) getBudget(ctx context.Context, id int) (*entity.Budget, error) {
+	budget, err := u.budgetRepository.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, custom_err.ErrBudgetNotFound) {
+			return nil, custom_err.ErrBudgetNotFound
+		}
+		return nil, custom_err.ErrInternalServer
+	}
+	return budget, nil
+}
+
+// resolveService points a budget for one service at its catalog service: the
+// service with ServiceID when it is set, otherwise the service named
+// ServiceName or having it as an alias. Unlike a subscription, a budget does
+// not add an unknown name to the catalog, it fails with ErrServiceNotFound.
+func (u *budgetUseCase) resolveService(ctx context.Context, budget *entity.Budget) error {
+	var service *entity.Service
+	var err error
+	switch {
+	case budget.ServiceID != nil:
+		service, err = u.serviceRepository.GetByID(ctx, *budget.ServiceID)
+	case budget.ServiceName != nil:
+		service, err = u.serviceRepository.Resolve(ctx, utils.NormalizeServiceName(*budget.ServiceName))
+	default:
+		return nil
+	}
+	if err != nil {
+		if errors.Is(err, custom_err.ErrServiceNotFound) {
+			return custom_err.ErrServiceNotFound
+		}
+		logger.FromContext(ctx).Error(fmt.Sprintf("failed to resolve service: %v", err))
+		return custom_err.ErrInternalServer
+	}
+
+	budget.ServiceID = &service.ID
+	budget.ServiceName = &service.Name
+	return nil
+}
+
+// status sums the cost of the budget's subscriptions over the budget period
+// containing now, in the budget currency.
+func (u *budgetUseCase) status(ctx context.Context, budget *entity.Budget, now time.Time) (*entity.BudgetStatus, error) {
+	start, end := budget.Window(now)
+	report, err := u.subscriptionRepository.CalculateCost(ctx, &entity.CostFilter{
+		UserID:    &budget.UserID,
+		ServiceID: budget.ServiceID,
+		Category:  budget.Category,
+		StartDate: start,
+		EndDate:   end,
+		Currency:  budget.Currency,
+		Mode:      entity.CostModeCharged,
+		GroupBy:   []string{entity.CostGroupByUserID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	status := &entity.BudgetStatus{Budget: budget, StartDate: start, EndDate: end}
+	for _, group := range report.Groups {
+		status.Spent += group.Cost
+	}
+	return status, nil
+}
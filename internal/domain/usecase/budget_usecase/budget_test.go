@@ -0,0 +1,238 @@
+package budget_usecase
+
+import (
+	"AggregationService/internal/converters"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	eventmocks "AggregationService/internal/domain/ports/events/mocks"
+	"AggregationService/internal/domain/ports/repository/mocks"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/money"
+	"AggregationService/internal/pkg/validation"
+)
+
+type testDeps struct {
+	budgets       *mocks.IBudgetRepository
+	subscriptions *mocks.ISubscriptionRepository
+	services      *mocks.IServiceRepository
+	publisher     *eventmocks.IBudgetAlertPublisher
+}
+
+func newTestUseCase(t *testing.T) (IBudgetUseCase, testDeps) {
+	deps := testDeps{
+		budgets:       mocks.NewIBudgetRepository(t),
+		subscriptions: mocks.NewISubscriptionRepository(t),
+		services:      mocks.NewIServiceRepository(t),
+		publisher:     eventmocks.NewIBudgetAlertPublisher(t),
+	}
+	validator, _ := validation.New()
+	return New(deps.budgets, deps.subscriptions, deps.services, deps.publisher, validator, converters.NewBudgetConverter()), deps
+}
+
+func costOf(cost money.Amount) *entity.CostReport {
+	return &entity.CostReport{Groups: []*entity.CostGroup{{Subscriptions: 1, Cost: cost}}}
+}
+
+func Test_CreateBudget(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	category := "vpn"
+	alias, unknown := " Кинопоиск", "Okko"
+
+	tests := []struct {
+		name       string
+		input      dto.CreateBudgetRequest
+		setupMocks func(deps testDeps)
+		wantErr    error
+	}{
+		{
+			name:  "Defaults",
+			input: dto.CreateBudgetRequest{UserID: userID, Period: "month", Amount: money.FromMajor(1000)},
+			setupMocks: func(deps testDeps) {
+				deps.budgets.On("Create", mock.Anything, mock.MatchedBy(func(b *entity.Budget) bool {
+					return b.Currency == "RUB" && b.Threshold == 100 && b.ServiceID == nil && b.ServiceName == nil
+				})).Return(&entity.Budget{ID: 1, UserID: userID, Period: "month"}, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name:       "Unknown period",
+			input:      dto.CreateBudgetRequest{UserID: userID, Period: "week", Amount: money.FromMajor(1000)},
+			setupMocks: func(deps testDeps) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name:       "Zero amount",
+			input:      dto.CreateBudgetRequest{UserID: userID, Period: "year"},
+			setupMocks: func(deps testDeps) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name:  "Service by alias",
+			input: dto.CreateBudgetRequest{UserID: userID, ServiceName: &alias, Period: "month", Amount: money.FromMajor(1000)},
+			setupMocks: func(deps testDeps) {
+				deps.services.On("Resolve", mock.Anything, "kinopoisk").
+					Return(&entity.Service{ID: 3, Name: "KinoPoisk HD"}, nil)
+				deps.budgets.On("Create", mock.Anything, mock.MatchedBy(func(b *entity.Budget) bool {
+					return *b.ServiceID == 3 && *b.ServiceName == "KinoPoisk HD"
+				})).Return(&entity.Budget{ID: 1, UserID: userID, Period: "month"}, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "Unknown service",
+			input: dto.CreateBudgetRequest{UserID: userID, ServiceName: &unknown, Period: "month", Amount: money.FromMajor(1000)},
+			setupMocks: func(deps testDeps) {
+				deps.services.On("Resolve", mock.Anything, "okko").Return(nil, custom_err.ErrServiceNotFound)
+			},
+			wantErr: custom_err.ErrServiceNotFound,
+		},
+		{
+			name:  "Unknown category",
+			input: dto.CreateBudgetRequest{UserID: userID, Category: &category, Period: "month", Amount: money.FromMajor(1000)},
+			setupMocks: func(deps testDeps) {
+				deps.budgets.On("Create", mock.Anything, mock.Anything).Return(nil, custom_err.ErrCategoryNotFound)
+			},
+			wantErr: custom_err.ErrCategoryNotFound,
+		},
+		{
+			name:  "Repository error",
+			input: dto.CreateBudgetRequest{UserID: userID, Period: "year", Amount: money.FromMajor(1000)},
+			setupMocks: func(deps testDeps) {
+				deps.budgets.On("Create", mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+			},
+			wantErr: custom_err.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCase, deps := newTestUseCase(t)
+			tt.setupMocks(deps)
+
+			result, err := useCase.Create(context.Background(), &tt.input)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, result)
+			}
+		})
+	}
+}
+
+func Test_BudgetStatus(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	service, serviceID := "Yandex", 7
+	category := "streaming"
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	yearStart := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		setupMocks   func(deps testDeps)
+		wantSpent    money.Amount
+		wantLeft     money.Amount
+		wantAlerting bool
+		wantErr      error
+	}{
+		{
+			name: "Monthly budget for a service",
+			setupMocks: func(deps testDeps) {
+				deps.budgets.On("GetByID", mock.Anything, 1).Return(&entity.Budget{
+					ID: 1, UserID: userID, ServiceID: &serviceID, ServiceName: &service, Period: "month",
+					Amount: money.FromMajor(1000), Currency: "RUB", Threshold: 80,
+				}, nil)
+				// the service is matched by id, not by a substring of its name
+				deps.subscriptions.On("CalculateCost", mock.Anything, mock.MatchedBy(func(f *entity.CostFilter) bool {
+					return *f.UserID == userID && *f.ServiceID == serviceID && f.ServiceName == nil &&
+						f.StartDate.Equal(monthStart) && f.EndDate.Equal(monthStart) && f.Currency == "RUB"
+				})).Return(costOf(money.FromMajor(850)), nil)
+			},
+			wantSpent:    money.FromMajor(850),
+			wantLeft:     money.FromMajor(150),
+			wantAlerting: true,
+		},
+		{
+			name: "Monthly budget for a category",
+			setupMocks: func(deps testDeps) {
+				deps.budgets.On("GetByID", mock.Anything, 1).Return(&entity.Budget{
+					ID: 1, UserID: userID, Category: &category, Period: "month",
+					Amount: money.FromMajor(1000), Currency: "RUB", Threshold: 100,
+				}, nil)
+				deps.subscriptions.On("CalculateCost", mock.Anything, mock.MatchedBy(func(f *entity.CostFilter) bool {
+					return f.ServiceID == nil && *f.Category == category && f.StartDate.Equal(monthStart)
+				})).Return(costOf(money.FromMajor(400)), nil)
+			},
+			wantSpent:    money.FromMajor(400),
+			wantLeft:     money.FromMajor(600),
+			wantAlerting: false,
+		},
+		{
+			name: "Yearly budget",
+			setupMocks: func(deps testDeps) {
+				deps.budgets.On("GetByID", mock.Anything, 1).Return(&entity.Budget{
+					ID: 1, UserID: userID, Period: "year", Amount: money.FromMajor(12000), Currency: "USD", Threshold: 100,
+				}, nil)
+				deps.subscriptions.On("CalculateCost", mock.Anything, mock.MatchedBy(func(f *entity.CostFilter) bool {
+					return f.ServiceID == nil && f.StartDate.Equal(yearStart) &&
+						f.EndDate.Equal(yearStart.AddDate(0, 11, 0)) && f.Currency == "USD"
+				})).Return(costOf(money.FromMajor(3000)), nil)
+			},
+			wantSpent:    money.FromMajor(3000),
+			wantLeft:     money.FromMajor(9000),
+			wantAlerting: false,
+		},
+		{
+			name: "Budget not found",
+			setupMocks: func(deps testDeps) {
+				deps.budgets.On("GetByID", mock.Anything, 1).Return(nil, custom_err.ErrBudgetNotFound)
+			},
+			wantErr: custom_err.ErrBudgetNotFound,
+		},
+		{
+			name: "Missing exchange rate",
+			setupMocks: func(deps testDeps) {
+				deps.budgets.On("GetByID", mock.Anything, 1).Return(&entity.Budget{
+					ID: 1, UserID: userID, Period: "month", Amount: money.FromMajor(100), Currency: "EUR", Threshold: 100,
+				}, nil)
+				deps.subscriptions.On("CalculateCost", mock.Anything, mock.Anything).
+					Return(nil, custom_err.ErrExchangeRateNotFound)
+			},
+			wantErr: custom_err.ErrExchangeRateNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCase, deps := newTestUseCase(t)
+			tt.setupMocks(deps)
+
+			result, err := useCase.Status(context.Background(), 1)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantSpent, result.Spent)
+			assert.Equal(t, tt.wantLeft, result.Remaining)
+			assert.Equal(t, tt.wantAlerting, result.Alerting)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package budget_usecase
+
+import (
+	"AggregationService/internal/converters"
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/domain/ports/events"
+	"AggregationService/internal/domain/ports/repository"
+	"AggregationService/internal/pkg/validation"
+	"context"
+	"github.com/google/uuid"
+)
+
+type IBudgetUseCase interface {
+	Create(ctx context.Context, req *dto.CreateBudgetRequest) (*dto.BudgetResponse, error)
+	GetByID(ctx context.Context, id int) (*dto.BudgetResponse, error)
+	GetAll(ctx context.Context, userID *uuid.UUID) ([]*dto.BudgetResponse, error)
+	Update(ctx context.Context, id int, req *dto.UpdateBudgetRequest) (*dto.BudgetResponse, error)
+	Delete(ctx context.Context, id int) error
+	Status(ctx context.Context, id int) (*dto.BudgetStatusResponse, error)
+	Snapshot(ctx context.Context, userID uuid.UUID) (*entity.BudgetSnapshot, error)
+	AlertCrossed(ctx context.Context, before *entity.BudgetSnapshot) error
+}
+
+type budgetUseCase struct {
+	budgetRepository       repository.IBudgetRepository
+	subscriptionRepository repository.ISubscriptionRepository
+	serviceRepository      repository.IServiceRepository
+	alertPublisher         events.IBudgetAlertPublisher
+	validator              *validation.Validator
+	converter              *converters.BudgetConverter
+}
+
+func New(
+	budgetRepository repository.IBudgetRepository,
+	subscriptionRepository repository.ISubscriptionRepository,
+	serviceRepository repository.IServiceRepository,
+	alertPublisher events.IBudgetAlertPublisher,
+	validator *validation.Validator,
+	converter *converters.BudgetConverter,
+) IBudgetUseCase {
+	return &budgetUseCase{
+		budgetRepository:       budgetRepository,
+		subscriptionRepository: subscriptionRepository,
+		serviceRepository:      serviceRepository,
+		alertPublisher:         alertPublisher,
+		validator:              validator,
+		converter:              converter,
+	}
+}
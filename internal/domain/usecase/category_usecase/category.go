@@ -0,0 +1,56 @@
+package category_usecase
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+func (u *categoryUseCase) Create(ctx context.Context, req *dto.CreateCategoryRequest) (*dto.CategoryResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to create category: %+v", req))
+
+	if err := u.validator.Validate(req); err != nil {
+		log.Error(fmt.Sprintf("invalid input: %v", custom_err.ErrInvalidRequest))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	category := u.converter.ToCategoryEntity(req)
+	category.CreatedAt = time.Now()
+
+	created, err := u.categoryRepository.Create(ctx, category)
+	if err != nil {
+		if errors.Is(err, custom_err.ErrCategoryAlreadyExists) {
+			log.Error(fmt.Sprintf("duplicate category: %v", err))
+			return nil, custom_err.ErrCategoryAlreadyExists
+		}
+		log.Error(fmt.Sprintf("failed to create category: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success creating category: %s", created.Code))
+	return u.converter.ToCategoryDTO(created), nil
+}
+
+func (u *categoryUseCase) GetAll(ctx context.Context) ([]*dto.CategoryResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+
+	categories, err := u.categoryRepository.GetAll(ctx)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get categories: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success getting categories: %d", len(categories)))
+	return u.converter.ToCategoryDTOs(categories), nil
+}
@@ -0,0 +1,98 @@
+package category_usecase
+
+import (
+	"AggregationService/internal/converters"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/domain/ports/repository/mocks"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/validation"
+)
+
+func Test_CreateCategory(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		input      dto.CreateCategoryRequest
+		setupMocks func(repo *mocks.ICategoryRepository)
+		wantErr    error
+	}{
+		{
+			name:  "Valid category",
+			input: dto.CreateCategoryRequest{Code: " VPN ", Name: "VPN"},
+			setupMocks: func(repo *mocks.ICategoryRepository) {
+				repo.On("Create", mock.Anything, mock.MatchedBy(func(category *entity.Category) bool {
+					return category.Code == "vpn" && !category.CreatedAt.IsZero()
+				})).Return(&entity.Category{Code: "vpn", Name: "VPN"}, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name:       "Missing name",
+			input:      dto.CreateCategoryRequest{Code: "vpn"},
+			setupMocks: func(repo *mocks.ICategoryRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name:  "Duplicate code",
+			input: dto.CreateCategoryRequest{Code: "music", Name: "Музыка"},
+			setupMocks: func(repo *mocks.ICategoryRepository) {
+				repo.On("Create", mock.Anything, mock.Anything).Return(nil, custom_err.ErrCategoryAlreadyExists)
+			},
+			wantErr: custom_err.ErrCategoryAlreadyExists,
+		},
+		{
+			name:  "Repository error",
+			input: dto.CreateCategoryRequest{Code: "vpn", Name: "VPN"},
+			setupMocks: func(repo *mocks.ICategoryRepository) {
+				repo.On("Create", mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+			},
+			wantErr: custom_err.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewICategoryRepository(t)
+			validator, _ := validation.New()
+			useCase := New(mockRepo, validator, converters.NewCategoryConverter())
+
+			tt.setupMocks(mockRepo)
+			ctx := context.Background()
+
+			_, err := useCase.Create(ctx, &tt.input)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_GetAllCategories(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := mocks.NewICategoryRepository(t)
+	validator, _ := validation.New()
+	useCase := New(mockRepo, validator, converters.NewCategoryConverter())
+
+	mockRepo.On("GetAll", mock.Anything).Return([]*entity.Category{
+		{Code: "music", Name: "Музыка"},
+		{Code: "other", Name: "Другое"},
+	}, nil)
+
+	categories, err := useCase.GetAll(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, categories, 2)
+	assert.Equal(t, "music", categories[0].Code)
+}
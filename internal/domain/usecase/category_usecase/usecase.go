@@ -0,0 +1,32 @@
+package category_usecase
+
+import (
+	"AggregationService/internal/converters"
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/ports/repository"
+	"AggregationService/internal/pkg/validation"
+	"context"
+)
+
+type ICategoryUseCase interface {
+	Create(ctx context.Context, req *dto.CreateCategoryRequest) (*dto.CategoryResponse, error)
+	GetAll(ctx context.Context) ([]*dto.CategoryResponse, error)
+}
+
+type categoryUseCase struct {
+	categoryRepository repository.ICategoryRepository
+	validator          *validation.Validator
+	converter          *converters.CategoryConverter
+}
+
+func New(
+	categoryRepository repository.ICategoryRepository,
+	validator *validation.Validator,
+	converter *converters.CategoryConverter,
+) ICategoryUseCase {
+	return &categoryUseCase{
+		categoryRepository: categoryRepository,
+		validator:          validator,
+		converter:          converter,
+	}
+}
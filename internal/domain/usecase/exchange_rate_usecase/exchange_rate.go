@@ -0,0 +1,79 @@
+package exchange_rate_usecase
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func (u *exchangeRateUseCase) Create(ctx context.Context, req *dto.CreateExchangeRateRequest) (*dto.ExchangeRateResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to set exchange rate: %+v", req))
+
+	if err := u.validator.Validate(req); err != nil {
+		log.Error(fmt.Sprintf("invalid input: %v", custom_err.ErrInvalidRequest))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	rate := u.converter.ToExchangeRateEntity(req)
+	rate.CreatedAt = time.Now()
+	rate.UpdatedAt = rate.CreatedAt
+
+	saved, err := u.exchangeRateRepository.Upsert(ctx, rate)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to set exchange rate: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success setting exchange rate: %+v", saved))
+	return u.converter.ToExchangeRateDTO(saved), nil
+}
+
+func (u *exchangeRateUseCase) GetAll(ctx context.Context, currency *string) ([]*dto.ExchangeRateResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+
+	if currency != nil {
+		upper := strings.ToUpper(*currency)
+		currency = &upper
+	}
+
+	rates, err := u.exchangeRateRepository.GetAll(ctx, currency)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get exchange rates: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success getting exchange rates: %d", len(rates)))
+	return u.converter.ToExchangeRateDTOs(rates), nil
+}
+
+func (u *exchangeRateUseCase) Delete(ctx context.Context, id int) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to delete exchange rate: id=%d", id))
+
+	if err := u.exchangeRateRepository.Delete(ctx, id); err != nil {
+		if errors.Is(err, custom_err.ErrExchangeRateNotFound) {
+			log.Error(fmt.Sprintf("failed to delete exchange rate: %v", err))
+			return custom_err.ErrExchangeRateNotFound
+		}
+		log.Error(fmt.Sprintf("failed to delete exchange rate: %v", err))
+		return custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success delete exchange rate: id=%d", id))
+	return nil
+}
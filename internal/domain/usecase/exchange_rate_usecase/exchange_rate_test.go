@@ -0,0 +1,197 @@
+package exchange_rate_usecase
+
+import (
+	"AggregationService/internal/converters"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/domain/ports/repository/mocks"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/validation"
+)
+
+func Test_CreateExchangeRate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		input      dto.CreateExchangeRateRequest
+		setupMocks func(repo *mocks.IExchangeRateRepository)
+		wantErr    error
+	}{
+		{
+			name:  "Valid rate",
+			input: dto.CreateExchangeRateRequest{Currency: "USD", Rate: 92.5, ValidFrom: "09-2025"},
+			setupMocks: func(repo *mocks.IExchangeRateRepository) {
+				repo.On("Upsert", mock.Anything, mock.MatchedBy(func(rate *entity.ExchangeRate) bool {
+					return rate.Currency == "USD" &&
+						rate.ValidFrom.Equal(time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC))
+				})).Return(&entity.ExchangeRate{ID: 1, Currency: "USD", Rate: 92.5}, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name:       "Base currency",
+			input:      dto.CreateExchangeRateRequest{Currency: "RUB", Rate: 1, ValidFrom: "09-2025"},
+			setupMocks: func(repo *mocks.IExchangeRateRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name:       "Invalid rate",
+			input:      dto.CreateExchangeRateRequest{Currency: "USD", Rate: -1, ValidFrom: "09-2025"},
+			setupMocks: func(repo *mocks.IExchangeRateRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name:       "Invalid currency",
+			input:      dto.CreateExchangeRateRequest{Currency: "DOLLAR", Rate: 92.5, ValidFrom: "09-2025"},
+			setupMocks: func(repo *mocks.IExchangeRateRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name:  "Repository error",
+			input: dto.CreateExchangeRateRequest{Currency: "EUR", Rate: 101.2, ValidFrom: "09-2025"},
+			setupMocks: func(repo *mocks.IExchangeRateRepository) {
+				repo.On("Upsert", mock.Anything, mock.Anything).
+					Return(nil, custom_err.ErrInternalServer)
+			},
+			wantErr: custom_err.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewIExchangeRateRepository(t)
+			validator, _ := validation.New()
+			useCase := New(mockRepo, validator, converters.NewExchangeRateConverter())
+
+			tt.setupMocks(mockRepo)
+			ctx := context.Background()
+
+			_, err := useCase.Create(ctx, &tt.input)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_GetAllExchangeRates(t *testing.T) {
+	t.Parallel()
+
+	currency := "usd"
+	upper := "USD"
+
+	tests := []struct {
+		name       string
+		currency   *string
+		setupMocks func(repo *mocks.IExchangeRateRepository)
+		wantCount  int
+		wantErr    error
+	}{
+		{
+			name:     "Filtered by currency",
+			currency: &currency,
+			setupMocks: func(repo *mocks.IExchangeRateRepository) {
+				repo.On("GetAll", mock.Anything, &upper).
+					Return([]*entity.ExchangeRate{{ID: 1, Currency: "USD"}, {ID: 2, Currency: "USD"}}, nil)
+			},
+			wantCount: 2,
+			wantErr:   nil,
+		},
+		{
+			name:     "Repository error",
+			currency: nil,
+			setupMocks: func(repo *mocks.IExchangeRateRepository) {
+				repo.On("GetAll", mock.Anything, (*string)(nil)).
+					Return(nil, custom_err.ErrInternalServer)
+			},
+			wantErr: custom_err.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewIExchangeRateRepository(t)
+			validator, _ := validation.New()
+			useCase := New(mockRepo, validator, converters.NewExchangeRateConverter())
+
+			tt.setupMocks(mockRepo)
+			ctx := context.Background()
+
+			rates, err := useCase.GetAll(ctx, tt.currency)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, rates, tt.wantCount)
+			}
+		})
+	}
+}
+
+func Test_DeleteExchangeRate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		id         int
+		setupMocks func(repo *mocks.IExchangeRateRepository)
+		wantErr    error
+	}{
+		{
+			name: "Valid delete",
+			id:   1,
+			setupMocks: func(repo *mocks.IExchangeRateRepository) {
+				repo.On("Delete", mock.Anything, 1).Return(nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Not found",
+			id:   999,
+			setupMocks: func(repo *mocks.IExchangeRateRepository) {
+				repo.On("Delete", mock.Anything, 999).Return(custom_err.ErrExchangeRateNotFound)
+			},
+			wantErr: custom_err.ErrExchangeRateNotFound,
+		},
+		{
+			name: "Repository error",
+			id:   2,
+			setupMocks: func(repo *mocks.IExchangeRateRepository) {
+				repo.On("Delete", mock.Anything, 2).Return(custom_err.ErrInternalServer)
+			},
+			wantErr: custom_err.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewIExchangeRateRepository(t)
+			validator, _ := validation.New()
+			useCase := New(mockRepo, validator, converters.NewExchangeRateConverter())
+
+			tt.setupMocks(mockRepo)
+			ctx := context.Background()
+
+			err := useCase.Delete(ctx, tt.id)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
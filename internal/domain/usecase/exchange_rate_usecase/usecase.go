@@ -0,0 +1,33 @@
+package exchange_rate_usecase
+
+import (
+	"AggregationService/internal/converters"
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/ports/repository"
+	"AggregationService/internal/pkg/validation"
+	"context"
+)
+
+type IExchangeRateUseCase interface {
+	Create(ctx context.Context, req *dto.CreateExchangeRateRequest) (*dto.ExchangeRateResponse, error)
+	GetAll(ctx context.Context, currency *string) ([]*dto.ExchangeRateResponse, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type exchangeRateUseCase struct {
+	exchangeRateRepository repository.IExchangeRateRepository
+	validator              *validation.Validator
+	converter              *converters.ExchangeRateConverter
+}
+
+func New(
+	exchangeRateRepository repository.IExchangeRateRepository,
+	validator *validation.Validator,
+	converter *converters.ExchangeRateConverter,
+) IExchangeRateUseCase {
+	return &exchangeRateUseCase{
+		exchangeRateRepository: exchangeRateRepository,
+		validator:              validator,
+		converter:              converter,
+	}
+}
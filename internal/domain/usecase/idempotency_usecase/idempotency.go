@@ -0,0 +1,80 @@
+package idempotency_usecase
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Begin reserves key in scope for a request with body. It returns either the
+// completed response of an earlier request with the same key and body, to be
+// replayed, or the new reservation, to be completed with the response by
+// Complete or freed by Release. The same key with another body fails with
+// ErrIdempotencyKeyReused, and with ErrIdempotencyKeyInUse while the earlier
+// request is still running. An earlier request that has not completed within
+// its lease is taken to have died, and its key is reserved anew.
+func (u *idempotencyUseCase) Begin(ctx context.Context, scope, key string, body []byte) (*entity.IdempotencyRecord, error) {
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to reserve idempotency key: %s %s", scope, key))
+
+	now := time.Now()
+	lockedUntil := now.Add(u.lease)
+	record := &entity.IdempotencyRecord{
+		Key:         key,
+		Scope:       scope,
+		RequestHash: requestHash(body),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(u.ttl),
+		LockedUntil: &lockedUntil,
+	}
+	stored, err := u.idempotencyRepository.Reserve(ctx, record)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to reserve idempotency key: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+	if stored == nil {
+		log.Debug(fmt.Sprintf("success reserve idempotency key: %s %s", scope, key))
+		return record, nil
+	}
+
+	if stored.RequestHash != record.RequestHash {
+		log.Error(fmt.Sprintf("idempotency key %s reused with another request", key))
+		return nil, custom_err.ErrIdempotencyKeyReused
+	}
+	if !stored.Completed() {
+		log.Error(fmt.Sprintf("idempotency key %s is in use", key))
+		return nil, custom_err.ErrIdempotencyKeyInUse
+	}
+
+	log.Debug(fmt.Sprintf("replaying response of idempotency key: %s %s", scope, key))
+	return stored, nil
+}
+
+// Complete stores the response set on a reservation made by Begin.
+func (u *idempotencyUseCase) Complete(ctx context.Context, record *entity.IdempotencyRecord) error {
+	if err := u.idempotencyRepository.Complete(ctx, record); err != nil {
+		logger.FromContext(ctx).Error(fmt.Sprintf("failed to store idempotent response: %v", err))
+		return custom_err.ErrInternalServer
+	}
+	return nil
+}
+
+// Release frees a reservation made by Begin after its request failed, so
+// that the request can be retried with the same key.
+func (u *idempotencyUseCase) Release(ctx context.Context, record *entity.IdempotencyRecord) error {
+	if err := u.idempotencyRepository.Release(ctx, record.Scope, record.Key); err != nil {
+		logger.FromContext(ctx).Error(fmt.Sprintf("failed to release idempotency key: %v", err))
+		return custom_err.ErrInternalServer
+	}
+	return nil
+}
+
+func requestHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
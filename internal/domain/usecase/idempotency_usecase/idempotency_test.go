@@ -0,0 +1,100 @@
+package idempotency_usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/domain/ports/repository/mocks"
+	custom_err "AggregationService/internal/errors"
+)
+
+func Test_Begin(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"service_name": "yandex"}`)
+	created := http.StatusCreated
+	stored := func(status *int, hash string) *entity.IdempotencyRecord {
+		return &entity.IdempotencyRecord{Key: "key-1", Scope: "POST /subscriptions/", RequestHash: hash,
+			StatusCode: status, Body: []byte(`{"id": 1}`)}
+	}
+
+	tests := []struct {
+		name       string
+		setupMocks func(repo *mocks.IIdempotencyRepository)
+		wantReplay bool
+		wantErr    error
+	}{
+		{
+			name: "First request",
+			setupMocks: func(repo *mocks.IIdempotencyRepository) {
+				repo.On("Reserve", mock.Anything, mock.MatchedBy(func(r *entity.IdempotencyRecord) bool {
+					return r.Key == "key-1" && r.RequestHash == requestHash(body) && r.ExpiresAt.Sub(r.CreatedAt) == time.Hour &&
+						r.LockedUntil.Sub(r.CreatedAt) == time.Minute
+				})).Return(nil, nil)
+			},
+		},
+		{
+			name: "Repeat is replayed",
+			setupMocks: func(repo *mocks.IIdempotencyRepository) {
+				repo.On("Reserve", mock.Anything, mock.Anything).Return(stored(&created, requestHash(body)), nil)
+			},
+			wantReplay: true,
+		},
+		{
+			name: "Another body",
+			setupMocks: func(repo *mocks.IIdempotencyRepository) {
+				repo.On("Reserve", mock.Anything, mock.Anything).Return(stored(&created, requestHash([]byte(`{}`))), nil)
+			},
+			wantErr: custom_err.ErrIdempotencyKeyReused,
+		},
+		{
+			name: "First request still running",
+			setupMocks: func(repo *mocks.IIdempotencyRepository) {
+				repo.On("Reserve", mock.Anything, mock.Anything).Return(stored(nil, requestHash(body)), nil)
+			},
+			wantErr: custom_err.ErrIdempotencyKeyInUse,
+		},
+		{
+			name: "Repository error",
+			setupMocks: func(repo *mocks.IIdempotencyRepository) {
+				repo.On("Reserve", mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+			},
+			wantErr: custom_err.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewIIdempotencyRepository(t)
+			useCase := New(mockRepo, time.Hour, time.Minute)
+			tt.setupMocks(mockRepo)
+
+			record, err := useCase.Begin(context.Background(), "POST /subscriptions/", "key-1", body)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, record)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantReplay, record.Completed())
+		})
+	}
+}
+
+func Test_Release(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := mocks.NewIIdempotencyRepository(t)
+	useCase := New(mockRepo, time.Hour, time.Minute)
+	mockRepo.On("Release", mock.Anything, "POST /subscriptions/", "key-1").Return(nil)
+
+	err := useCase.Release(context.Background(), &entity.IdempotencyRecord{Scope: "POST /subscriptions/", Key: "key-1"})
+	assert.NoError(t, err)
+}
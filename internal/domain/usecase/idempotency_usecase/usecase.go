@@ -0,0 +1,36 @@
+package idempotency_usecase
+
+import (
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/domain/ports/repository"
+	"context"
+	"time"
+)
+
+// DefaultTTL is how long a response is replayed to repeats of its request.
+const DefaultTTL = 24 * time.Hour
+
+// DefaultLease is how long a running request holds its key. It outlasts the
+// timeout of the requests made with a key, so only a request that died without
+// completing or releasing its key loses it.
+const DefaultLease = time.Minute
+
+type IIdempotencyUseCase interface {
+	Begin(ctx context.Context, scope, key string, body []byte) (*entity.IdempotencyRecord, error)
+	Complete(ctx context.Context, record *entity.IdempotencyRecord) error
+	Release(ctx context.Context, record *entity.IdempotencyRecord) error
+}
+
+type idempotencyUseCase struct {
+	idempotencyRepository repository.IIdempotencyRepository
+	ttl                   time.Duration
+	lease                 time.Duration
+}
+
+func New(idempotencyRepository repository.IIdempotencyRepository, ttl, lease time.Duration) IIdempotencyUseCase {
+	return &idempotencyUseCase{
+		idempotencyRepository: idempotencyRepository,
+		ttl:                   ttl,
+		lease:                 lease,
+	}
+}
@@ -0,0 +1,143 @@
+package service_usecase
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AddAlias makes another name resolve to the service. The alias can not be the
+// name or alias of any service.
+func (u *serviceUseCase) AddAlias(ctx context.Context, id int, req *dto.CreateServiceAliasRequest) (*dto.ServiceAliasResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to add service alias: id=%d %+v", id, req))
+
+	if err := u.validator.Validate(req); err != nil {
+		log.Error(fmt.Sprintf("invalid input: %v", custom_err.ErrInvalidRequest))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	alias := u.converter.ToServiceAliasEntity(id, req)
+	alias.CreatedAt = time.Now()
+
+	created, err := u.serviceRepository.AddAlias(ctx, alias)
+	if err != nil {
+		switch {
+		case errors.Is(err, custom_err.ErrServiceNotFound):
+			log.Error(fmt.Sprintf("failed to add service alias: %v", err))
+			return nil, custom_err.ErrServiceNotFound
+		case errors.Is(err, custom_err.ErrServiceAlreadyExists):
+			log.Error(fmt.Sprintf("duplicate service alias: %v", err))
+			return nil, custom_err.ErrServiceAlreadyExists
+		}
+		log.Error(fmt.Sprintf("failed to add service alias: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success adding service alias: id=%d", created.ID))
+	return u.converter.ToServiceAliasDTO(created), nil
+}
+
+func (u *serviceUseCase) GetAliases(ctx context.Context, id int) ([]*dto.ServiceAliasResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+
+	if _, err := u.getService(ctx, id); err != nil {
+		log.Error(fmt.Sprintf("failed to get service for aliases: %v", err))
+		return nil, err
+	}
+
+	aliases, err := u.serviceRepository.GetAliases(ctx, id)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get service aliases: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success getting service aliases: %d", len(aliases)))
+	return u.converter.ToServiceAliasDTOs(aliases), nil
+}
+
+func (u *serviceUseCase) DeleteAlias(ctx context.Context, id, aliasID int) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to delete service alias: id=%d alias=%d", id, aliasID))
+
+	if err := u.serviceRepository.DeleteAlias(ctx, id, aliasID); err != nil {
+		if errors.Is(err, custom_err.ErrServiceAliasNotFound) {
+			log.Error(fmt.Sprintf("failed to delete service alias: %v", err))
+			return custom_err.ErrServiceAliasNotFound
+		}
+		log.Error(fmt.Sprintf("failed to delete service alias: %v", err))
+		return custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success delete service alias: id=%d alias=%d", id, aliasID))
+	return nil
+}
+
+// SuggestDuplicates lists pairs of services with similar names for an admin to
+// merge.
+func (u *serviceUseCase) SuggestDuplicates(ctx context.Context, req *dto.ServiceDuplicatesRequest) ([]*dto.ServiceDuplicateResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to suggest duplicate services: %+v", req))
+
+	if err := u.validator.Validate(req); err != nil {
+		log.Error(fmt.Sprintf("invalid input: %v", custom_err.ErrInvalidRequest))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	duplicates, err := u.serviceRepository.SuggestDuplicates(ctx, req.MinSimilarity, req.Limit)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to suggest duplicate services: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success suggesting duplicate services: %d", len(duplicates)))
+	return u.converter.ToServiceDuplicateDTOs(duplicates), nil
+}
+
+// Merge folds the service of req into the service id: its subscriptions and
+// aliases move over and its name becomes an alias.
+func (u *serviceUseCase) Merge(ctx context.Context, id int, req *dto.MergeServiceRequest) (*dto.ServiceResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to merge service %d into %d", req.ServiceID, id))
+
+	if err := u.validator.Validate(req); err != nil || req.ServiceID == id {
+		log.Error(fmt.Sprintf("invalid input: %v", custom_err.ErrInvalidRequest))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	merged, err := u.serviceRepository.Merge(ctx, id, req.ServiceID)
+	if err != nil {
+		if errors.Is(err, custom_err.ErrServiceNotFound) {
+			log.Error(fmt.Sprintf("failed to merge services: %v", err))
+			return nil, custom_err.ErrServiceNotFound
+		}
+		if errors.Is(err, custom_err.ErrSubscriptionAlreadyFound) {
+			log.Error(fmt.Sprintf("merge would overlap subscriptions: %v", err))
+			return nil, custom_err.ErrSubscriptionAlreadyFound
+		}
+		log.Error(fmt.Sprintf("failed to merge services: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success merging service %d into %d", req.ServiceID, id))
+	return u.converter.ToServiceDTO(merged), nil
+}
@@ -0,0 +1,208 @@
+package service_usecase
+
+import (
+	"AggregationService/internal/converters"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/domain/ports/repository/mocks"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/validation"
+)
+
+func Test_AddServiceAlias(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		id         int
+		input      dto.CreateServiceAliasRequest
+		setupMocks func(repo *mocks.IServiceRepository)
+		wantErr    error
+	}{
+		{
+			name:  "Cyrillic spelling",
+			id:    2,
+			input: dto.CreateServiceAliasRequest{Alias: " Кинопоиск  HD "},
+			setupMocks: func(repo *mocks.IServiceRepository) {
+				repo.On("AddAlias", mock.Anything, mock.MatchedBy(func(alias *entity.ServiceAlias) bool {
+					return alias.ServiceID == 2 && alias.Alias == "Кинопоиск HD" && alias.NormalizedAlias == "kinopoisk hd"
+				})).Return(&entity.ServiceAlias{ID: 1, ServiceID: 2, Alias: "Кинопоиск HD"}, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name:       "Empty alias",
+			id:         2,
+			input:      dto.CreateServiceAliasRequest{},
+			setupMocks: func(repo *mocks.IServiceRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name:  "Alias taken",
+			id:    2,
+			input: dto.CreateServiceAliasRequest{Alias: "Okko"},
+			setupMocks: func(repo *mocks.IServiceRepository) {
+				repo.On("AddAlias", mock.Anything, mock.Anything).Return(nil, custom_err.ErrServiceAlreadyExists)
+			},
+			wantErr: custom_err.ErrServiceAlreadyExists,
+		},
+		{
+			name:  "Unknown service",
+			id:    999,
+			input: dto.CreateServiceAliasRequest{Alias: "Okko"},
+			setupMocks: func(repo *mocks.IServiceRepository) {
+				repo.On("AddAlias", mock.Anything, mock.Anything).Return(nil, custom_err.ErrServiceNotFound)
+			},
+			wantErr: custom_err.ErrServiceNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewIServiceRepository(t)
+			validator, _ := validation.New()
+			useCase := New(mockRepo, validator, converters.NewServiceConverter())
+
+			tt.setupMocks(mockRepo)
+			ctx := context.Background()
+
+			_, err := useCase.AddAlias(ctx, tt.id, &tt.input)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_SuggestDuplicateServices(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		input      dto.ServiceDuplicatesRequest
+		setupMocks func(repo *mocks.IServiceRepository)
+		wantCount  int
+		wantErr    error
+	}{
+		{
+			name:  "Pairs found",
+			input: dto.ServiceDuplicatesRequest{MinSimilarity: 0.4, Limit: 50},
+			setupMocks: func(repo *mocks.IServiceRepository) {
+				repo.On("SuggestDuplicates", mock.Anything, 0.4, 50).Return([]*entity.ServiceDuplicate{
+					{ServiceID: 1, ServiceName: "Yandex Plus", DuplicateID: 2, DuplicateName: "Yandex Plus Multi", Similarity: 0.6},
+				}, nil)
+			},
+			wantCount: 1,
+		},
+		{
+			name:       "Threshold out of range",
+			input:      dto.ServiceDuplicatesRequest{MinSimilarity: 1.5, Limit: 50},
+			setupMocks: func(repo *mocks.IServiceRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name:  "Repository error",
+			input: dto.ServiceDuplicatesRequest{MinSimilarity: 0.4, Limit: 50},
+			setupMocks: func(repo *mocks.IServiceRepository) {
+				repo.On("SuggestDuplicates", mock.Anything, 0.4, 50).Return(nil, errors.New("db down"))
+			},
+			wantErr: custom_err.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewIServiceRepository(t)
+			validator, _ := validation.New()
+			useCase := New(mockRepo, validator, converters.NewServiceConverter())
+
+			tt.setupMocks(mockRepo)
+			ctx := context.Background()
+
+			duplicates, err := useCase.SuggestDuplicates(ctx, &tt.input)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, duplicates, tt.wantCount)
+			}
+		})
+	}
+}
+
+func Test_MergeServices(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		id         int
+		input      dto.MergeServiceRequest
+		setupMocks func(repo *mocks.IServiceRepository)
+		wantErr    error
+	}{
+		{
+			name:  "Valid merge",
+			id:    1,
+			input: dto.MergeServiceRequest{ServiceID: 2},
+			setupMocks: func(repo *mocks.IServiceRepository) {
+				repo.On("Merge", mock.Anything, 1, 2).Return(&entity.Service{ID: 1, Name: "Kinopoisk"}, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name:       "Into itself",
+			id:         1,
+			input:      dto.MergeServiceRequest{ServiceID: 1},
+			setupMocks: func(repo *mocks.IServiceRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name:  "Unknown service",
+			id:    1,
+			input: dto.MergeServiceRequest{ServiceID: 999},
+			setupMocks: func(repo *mocks.IServiceRepository) {
+				repo.On("Merge", mock.Anything, 1, 999).Return(nil, custom_err.ErrServiceNotFound)
+			},
+			wantErr: custom_err.ErrServiceNotFound,
+		},
+		{
+			name:  "Overlapping subscriptions",
+			id:    1,
+			input: dto.MergeServiceRequest{ServiceID: 3},
+			setupMocks: func(repo *mocks.IServiceRepository) {
+				repo.On("Merge", mock.Anything, 1, 3).Return(nil, custom_err.ErrSubscriptionAlreadyFound)
+			},
+			wantErr: custom_err.ErrSubscriptionAlreadyFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewIServiceRepository(t)
+			validator, _ := validation.New()
+			useCase := New(mockRepo, validator, converters.NewServiceConverter())
+
+			tt.setupMocks(mockRepo)
+			ctx := context.Background()
+
+			_, err := useCase.Merge(ctx, tt.id, &tt.input)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package service_usecase
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"AggregationService/internal/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+func (u *serviceUseCase) Create(ctx context.Context, req *dto.CreateServiceRequest) (*dto.ServiceResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to create service: %+v", req))
+
+	if err := u.validator.Validate(req); err != nil {
+		log.Error(fmt.Sprintf("invalid input: %v", custom_err.ErrInvalidRequest))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	service := u.converter.ToServiceEntity(req)
+	service.CreatedAt = time.Now()
+	service.UpdatedAt = service.CreatedAt
+
+	if err := u.checkKeyFree(ctx, service.NormalizedName, 0); err != nil {
+		log.Error(fmt.Sprintf("failed to create service: %v", err))
+		return nil, err
+	}
+
+	created, err := u.serviceRepository.Create(ctx, service)
+	if err != nil {
+		if errors.Is(err, custom_err.ErrServiceAlreadyExists) {
+			log.Error(fmt.Sprintf("duplicate service: %v", err))
+			return nil, custom_err.ErrServiceAlreadyExists
+		}
+		log.Error(fmt.Sprintf("failed to create service: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success creating service: id=%d", created.ID))
+	return u.converter.ToServiceDTO(created), nil
+}
+
+func (u *serviceUseCase) GetByID(ctx context.Context, id int) (*dto.ServiceResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to get service by id: %d", id))
+
+	service, err := u.getService(ctx, id)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get service by id: %v", err))
+		return nil, err
+	}
+
+	log.Debug(fmt.Sprintf("success get service by id: %d", id))
+	return u.converter.ToServiceDTO(service), nil
+}
+
+func (u *serviceUseCase) GetAll(ctx context.Context, name *string) ([]*dto.ServiceResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+
+	var key *string
+	if name != nil {
+		normalized := utils.NormalizeServiceName(*name)
+		key = &normalized
+	}
+
+	services, err := u.serviceRepository.GetAll(ctx, key)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get services: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success getting services: %d", len(services)))
+	return u.converter.ToServiceDTOs(services), nil
+}
+
+// Update renames a service. The subscriptions of the service show the new
+// name right away.
+func (u *serviceUseCase) Update(ctx context.Context, id int, req *dto.UpdateServiceRequest) (*dto.ServiceResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to update service: id=%d", id))
+
+	if err := u.validator.Validate(req); err != nil {
+		log.Error(fmt.Sprintf("invalid input: %v", custom_err.ErrInvalidRequest))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	service := &entity.Service{
+		ID:             id,
+		Name:           utils.TidyServiceName(req.Name),
+		NormalizedName: utils.NormalizeServiceName(req.Name),
+		UpdatedAt:      time.Now(),
+	}
+	if err := u.checkKeyFree(ctx, service.NormalizedName, id); err != nil {
+		log.Error(fmt.Sprintf("failed to update service: %v", err))
+		return nil, err
+	}
+
+	updated, err := u.serviceRepository.Update(ctx, service)
+	if err != nil {
+		switch {
+		case errors.Is(err, custom_err.ErrServiceNotFound):
+			log.Error(fmt.Sprintf("failed to update service: %v", err))
+			return nil, custom_err.ErrServiceNotFound
+		case errors.Is(err, custom_err.ErrServiceAlreadyExists):
+			log.Error(fmt.Sprintf("duplicate service: %v", err))
+			return nil, custom_err.ErrServiceAlreadyExists
+		}
+		log.Error(fmt.Sprintf("failed to update service: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success update service: id=%d", id))
+	return u.converter.ToServiceDTO(updated), nil
+}
+
+// Delete removes a service that no subscription refers to.
+func (u *serviceUseCase) Delete(ctx context.Context, id int) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to delete service: id=%d", id))
+
+	if err := u.serviceRepository.Delete(ctx, id); err != nil {
+		switch {
+		case errors.Is(err, custom_err.ErrServiceNotFound):
+			log.Error(fmt.Sprintf("failed to delete service: %v", err))
+			return custom_err.ErrServiceNotFound
+		case errors.Is(err, custom_err.ErrServiceInUse):
+			log.Error(fmt.Sprintf("failed to delete service: %v", err))
+			return custom_err.ErrServiceInUse
+		}
+		log.Error(fmt.Sprintf("failed to delete service: %v", err))
+		return custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success delete service: id=%d", id))
+	return nil
+}
+
+// checkKeyFree fails with ErrServiceAlreadyExists when key already names a
+// service other than ownID, directly or through an alias.
+func (u *serviceUseCase) checkKeyFree(ctx context.Context, key string, ownID int) error {
+	existing, err := u.serviceRepository.Resolve(ctx, key)
+	switch {
+	case errors.Is(err, custom_err.ErrServiceNotFound):
+		return nil
+	case err != nil:
+		return custom_err.ErrInternalServer
+	case existing.ID != ownID:
+		return custom_err.ErrServiceAlreadyExists
+	}
+	return nil
+}
+
+func (u *serviceUseCase) getService(ctx context.Context, id int) (*entity.Service, error) {
+	service, err := u.serviceRepository.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, custom_err.ErrServiceNotFound) {
+			return nil, custom_err.ErrServiceNotFound
+		}
+		return nil, custom_err.ErrInternalServer
+	}
+	return service, nil
+}
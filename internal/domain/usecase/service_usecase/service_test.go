@@ -0,0 +1,229 @@
+package service_usecase
+
+import (
+	"AggregationService/internal/converters"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/domain/ports/repository/mocks"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/validation"
+)
+
+func Test_CreateService(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		input      dto.CreateServiceRequest
+		setupMocks func(repo *mocks.IServiceRepository)
+		wantErr    error
+	}{
+		{
+			name:  "Valid service",
+			input: dto.CreateServiceRequest{Name: "Yandex Plus"},
+			setupMocks: func(repo *mocks.IServiceRepository) {
+				repo.On("Resolve", mock.Anything, "yandex plus").Return(nil, custom_err.ErrServiceNotFound)
+				repo.On("Create", mock.Anything, mock.MatchedBy(func(service *entity.Service) bool {
+					return service.Name == "Yandex Plus" && service.NormalizedName == "yandex plus" && !service.CreatedAt.IsZero()
+				})).Return(&entity.Service{ID: 1, Name: "Yandex Plus"}, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name:       "Empty name",
+			input:      dto.CreateServiceRequest{},
+			setupMocks: func(repo *mocks.IServiceRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name:  "Duplicate name",
+			input: dto.CreateServiceRequest{Name: "yandex plus "},
+			setupMocks: func(repo *mocks.IServiceRepository) {
+				repo.On("Resolve", mock.Anything, "yandex plus").Return(&entity.Service{ID: 1, Name: "Yandex Plus"}, nil)
+			},
+			wantErr: custom_err.ErrServiceAlreadyExists,
+		},
+		{
+			name:  "Name taken by an alias",
+			input: dto.CreateServiceRequest{Name: "Кинопоиск"},
+			setupMocks: func(repo *mocks.IServiceRepository) {
+				repo.On("Resolve", mock.Anything, "kinopoisk").Return(&entity.Service{ID: 2, Name: "KinoPoisk HD"}, nil)
+			},
+			wantErr: custom_err.ErrServiceAlreadyExists,
+		},
+		{
+			name:  "Created concurrently",
+			input: dto.CreateServiceRequest{Name: "Okko"},
+			setupMocks: func(repo *mocks.IServiceRepository) {
+				repo.On("Resolve", mock.Anything, mock.Anything).Return(nil, custom_err.ErrServiceNotFound)
+				repo.On("Create", mock.Anything, mock.Anything).Return(nil, custom_err.ErrServiceAlreadyExists)
+			},
+			wantErr: custom_err.ErrServiceAlreadyExists,
+		},
+		{
+			name:  "Repository error",
+			input: dto.CreateServiceRequest{Name: "Kinopoisk"},
+			setupMocks: func(repo *mocks.IServiceRepository) {
+				repo.On("Resolve", mock.Anything, mock.Anything).Return(nil, custom_err.ErrServiceNotFound)
+				repo.On("Create", mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+			},
+			wantErr: custom_err.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewIServiceRepository(t)
+			validator, _ := validation.New()
+			useCase := New(mockRepo, validator, converters.NewServiceConverter())
+
+			tt.setupMocks(mockRepo)
+			ctx := context.Background()
+
+			_, err := useCase.Create(ctx, &tt.input)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_UpdateService(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		id         int
+		input      dto.UpdateServiceRequest
+		setupMocks func(repo *mocks.IServiceRepository)
+		wantErr    error
+	}{
+		{
+			name:  "Rename",
+			id:    1,
+			input: dto.UpdateServiceRequest{Name: "Yandex Plus Multi"},
+			setupMocks: func(repo *mocks.IServiceRepository) {
+				repo.On("Resolve", mock.Anything, mock.Anything).Return(nil, custom_err.ErrServiceNotFound)
+				repo.On("Update", mock.Anything, mock.MatchedBy(func(service *entity.Service) bool {
+					return service.ID == 1 && service.Name == "Yandex Plus Multi"
+				})).Return(&entity.Service{ID: 1, Name: "Yandex Plus Multi"}, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "Not found",
+			id:    999,
+			input: dto.UpdateServiceRequest{Name: "Kinopoisk"},
+			setupMocks: func(repo *mocks.IServiceRepository) {
+				repo.On("Resolve", mock.Anything, mock.Anything).Return(nil, custom_err.ErrServiceNotFound)
+				repo.On("Update", mock.Anything, mock.Anything).Return(nil, custom_err.ErrServiceNotFound)
+			},
+			wantErr: custom_err.ErrServiceNotFound,
+		},
+		{
+			name:  "Name taken",
+			id:    1,
+			input: dto.UpdateServiceRequest{Name: "Kinopoisk"},
+			setupMocks: func(repo *mocks.IServiceRepository) {
+				repo.On("Resolve", mock.Anything, "kinopoisk").Return(&entity.Service{ID: 2, Name: "Kinopoisk"}, nil)
+			},
+			wantErr: custom_err.ErrServiceAlreadyExists,
+		},
+		{
+			name:  "Respelling its own name",
+			id:    2,
+			input: dto.UpdateServiceRequest{Name: "КиноПоиск"},
+			setupMocks: func(repo *mocks.IServiceRepository) {
+				repo.On("Resolve", mock.Anything, "kinopoisk").Return(&entity.Service{ID: 2, Name: "Kinopoisk"}, nil)
+				repo.On("Update", mock.Anything, mock.MatchedBy(func(service *entity.Service) bool {
+					return service.Name == "КиноПоиск" && service.NormalizedName == "kinopoisk"
+				})).Return(&entity.Service{ID: 2, Name: "КиноПоиск"}, nil)
+			},
+			wantErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewIServiceRepository(t)
+			validator, _ := validation.New()
+			useCase := New(mockRepo, validator, converters.NewServiceConverter())
+
+			tt.setupMocks(mockRepo)
+			ctx := context.Background()
+
+			_, err := useCase.Update(ctx, tt.id, &tt.input)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_DeleteService(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		id         int
+		setupMocks func(repo *mocks.IServiceRepository)
+		wantErr    error
+	}{
+		{
+			name: "Valid delete",
+			id:   1,
+			setupMocks: func(repo *mocks.IServiceRepository) {
+				repo.On("Delete", mock.Anything, 1).Return(nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "In use",
+			id:   2,
+			setupMocks: func(repo *mocks.IServiceRepository) {
+				repo.On("Delete", mock.Anything, 2).Return(custom_err.ErrServiceInUse)
+			},
+			wantErr: custom_err.ErrServiceInUse,
+		},
+		{
+			name: "Not found",
+			id:   999,
+			setupMocks: func(repo *mocks.IServiceRepository) {
+				repo.On("Delete", mock.Anything, 999).Return(custom_err.ErrServiceNotFound)
+			},
+			wantErr: custom_err.ErrServiceNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewIServiceRepository(t)
+			validator, _ := validation.New()
+			useCase := New(mockRepo, validator, converters.NewServiceConverter())
+
+			tt.setupMocks(mockRepo)
+			ctx := context.Background()
+
+			err := useCase.Delete(ctx, tt.id)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
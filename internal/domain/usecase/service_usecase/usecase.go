@@ -0,0 +1,40 @@
+package service_usecase
+
+import (
+	"AggregationService/internal/converters"
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/ports/repository"
+	"AggregationService/internal/pkg/validation"
+	"context"
+)
+
+type IServiceUseCase interface {
+	Create(ctx context.Context, req *dto.CreateServiceRequest) (*dto.ServiceResponse, error)
+	GetByID(ctx context.Context, id int) (*dto.ServiceResponse, error)
+	GetAll(ctx context.Context, name *string) ([]*dto.ServiceResponse, error)
+	Update(ctx context.Context, id int, req *dto.UpdateServiceRequest) (*dto.ServiceResponse, error)
+	Delete(ctx context.Context, id int) error
+	AddAlias(ctx context.Context, id int, req *dto.CreateServiceAliasRequest) (*dto.ServiceAliasResponse, error)
+	GetAliases(ctx context.Context, id int) ([]*dto.ServiceAliasResponse, error)
+	DeleteAlias(ctx context.Context, id, aliasID int) error
+	SuggestDuplicates(ctx context.Context, req *dto.ServiceDuplicatesRequest) ([]*dto.ServiceDuplicateResponse, error)
+	Merge(ctx context.Context, id int, req *dto.MergeServiceRequest) (*dto.ServiceResponse, error)
+}
+
+type serviceUseCase struct {
+	serviceRepository repository.IServiceRepository
+	validator         *validation.Validator
+	converter         *converters.ServiceConverter
+}
+
+func New(
+	serviceRepository repository.IServiceRepository,
+	validator *validation.Validator,
+	converter *converters.ServiceConverter,
+) IServiceUseCase {
+	return &serviceUseCase{
+		serviceRepository: serviceRepository,
+		validator:         validator,
+		converter:         converter,
+	}
+}
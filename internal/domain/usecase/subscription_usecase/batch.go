@@ -0,0 +1,209 @@
+package subscription_usecase
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"AggregationService/internal/pkg/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Batch runs the operations of req in one transaction and reports the result
+// of every operation at its position. The operations are checked like
+// Create, Update and Delete do, the subscriptions they change are read and
+// their services resolved within the transaction. When one of them fails, an
+// atomic batch is not applied at all and the other operations fail with
+// ErrBatchRolledBack.
+func (u *subscriptionUseCase) Batch(ctx context.Context, req *dto.SubscriptionBatchRequest) (*dto.SubscriptionBatchResponse, error) {
+	// a batch reads every subscription it changes, so it gets longer than one
+	// change; its route is left out of the short request timeout, see
+	// app.bulkRequestTimeout
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to run subscription batch: %d operations, atomic=%t", len(req.Operations), req.Atomic))
+
+	if err := u.validator.Validate(req); err != nil {
+		log.Error(fmt.Sprintf("invalid input: %v", custom_err.ErrInvalidRequest))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	resp := &dto.SubscriptionBatchResponse{
+		Atomic:  req.Atomic,
+		Results: make([]*dto.SubscriptionBatchResult, len(req.Operations)),
+	}
+	items := make([]*entity.SubscriptionBatchItem, 0, len(req.Operations))
+	positions := make([]int, 0, len(req.Operations))
+	failed := false
+	for i := range req.Operations {
+		op := &req.Operations[i]
+		resp.Results[i] = &dto.SubscriptionBatchResult{Op: op.Op, ID: op.ID}
+
+		item, err := u.batchItem(ctx, op)
+		if err != nil {
+			log.Error(fmt.Sprintf("failed batch operation %d: %v", i, err))
+			resp.Results[i].Err = err
+			failed = true
+			continue
+		}
+		items = append(items, item)
+		positions = append(positions, i)
+	}
+	if len(items) == 0 || req.Atomic && failed {
+		rollBackBatch(resp)
+		return resp, nil
+	}
+
+	budgets := u.batchBudgets(ctx, items)
+
+	err := u.subscriptionRepository.Batch(ctx, items, req.Atomic)
+	if err != nil && !errors.Is(err, custom_err.ErrBatchRolledBack) {
+		log.Error(fmt.Sprintf("failed to run subscription batch: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+	rolledBack := err != nil
+
+	for k, item := range items {
+		result := resp.Results[positions[k]]
+		switch {
+		case item.Err != nil:
+			log.Error(fmt.Sprintf("failed batch operation %d: %v", positions[k], item.Err))
+			result.Err = batchError(item.Err)
+		case !rolledBack:
+			result.ID = &item.Subscription.PublicID
+			if item.Action != entity.BatchActionDelete {
+				result.Subscription = u.converter.ToSubscriptionDTO(item.Subscription)
+			}
+		}
+	}
+	if rolledBack {
+		rollBackBatch(resp)
+		return resp, nil
+	}
+
+	for _, snapshot := range budgets {
+		u.alertBudgets(ctx, snapshot)
+	}
+
+	log.Debug(fmt.Sprintf("success running subscription batch: %d of %d operations applied", len(items), len(req.Operations)))
+	return resp, nil
+}
+
+// batchItem checks one operation of a batch and prepares it for the
+// repository.
+func (u *subscriptionUseCase) batchItem(ctx context.Context, op *dto.SubscriptionBatchOperation) (*entity.SubscriptionBatchItem, error) {
+	if err := u.validator.Validate(op); err != nil {
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	switch op.Op {
+	case entity.BatchActionCreate:
+		var req dto.CreateSubscriptionRequest
+		if err := json.Unmarshal(op.Data, &req); err != nil {
+			return nil, custom_err.ErrInvalidRequest
+		}
+		if err := u.validator.Validate(&req); err != nil {
+			return nil, custom_err.ErrInvalidRequest
+		}
+
+		sub := u.converter.ToSubscriptionEntity(&req)
+		sub.CreatedAt = time.Now()
+		sub.UpdatedAt = sub.CreatedAt
+		return &entity.SubscriptionBatchItem{Action: op.Op, Subscription: sub}, nil
+
+	case entity.BatchActionUpdate:
+		var req dto.UpdateSubscriptionRequest
+		if err := json.Unmarshal(op.Data, &req); err != nil {
+			return nil, custom_err.ErrInvalidRequest
+		}
+		if err := u.validator.Validate(&req); err != nil {
+			return nil, custom_err.ErrInvalidRequest
+		}
+
+		apply := func(sub *entity.Subscription) (*entity.SubscriptionPrice, error) {
+			var price *entity.SubscriptionPrice
+			if req.Price != nil {
+				priceReq := &dto.CreateSubscriptionPriceRequest{
+					Price:         *req.Price,
+					EffectiveFrom: utils.TimeToMonthYear(latestMonth(time.Now(), sub.StartDate)),
+				}
+				if req.PriceFrom != nil {
+					priceReq.EffectiveFrom = *req.PriceFrom
+				}
+				var err error
+				if price, err = u.newPrice(sub, priceReq); err != nil {
+					return nil, err
+				}
+			}
+			u.converter.ApplyUpdateToEntity(sub, &req)
+			sub.UpdatedAt = time.Now()
+			return price, nil
+		}
+		return &entity.SubscriptionBatchItem{Action: op.Op, ID: *op.ID, Version: op.Version, Apply: apply}, nil
+
+	default:
+		return &entity.SubscriptionBatchItem{Action: op.Op, ID: *op.ID, Version: op.Version}, nil
+	}
+}
+
+// batchBudgets snapshots the budgets of the users whose subscriptions items
+// change. The subscriptions of updates and deletes are read here only for
+// their user, which a change keeps; the batch reads them again.
+func (u *subscriptionUseCase) batchBudgets(ctx context.Context, items []*entity.SubscriptionBatchItem) map[uuid.UUID]*entity.BudgetSnapshot {
+	budgets := make(map[uuid.UUID]*entity.BudgetSnapshot)
+	if u.budgets == nil {
+		return budgets
+	}
+	for _, item := range items {
+		sub := item.Subscription
+		if sub == nil {
+			var err error
+			// a missing subscription fails its operation in the batch
+			if sub, err = u.subscriptionRepository.GetByID(ctx, item.ID); err != nil {
+				continue
+			}
+		}
+		if _, ok := budgets[sub.UserID]; !ok {
+			budgets[sub.UserID] = u.snapshotBudgets(ctx, sub.UserID)
+		}
+	}
+	return budgets
+}
+
+// batchError maps the failure of a batch operation in the repository to the
+// error of its own request.
+func batchError(err error) error {
+	if errors.Is(err, custom_err.ErrSubscriptionAlreadyFound) {
+		return overlapError(err)
+	}
+	for _, known := range []error{
+		custom_err.ErrInvalidRequest,
+		custom_err.ErrSubscriptionNotFound,
+		custom_err.ErrVersionMismatch,
+		custom_err.ErrServiceNotFound,
+		custom_err.ErrCategoryNotFound,
+	} {
+		if errors.Is(err, known) {
+			return known
+		}
+	}
+	return custom_err.ErrInternalServer
+}
+
+// rollBackBatch fails the operations of a batch that was not applied and
+// did not fail on their own.
+func rollBackBatch(resp *dto.SubscriptionBatchResponse) {
+	for _, result := range resp.Results {
+		if result.Err == nil {
+			result.Err = custom_err.ErrBatchRolledBack
+			result.Subscription = nil
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package subscription_usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/converters"
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/domain/ports/repository/mocks"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/money"
+	"AggregationService/internal/pkg/validation"
+)
+
+func Test_Batch(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.MustParse("60601fee-2bf1-4721-ae6f-7636e79a0cba")
+	startDate := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	existing := func(id int) *entity.Subscription {
+		return &entity.Subscription{ID: id, PublicID: publicID(id), ServiceID: 1, ServiceName: "yandex",
+			Price: money.FromMajor(299), UserID: userID, StartDate: startDate, Version: 2}
+	}
+	create := dto.SubscriptionBatchOperation{Op: "create",
+		Data: json.RawMessage(`{"service_name": "yandex", "price": 299, "user_id": "60601fee-2bf1-4721-ae6f-7636e79a0cba", "start_date": "01-2025"}`)}
+	update := func(id int) dto.SubscriptionBatchOperation {
+		pid := publicID(id)
+		return dto.SubscriptionBatchOperation{Op: "update", ID: &pid, Data: json.RawMessage(`{"category": "music"}`)}
+	}
+	remove := func(id int) dto.SubscriptionBatchOperation {
+		pid := publicID(id)
+		return dto.SubscriptionBatchOperation{Op: "delete", ID: &pid}
+	}
+	// applied gives the created subscriptions their public id and reads and
+	// changes the others like the repository does
+	applied := func(args mock.Arguments) {
+		for i, item := range args.Get(1).([]*entity.SubscriptionBatchItem) {
+			switch item.Action {
+			case entity.BatchActionCreate:
+				item.Subscription.PublicID = publicID(100 + i)
+			case entity.BatchActionUpdate:
+				item.Subscription = existing(100 + i)
+				item.Subscription.PublicID = item.ID
+				item.Price, item.Err = item.Apply(item.Subscription)
+			case entity.BatchActionDelete:
+				item.Subscription = existing(100 + i)
+				item.Subscription.PublicID = item.ID
+			}
+		}
+	}
+
+	tests := []struct {
+		name       string
+		req        dto.SubscriptionBatchRequest
+		setupMocks func(repo *mocks.ISubscriptionRepository)
+		wantErr    error
+		wantErrs   []error
+	}{
+		{
+			name: "Atomic batch applied",
+			req:  dto.SubscriptionBatchRequest{Atomic: true, Operations: []dto.SubscriptionBatchOperation{create, update(1), remove(2)}},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("Batch", mock.Anything, mock.MatchedBy(func(items []*entity.SubscriptionBatchItem) bool {
+					if len(items) != 3 {
+						return false
+					}
+					// the targets are read and the services resolved by the repository
+					changed := existing(1)
+					price, err := items[1].Apply(changed)
+					return items[0].Action == entity.BatchActionCreate && items[0].Subscription.ServiceName == "yandex" &&
+						items[1].Action == entity.BatchActionUpdate && items[1].ID == publicID(1) && items[1].Subscription == nil &&
+						err == nil && price == nil && changed.Category == "music" &&
+						items[2].Action == entity.BatchActionDelete && items[2].ID == publicID(2) && items[2].Version == nil
+				}), true).Run(applied).Return(nil)
+			},
+			wantErrs: []error{nil, nil, nil},
+		},
+		{
+			name: "Atomic batch with an invalid operation is not run",
+			req: dto.SubscriptionBatchRequest{Atomic: true, Operations: []dto.SubscriptionBatchOperation{
+				{Op: "create", Data: json.RawMessage(`{"price": 299}`)}, update(1)}},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {},
+			wantErrs:   []error{custom_err.ErrInvalidRequest, custom_err.ErrBatchRolledBack},
+		},
+		{
+			name: "Atomic batch rolled back by the repository",
+			req:  dto.SubscriptionBatchRequest{Atomic: true, Operations: []dto.SubscriptionBatchOperation{create, update(1), create}},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("Batch", mock.Anything, mock.Anything, true).Run(func(args mock.Arguments) {
+					args.Get(1).([]*entity.SubscriptionBatchItem)[1].Err = custom_err.ErrVersionMismatch
+				}).Return(custom_err.ErrBatchRolledBack)
+			},
+			wantErrs: []error{custom_err.ErrBatchRolledBack, custom_err.ErrVersionMismatch, custom_err.ErrBatchRolledBack},
+		},
+		{
+			name: "Non-atomic batch keeps the operations that succeed",
+			req: dto.SubscriptionBatchRequest{Operations: []dto.SubscriptionBatchOperation{
+				create, remove(3), {Op: "update"}, create}},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("Batch", mock.Anything, mock.MatchedBy(func(items []*entity.SubscriptionBatchItem) bool {
+					return len(items) == 3
+				}), false).Run(func(args mock.Arguments) {
+					items := args.Get(1).([]*entity.SubscriptionBatchItem)
+					items[0].Subscription.PublicID = publicID(100)
+					items[1].Err = custom_err.ErrSubscriptionNotFound
+					items[2].Err = errors.Join(errors.New("repository.postgres.Create"),
+						&custom_err.SubscriptionConflictError{ConflictingID: 7, ConflictingPublicID: publicID(7)})
+				}).Return(nil)
+			},
+			wantErrs: []error{nil, custom_err.ErrSubscriptionNotFound, custom_err.ErrInvalidRequest, custom_err.ErrSubscriptionAlreadyFound},
+		},
+		{
+			name:       "Empty batch",
+			req:        dto.SubscriptionBatchRequest{Atomic: true},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name: "Repository error",
+			req:  dto.SubscriptionBatchRequest{Atomic: true, Operations: []dto.SubscriptionBatchOperation{create}},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("Batch", mock.Anything, mock.Anything, true).Return(errors.New("commit failed"))
+			},
+			wantErr: custom_err.ErrInternalServer,
+		},
+		{
+			name: "Price before the start of the subscription",
+			req: dto.SubscriptionBatchRequest{Operations: []dto.SubscriptionBatchOperation{
+				{Op: "update", ID: update(1).ID, Data: json.RawMessage(`{"price": 399, "price_from": "12-2024"}`)}}},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("Batch", mock.Anything, mock.Anything, false).Run(applied).Return(nil)
+			},
+			wantErrs: []error{custom_err.ErrInvalidRequest},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewISubscriptionRepository(t)
+			useCase := newStatusUseCase(t, mockRepo)
+			tt.setupMocks(mockRepo)
+
+			result, err := useCase.Batch(context.Background(), &tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.req.Atomic, result.Atomic)
+			assert.Len(t, result.Results, len(tt.wantErrs))
+			for i, wantErr := range tt.wantErrs {
+				got := result.Results[i]
+				assert.Equal(t, tt.req.Operations[i].Op, got.Op)
+				if wantErr != nil {
+					assert.ErrorIs(t, got.Err, wantErr, "operation %d", i)
+					assert.Nil(t, got.Subscription, "operation %d", i)
+					continue
+				}
+				assert.NoError(t, got.Err, "operation %d", i)
+				assert.NotNil(t, got.ID, "operation %d", i)
+				if got.Op != "delete" {
+					assert.Equal(t, *got.ID, got.Subscription.PublicID, "operation %d", i)
+				}
+			}
+		})
+	}
+}
+
+func Test_Batch_BudgetAlerts(t *testing.T) {
+	t.Parallel()
+
+	userA := uuid.MustParse("60601fee-2bf1-4721-ae6f-7636e79a0cba")
+	userB := uuid.MustParse("70601fee-2bf1-4721-ae6f-7636e79a0cba")
+	create := dto.SubscriptionBatchOperation{Op: "create",
+		Data: json.RawMessage(`{"service_name": "yandex", "price": 299, "user_id": "60601fee-2bf1-4721-ae6f-7636e79a0cba", "start_date": "01-2025"}`)}
+	remove := func(id int) dto.SubscriptionBatchOperation {
+		pid := publicID(id)
+		return dto.SubscriptionBatchOperation{Op: "delete", ID: &pid}
+	}
+
+	mockRepo := mocks.NewISubscriptionRepository(t)
+	budgets := new(mockBudgetWatcher)
+	validator, _ := validation.New()
+	useCase := New(mockRepo, mocks.NewIServiceRepository(t), validator, converters.New(), budgets)
+
+	// the user of a change is read once, the missing subscription is left to the batch
+	mockRepo.On("GetByID", mock.Anything, publicID(1)).Return(&entity.Subscription{ID: 1, PublicID: publicID(1), UserID: userB}, nil).Once()
+	mockRepo.On("GetByID", mock.Anything, publicID(2)).Return(nil, custom_err.ErrSubscriptionNotFound).Once()
+	mockRepo.On("Batch", mock.Anything, mock.Anything, false).Run(func(args mock.Arguments) {
+		items := args.Get(1).([]*entity.SubscriptionBatchItem)
+		items[0].Subscription.PublicID = publicID(100)
+		items[1].Subscription = &entity.Subscription{ID: 1, PublicID: publicID(1), UserID: userB}
+		items[2].Err = custom_err.ErrSubscriptionNotFound
+	}).Return(nil)
+	for _, userID := range []uuid.UUID{userA, userB} {
+		snapshot := &entity.BudgetSnapshot{UserID: userID}
+		budgets.On("Snapshot", mock.Anything, userID).Return(snapshot, nil).Once()
+		budgets.On("AlertCrossed", mock.Anything, snapshot).Return(nil).Once()
+	}
+
+	result, err := useCase.Batch(context.Background(), &dto.SubscriptionBatchRequest{
+		Operations: []dto.SubscriptionBatchOperation{create, remove(1), remove(2)}})
+	assert.NoError(t, err)
+	assert.NoError(t, result.Results[1].Err)
+	assert.ErrorIs(t, result.Results[2].Err, custom_err.ErrSubscriptionNotFound)
+	budgets.AssertExpectations(t)
+}
@@ -0,0 +1,59 @@
+package subscription_usecase
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Upcoming lists the charges of auto-renewing subscriptions due in the next
+// req.Days days, today included, grouped by user. Every charge is priced at
+// the price in force on its day.
+func (u *subscriptionUseCase) Upcoming(ctx context.Context, req *dto.UpcomingChargesRequest) (*dto.UpcomingChargesResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to get upcoming charges: %+v", req))
+
+	if err := u.validator.Validate(req); err != nil {
+		log.Error(fmt.Sprintf("invalid input: %v", custom_err.ErrInvalidRequest))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	filter := u.converter.ToChargeFilter(req, time.Now())
+	subs, err := u.subscriptionRepository.GetRenewing(ctx, filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get renewing subscriptions: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	var charges []*entity.Charge
+	for _, sub := range subs {
+		for _, date := range sub.ChargeDates(filter.From, filter.To) {
+			charges = append(charges, &entity.Charge{
+				SubscriptionID:       sub.ID,
+				SubscriptionPublicID: sub.PublicID,
+				ServiceName:          sub.ServiceName,
+				UserID:               sub.UserID,
+				Date:                 date,
+				Amount:               sub.PriceAt(date),
+				Currency:             sub.Currency,
+			})
+		}
+	}
+	sort.SliceStable(charges, func(i, j int) bool {
+		if charges[i].UserID != charges[j].UserID {
+			return charges[i].UserID.String() < charges[j].UserID.String()
+		}
+		return charges[i].Date.Before(charges[j].Date)
+	})
+
+	log.Debug(fmt.Sprintf("success getting upcoming charges: count=%d", len(charges)))
+	return u.converter.ToUpcomingChargesResponse(charges, filter), nil
+}
@@ -0,0 +1,73 @@
+package subscription_usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/domain/ports/repository/mocks"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/money"
+	"AggregationService/internal/pkg/utils"
+)
+
+func Test_UpcomingCharges(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	startDate := utils.MonthStart(today).AddDate(-1, 0, 0)
+	alice := uuid.MustParse("60601fee-2bf1-4721-ae6f-7636e79a0cba")
+	bob := uuid.MustParse("8b0e2f4c-3c3a-4d55-9d1e-2f5b6a7c8d9e")
+
+	t.Run("Charges grouped by user", func(t *testing.T) {
+		mockRepo := mocks.NewISubscriptionRepository(t)
+		useCase := newStatusUseCase(t, mockRepo)
+		mockRepo.On("GetRenewing", mock.Anything, mock.MatchedBy(func(f *entity.ChargeFilter) bool {
+			return f.From.Equal(today) && f.To.Equal(today) && f.UserID == nil
+		})).Return([]*entity.Subscription{
+			{ID: 2, ServiceName: "Kinopoisk", UserID: bob, Price: money.FromMajor(399), Currency: "RUB",
+				BillingPeriod: entity.BillingPeriodMonth, BillingMonths: 1, BillingDay: today.Day(), AutoRenew: true, StartDate: startDate},
+			{ID: 1, ServiceName: "Yandex Plus", UserID: alice, Price: money.FromMajor(399), Currency: "RUB",
+				BillingPeriod: entity.BillingPeriodMonth, BillingMonths: 1, BillingDay: today.Day(), AutoRenew: true, StartDate: startDate,
+				Prices: []*entity.SubscriptionPrice{
+					{Price: money.FromMajor(299), EffectiveFrom: startDate},
+					{Price: money.FromMajor(399), EffectiveFrom: utils.MonthStart(today).AddDate(0, 1, 0)},
+				}},
+		}, nil)
+
+		result, err := useCase.Upcoming(context.Background(), &dto.UpcomingChargesRequest{Days: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, utils.TimeToDayMonthYear(today), result.From)
+		assert.Len(t, result.Users, 2)
+		assert.Equal(t, alice, result.Users[0].UserID)
+		assert.Equal(t, money.FromMajor(299), result.Users[0].Charges[0].Amount, "the current price, not the next one")
+		assert.Equal(t, []*dto.ChargeTotalResponse{{Currency: "RUB", Amount: money.FromMajor(299)}}, result.Users[0].Totals)
+		assert.Equal(t, bob, result.Users[1].UserID)
+		assert.Equal(t, utils.TimeToDayMonthYear(today), result.Users[1].Charges[0].Date)
+	})
+
+	t.Run("Invalid days", func(t *testing.T) {
+		mockRepo := mocks.NewISubscriptionRepository(t)
+		useCase := newStatusUseCase(t, mockRepo)
+
+		_, err := useCase.Upcoming(context.Background(), &dto.UpcomingChargesRequest{Days: 0})
+		assert.True(t, errors.Is(err, custom_err.ErrInvalidRequest))
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		mockRepo := mocks.NewISubscriptionRepository(t)
+		useCase := newStatusUseCase(t, mockRepo)
+		mockRepo.On("GetRenewing", mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+
+		_, err := useCase.Upcoming(context.Background(), &dto.UpcomingChargesRequest{Days: 30})
+		assert.ErrorIs(t, err, custom_err.ErrInternalServer)
+	})
+}
@@ -0,0 +1,218 @@
+package subscription_usecase
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"AggregationService/internal/pkg/money"
+	"AggregationService/internal/pkg/utils"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// importFields are the columns of an import file. All but end_date are
+// required.
+var importFields = []string{"service_name", "price", "user_id", "start_date", "end_date"}
+
+// Import stores the subscriptions of a CSV file. The file is read row by row
+// while the repository copies the valid rows in; every row is validated like
+// a CreateSubscriptionRequest and the problems of the invalid ones are
+// reported by line. Rows overlapping an active subscription or an earlier row
+// are reported too. A dry run reports the same without storing anything.
+// Like Create, an import checks the budgets of every user it adds
+// subscriptions to.
+func (u *subscriptionUseCase) Import(ctx context.Context, file io.Reader, req *dto.ImportSubscriptionsRequest) (*dto.ImportSubscriptionsResponse, error) {
+	// the import streams the whole file, so it sets no timeout of its own and
+	// runs under the one of its route, see app.bulkRequestTimeout
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to import subscriptions: %+v", req))
+
+	if err := u.validator.Validate(req); err != nil {
+		log.Error(fmt.Sprintf("invalid input: %v", custom_err.ErrInvalidRequest))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	if req.Delimiter != "" {
+		reader.Comma, _ = utf8.DecodeRuneInString(req.Delimiter)
+	}
+	header, err := reader.Read()
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to read import header: %v", err))
+		return nil, custom_err.ErrInvalidRequest
+	}
+	positions, err := importPositions(header, req.Columns)
+	if err != nil {
+		log.Error(fmt.Sprintf("invalid import header: %v", err))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	resp := &dto.ImportSubscriptionsResponse{DryRun: req.DryRun, Errors: make([]*dto.ImportLineError, 0)}
+	services := make(map[string]*entity.Service)
+	// the rows are stored only after the whole file is read, so the budgets
+	// are snapshot as their users come up
+	budgets := make(map[uuid.UUID]*entity.BudgetSnapshot)
+	next := func() (*entity.SubscriptionImportRow, error) {
+		for {
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				return nil, nil
+			}
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				resp.Rows++
+				resp.Errors = append(resp.Errors, &dto.ImportLineError{
+					Line:   parseErr.StartLine,
+					Errors: map[string][]string{"_error": {parseErr.Err.Error()}},
+				})
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			resp.Rows++
+			line, _ := reader.FieldPos(0)
+			sub, problems, err := u.importRow(ctx, record, positions, services)
+			if err != nil {
+				return nil, err
+			}
+			if problems != nil {
+				resp.Errors = append(resp.Errors, &dto.ImportLineError{Line: line, Errors: problems})
+				continue
+			}
+			if _, ok := budgets[sub.UserID]; !ok && !req.DryRun {
+				budgets[sub.UserID] = u.snapshotBudgets(ctx, sub.UserID)
+			}
+			return &entity.SubscriptionImportRow{Line: line, Subscription: sub}, nil
+		}
+	}
+
+	report, err := u.subscriptionRepository.Import(ctx, next, req.DryRun)
+	if err != nil {
+		if errors.Is(err, custom_err.ErrSubscriptionAlreadyFound) {
+			log.Error(fmt.Sprintf("imported subscription would overlap: %v", err))
+			return nil, custom_err.ErrSubscriptionAlreadyFound
+		}
+		if errors.Is(err, custom_err.ErrCategoryNotFound) {
+			log.Error(fmt.Sprintf("unknown category: %v", err))
+			return nil, custom_err.ErrCategoryNotFound
+		}
+		log.Error(fmt.Sprintf("failed to import subscriptions: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	resp.Imported = report.Imported
+	for _, rejected := range report.Rejected {
+		resp.Errors = append(resp.Errors, &dto.ImportLineError{
+			Line:   rejected.Line,
+			Errors: map[string][]string{"_error": {rejected.Err.Error()}},
+		})
+	}
+	sort.SliceStable(resp.Errors, func(i, j int) bool { return resp.Errors[i].Line < resp.Errors[j].Line })
+
+	for _, snapshot := range budgets {
+		u.alertBudgets(ctx, snapshot)
+	}
+
+	log.Debug(fmt.Sprintf("success importing subscriptions: %d of %d rows, dry run %t", resp.Imported, resp.Rows, req.DryRun))
+	return resp, nil
+}
+
+// importPositions finds the column of every import field in header. columns
+// renames the fields; the header is matched ignoring case and spaces.
+func importPositions(header []string, columns map[string]string) (map[string]int, error) {
+	positions := make(map[string]int, len(importFields))
+	for _, field := range importFields {
+		name := field
+		if column, ok := columns[field]; ok {
+			name = column
+		}
+		for i, h := range header {
+			// spreadsheets often start the file with a byte order mark
+			h = strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))
+			if strings.EqualFold(h, strings.TrimSpace(name)) {
+				positions[field] = i
+				break
+			}
+		}
+		if _, ok := positions[field]; !ok && field != "end_date" {
+			return nil, fmt.Errorf("no column %q for %s", name, field)
+		}
+	}
+	return positions, nil
+}
+
+// importRow converts a row of an import file into a subscription, or returns
+// its problems by field. The service is resolved like in Create, except that
+// unknown names are left for the repository to add, so that a dry run adds
+// nothing. services caches the lookups by normalized name.
+func (u *subscriptionUseCase) importRow(ctx context.Context, record []string, positions map[string]int,
+	services map[string]*entity.Service) (*entity.Subscription, map[string][]string, error) {
+	field := func(name string) string {
+		i, ok := positions[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	req := dto.CreateSubscriptionRequest{ServiceName: field("service_name"), StartDate: field("start_date")}
+	problems := make(map[string][]string)
+	var err error
+	if v := field("price"); v != "" {
+		if req.Price, err = money.Parse(v); err != nil {
+			problems["price"] = append(problems["price"], "price must be a decimal amount")
+		}
+	}
+	if v := field("user_id"); v != "" {
+		if req.UserID, err = uuid.Parse(v); err != nil {
+			problems["user_id"] = append(problems["user_id"], "user_id must be a valid uuid4")
+		}
+	}
+	if v := field("end_date"); v != "" {
+		req.EndDate = &v
+	}
+	for key, messages := range u.validator.ValidateStruct(&req) {
+		// a value that didn't parse is reported once
+		if _, ok := problems[key]; !ok {
+			problems[key] = messages
+		}
+	}
+	if len(problems) > 0 {
+		return nil, problems, nil
+	}
+
+	sub := u.converter.ToSubscriptionEntity(&req)
+	if sub.EndDate != nil && sub.EndDate.Before(sub.StartDate) {
+		return nil, map[string][]string{"end_date": {"end_date must not be before start_date"}}, nil
+	}
+	sub.CreatedAt = time.Now()
+	sub.UpdatedAt = sub.CreatedAt
+
+	key := utils.NormalizeServiceName(sub.ServiceName)
+	service, ok := services[key]
+	if !ok {
+		service, err = u.serviceRepository.Resolve(ctx, key)
+		if err != nil && !errors.Is(err, custom_err.ErrServiceNotFound) {
+			return nil, nil, fmt.Errorf("to resolve service: %w", err)
+		}
+		services[key] = service
+	}
+	if service != nil {
+		sub.ServiceID = service.ID
+		sub.ServiceName = service.Name
+	}
+	return sub, nil, nil
+}
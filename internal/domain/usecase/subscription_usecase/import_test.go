@@ -0,0 +1,235 @@
+package subscription_usecase
+
+import (
+	"AggregationService/internal/converters"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/domain/ports/repository/mocks"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/money"
+	"AggregationService/internal/pkg/validation"
+)
+
+// drainImport returns an Import of the repository that reads every row of
+// next into rows and reports them all imported but rejected.
+func drainImport(rows *[]*entity.SubscriptionImportRow, rejected ...*entity.SubscriptionImportRejection) func(context.Context, func() (*entity.SubscriptionImportRow, error), bool) (*entity.SubscriptionImportReport, error) {
+	return func(ctx context.Context, next func() (*entity.SubscriptionImportRow, error), dryRun bool) (*entity.SubscriptionImportReport, error) {
+		for {
+			row, err := next()
+			if err != nil {
+				return nil, err
+			}
+			if row == nil {
+				break
+			}
+			*rows = append(*rows, row)
+		}
+		return &entity.SubscriptionImportReport{Imported: int64(len(*rows) - len(rejected)), Rejected: rejected}, nil
+	}
+}
+
+func Test_ImportSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	const userID = "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+
+	tests := []struct {
+		name       string
+		file       string
+		req        dto.ImportSubscriptionsRequest
+		rejected   []*entity.SubscriptionImportRejection
+		wantLines  []int
+		wantErrors map[int][]string
+		wantErr    error
+	}{
+		{
+			name: "Valid and invalid rows",
+			file: "\ufeffservice_name,price,user_id,start_date,end_date\n" +
+				"Yandex Plus,299," + userID + ",01-2025,\n" +
+				"Netflix,abc," + userID + ",01-2025,\n" +
+				"Netflix,499,,01-2025,\n" +
+				"Netflix,499," + userID + ",05-2025,03-2025\n" +
+				"\"Spotify\"x,199," + userID + ",01-2025,\n" +
+				"Spotify,199.90," + userID + ",01-2025,12-2025\n",
+			rejected:  []*entity.SubscriptionImportRejection{{Line: 7, Err: custom_err.ErrSubscriptionAlreadyFound}},
+			wantLines: []int{2, 7},
+			wantErrors: map[int][]string{
+				3: {"price"}, 4: {"user_id"}, 5: {"end_date"}, 6: {"_error"}, 7: {"_error"},
+			},
+		},
+		{
+			name: "Column mapping and delimiter",
+			file: "Service;Cost;Customer;From\n" +
+				"Yandex Plus;299;" + userID + ";01-2025\n",
+			req: dto.ImportSubscriptionsRequest{Delimiter: ";", DryRun: true, Columns: map[string]string{
+				"service_name": "service", "price": "Cost", "user_id": "Customer", "start_date": "From"}},
+			wantLines:  []int{2},
+			wantErrors: map[int][]string{},
+		},
+		{
+			name:    "Missing required column",
+			file:    "service_name,price,start_date\nYandex Plus,299,01-2025\n",
+			wantErr: custom_err.ErrInvalidRequest,
+		},
+		{
+			name:    "Invalid delimiter",
+			file:    "service_name\"price\n",
+			req:     dto.ImportSubscriptionsRequest{Delimiter: "\""},
+			wantErr: custom_err.ErrInvalidRequest,
+		},
+		{
+			name:    "Unknown column in mapping",
+			file:    "service_name,price,user_id,start_date\n",
+			req:     dto.ImportSubscriptionsRequest{Columns: map[string]string{"category": "Category"}},
+			wantErr: custom_err.ErrInvalidRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewISubscriptionRepository(t)
+			serviceRepo := mocks.NewIServiceRepository(t)
+			validator, _ := validation.New()
+			useCase := New(mockRepo, serviceRepo, validator, converters.New(), nil)
+
+			var rows []*entity.SubscriptionImportRow
+			if tt.wantErr == nil {
+				serviceRepo.On("Resolve", mock.Anything, "yandex plus").Return(&entity.Service{ID: 1, Name: "Yandex Plus"}, nil).Maybe()
+				serviceRepo.On("Resolve", mock.Anything, mock.Anything).Return(nil, custom_err.ErrServiceNotFound).Maybe()
+				mockRepo.On("Import", mock.Anything, mock.Anything, tt.req.DryRun).Return(drainImport(&rows, tt.rejected...))
+			}
+
+			result, err := useCase.Import(context.Background(), strings.NewReader(tt.file), &tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.req.DryRun, result.DryRun)
+
+			lines := make([]int, 0, len(rows))
+			for _, row := range rows {
+				lines = append(lines, row.Line)
+			}
+			assert.Equal(t, tt.wantLines, lines)
+			assert.Equal(t, int64(len(tt.wantLines)-len(tt.rejected)), result.Imported)
+
+			gotErrors := make(map[int][]string)
+			for i, lineErr := range result.Errors {
+				if i > 0 {
+					assert.LessOrEqual(t, result.Errors[i-1].Line, lineErr.Line)
+				}
+				for field := range lineErr.Errors {
+					gotErrors[lineErr.Line] = append(gotErrors[lineErr.Line], field)
+				}
+			}
+			assert.Equal(t, tt.wantErrors, gotErrors)
+		})
+	}
+}
+
+func Test_ImportSubscriptions_Rows(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := mocks.NewISubscriptionRepository(t)
+	serviceRepo := mocks.NewIServiceRepository(t)
+	validator, _ := validation.New()
+	useCase := New(mockRepo, serviceRepo, validator, converters.New(), nil)
+
+	serviceRepo.On("Resolve", mock.Anything, "yandex plus").Return(&entity.Service{ID: 1, Name: "Yandex Plus"}, nil).Once()
+	serviceRepo.On("Resolve", mock.Anything, "spotify").Return(nil, custom_err.ErrServiceNotFound).Once()
+	var rows []*entity.SubscriptionImportRow
+	mockRepo.On("Import", mock.Anything, mock.Anything, false).Return(drainImport(&rows))
+
+	file := "service_name,price,user_id,start_date,end_date\n" +
+		"yandex  plus,299,60601fee-2bf1-4721-ae6f-7636e79a0cba,01-2025,\n" +
+		"Spotify,199.90,60601fee-2bf1-4721-ae6f-7636e79a0cba,01-2025,12-2025\n" +
+		"Yandex Plus,299,70601fee-2bf1-4721-ae6f-7636e79a0cba,01-2025,\n"
+	result, err := useCase.Import(context.Background(), strings.NewReader(file), &dto.ImportSubscriptionsRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Rows)
+	assert.Len(t, rows, 3)
+
+	// known services are resolved once, unknown ones are left to the repository
+	assert.Equal(t, 1, rows[0].Subscription.ServiceID)
+	assert.Equal(t, "Yandex Plus", rows[0].Subscription.ServiceName)
+	assert.Equal(t, 0, rows[1].Subscription.ServiceID)
+	assert.Equal(t, money.Amount(19990), rows[1].Subscription.Price)
+	assert.Equal(t, "12-2025", rows[1].Subscription.EndDate.Format("01-2006"))
+	assert.Equal(t, entity.BillingPeriodMonth, rows[1].Subscription.BillingPeriod)
+	assert.Equal(t, 1, rows[2].Subscription.ServiceID)
+}
+
+func Test_ImportSubscriptions_BudgetAlerts(t *testing.T) {
+	t.Parallel()
+
+	userA := uuid.MustParse("60601fee-2bf1-4721-ae6f-7636e79a0cba")
+	userB := uuid.MustParse("70601fee-2bf1-4721-ae6f-7636e79a0cba")
+	file := "service_name,price,user_id,start_date,end_date\n" +
+		"Yandex Plus,299," + userA.String() + ",01-2025,\n" +
+		"Spotify,199," + userA.String() + ",01-2025,\n" +
+		"Yandex Plus,299," + userB.String() + ",01-2025,\n"
+
+	tests := []struct {
+		name       string
+		dryRun     bool
+		setupMocks func(budgets *mockBudgetWatcher)
+	}{
+		{
+			name: "Checks the budgets of every user once",
+			setupMocks: func(budgets *mockBudgetWatcher) {
+				for _, userID := range []uuid.UUID{userA, userB} {
+					snapshot := &entity.BudgetSnapshot{UserID: userID}
+					budgets.On("Snapshot", mock.Anything, userID).Return(snapshot, nil).Once()
+					budgets.On("AlertCrossed", mock.Anything, snapshot).Return(nil).Once()
+				}
+			},
+		},
+		{
+			name:       "Dry run checks nothing",
+			dryRun:     true,
+			setupMocks: func(budgets *mockBudgetWatcher) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewISubscriptionRepository(t)
+			budgets := new(mockBudgetWatcher)
+			validator, _ := validation.New()
+			serviceRepo := mocks.NewIServiceRepository(t)
+			serviceRepo.On("Resolve", mock.Anything, mock.Anything).Return(&entity.Service{ID: 1, Name: "Yandex Plus"}, nil)
+			useCase := New(mockRepo, serviceRepo, validator, converters.New(), budgets)
+			tt.setupMocks(budgets)
+
+			var rows []*entity.SubscriptionImportRow
+			mockRepo.On("Import", mock.Anything, mock.Anything, tt.dryRun).Return(drainImport(&rows))
+
+			result, err := useCase.Import(context.Background(), strings.NewReader(file), &dto.ImportSubscriptionsRequest{DryRun: tt.dryRun})
+			assert.NoError(t, err)
+			assert.Equal(t, int64(3), result.Imported)
+			budgets.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_ImportSubscriptions_RepositoryError(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := mocks.NewISubscriptionRepository(t)
+	useCase := newStatusUseCase(t, mockRepo)
+	mockRepo.On("Import", mock.Anything, mock.Anything, false).Return(nil, errors.New("copy failed"))
+
+	_, err := useCase.Import(context.Background(), strings.NewReader("service_name,price,user_id,start_date\n"), &dto.ImportSubscriptionsRequest{})
+	assert.ErrorIs(t, err, custom_err.ErrInternalServer)
+}
@@ -0,0 +1,102 @@
+package subscription_usecase
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"AggregationService/internal/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+func (u *subscriptionUseCase) AddPrice(ctx context.Context, id uuid.UUID, req *dto.CreateSubscriptionPriceRequest) (*dto.SubscriptionPriceResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to add subscription price: id=%s %+v", id, req))
+
+	if err := u.validator.Validate(req); err != nil {
+		log.Error(fmt.Sprintf("invalid input: %v", custom_err.ErrInvalidRequest))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	sub, err := u.subscriptionRepository.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, custom_err.ErrSubscriptionNotFound) {
+			log.Error(fmt.Sprintf("failed to get subscription for price: %v", err))
+			return nil, custom_err.ErrSubscriptionNotFound
+		}
+		log.Error(fmt.Sprintf("failed to get subscription for price: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	price, err := u.newPrice(sub, req)
+	if err != nil {
+		log.Error(fmt.Sprintf("invalid price: %v", err))
+		return nil, err
+	}
+
+	created, err := u.subscriptionRepository.AddPrice(ctx, price)
+	if err != nil {
+		if errors.Is(err, custom_err.ErrSubscriptionNotFound) {
+			log.Error(fmt.Sprintf("failed to add subscription price: %v", err))
+			return nil, custom_err.ErrSubscriptionNotFound
+		}
+		log.Error(fmt.Sprintf("failed to add subscription price: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success adding subscription price: id=%s from %s", id, req.EffectiveFrom))
+	return u.converter.ToSubscriptionPriceDTO(created), nil
+}
+
+func (u *subscriptionUseCase) GetPrices(ctx context.Context, id uuid.UUID) ([]*dto.SubscriptionPriceResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to get subscription prices: id=%s", id))
+
+	sub, err := u.subscriptionRepository.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, custom_err.ErrSubscriptionNotFound) {
+			log.Error(fmt.Sprintf("failed to get subscription for prices: %v", err))
+			return nil, custom_err.ErrSubscriptionNotFound
+		}
+		log.Error(fmt.Sprintf("failed to get subscription for prices: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	prices, err := u.subscriptionRepository.GetPrices(ctx, sub.ID)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get subscription prices: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success getting subscription prices: id=%s", id))
+	return u.converter.ToSubscriptionPriceDTOs(prices), nil
+}
+
+// newPrice converts a validated price request into a price period of sub.
+// A period cannot start before the subscription does.
+func (u *subscriptionUseCase) newPrice(sub *entity.Subscription, req *dto.CreateSubscriptionPriceRequest) (*entity.SubscriptionPrice, error) {
+	price := u.converter.ToSubscriptionPriceEntity(sub.ID, req)
+	if price.EffectiveFrom.Before(utils.MonthStart(sub.StartDate)) {
+		return nil, custom_err.ErrInvalidRequest
+	}
+	return price, nil
+}
+
+// latestMonth returns the first day of the later month of a and b.
+func latestMonth(a, b time.Time) time.Time {
+	a, b = utils.MonthStart(a), utils.MonthStart(b)
+	if a.After(b) {
+		return a
+	}
+	return b
+}
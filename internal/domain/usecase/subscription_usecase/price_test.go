@@ -0,0 +1,166 @@
+package subscription_usecase
+
+import (
+	"AggregationService/internal/converters"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/domain/ports/repository/mocks"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/money"
+	"AggregationService/internal/pkg/validation"
+)
+
+func Test_AddPrice(t *testing.T) {
+	t.Parallel()
+
+	startDate := time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)
+	sub := &entity.Subscription{ID: 1, ServiceName: "yandex", Price: money.FromMajor(299), StartDate: startDate}
+
+	tests := []struct {
+		name       string
+		id         uuid.UUID
+		input      dto.CreateSubscriptionPriceRequest
+		setupMocks func(repo *mocks.ISubscriptionRepository)
+		wantFrom   string
+		wantErr    error
+	}{
+		{
+			name:  "Valid price change",
+			id:    publicID(1),
+			input: dto.CreateSubscriptionPriceRequest{Price: money.FromMajor(399), EffectiveFrom: "11-2025"},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetByID", mock.Anything, publicID(1)).Return(sub, nil)
+				repo.On("AddPrice", mock.Anything, mock.MatchedBy(func(p *entity.SubscriptionPrice) bool {
+					return p.SubscriptionID == 1 && p.Price == money.FromMajor(399) &&
+						p.EffectiveFrom.Equal(time.Date(2025, time.November, 1, 0, 0, 0, 0, time.UTC))
+				})).Return(&entity.SubscriptionPrice{
+					ID:             2,
+					SubscriptionID: 1,
+					Price:          money.FromMajor(399),
+					EffectiveFrom:  time.Date(2025, time.November, 1, 0, 0, 0, 0, time.UTC),
+				}, nil)
+			},
+			wantFrom: "11-2025",
+			wantErr:  nil,
+		},
+		{
+			name:       "Invalid price",
+			id:         publicID(1),
+			input:      dto.CreateSubscriptionPriceRequest{Price: 0, EffectiveFrom: "11-2025"},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name:  "Before subscription start",
+			id:    publicID(1),
+			input: dto.CreateSubscriptionPriceRequest{Price: money.FromMajor(399), EffectiveFrom: "08-2025"},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetByID", mock.Anything, publicID(1)).Return(sub, nil)
+			},
+			wantErr: custom_err.ErrInvalidRequest,
+		},
+		{
+			name:  "Subscription not found",
+			id:    publicID(999),
+			input: dto.CreateSubscriptionPriceRequest{Price: money.FromMajor(399), EffectiveFrom: "11-2025"},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetByID", mock.Anything, publicID(999)).Return(nil, custom_err.ErrSubscriptionNotFound)
+			},
+			wantErr: custom_err.ErrSubscriptionNotFound,
+		},
+		{
+			name:  "Repository error",
+			id:    publicID(1),
+			input: dto.CreateSubscriptionPriceRequest{Price: money.FromMajor(399), EffectiveFrom: "11-2025"},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetByID", mock.Anything, publicID(1)).Return(sub, nil)
+				repo.On("AddPrice", mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+			},
+			wantErr: custom_err.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewISubscriptionRepository(t)
+			validator, _ := validation.New()
+			converter := converters.New()
+			useCase := New(mockRepo, mocks.NewIServiceRepository(t), validator, converter, nil)
+
+			tt.setupMocks(mockRepo)
+
+			result, err := useCase.AddPrice(context.Background(), tt.id, &tt.input)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantFrom, result.EffectiveFrom)
+			}
+		})
+	}
+}
+
+func Test_GetPrices(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		id         uuid.UUID
+		setupMocks func(repo *mocks.ISubscriptionRepository)
+		wantLen    int
+		wantErr    error
+	}{
+		{
+			name: "Price history",
+			id:   publicID(1),
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetByID", mock.Anything, publicID(1)).Return(&entity.Subscription{ID: 1}, nil)
+				repo.On("GetPrices", mock.Anything, 1).Return([]*entity.SubscriptionPrice{
+					{ID: 1, SubscriptionID: 1, Price: money.FromMajor(299), EffectiveFrom: time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)},
+					{ID: 2, SubscriptionID: 1, Price: money.FromMajor(399), EffectiveFrom: time.Date(2025, time.November, 1, 0, 0, 0, 0, time.UTC)},
+				}, nil)
+			},
+			wantLen: 2,
+			wantErr: nil,
+		},
+		{
+			name: "Subscription not found",
+			id:   publicID(999),
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetByID", mock.Anything, publicID(999)).Return(nil, custom_err.ErrSubscriptionNotFound)
+			},
+			wantErr: custom_err.ErrSubscriptionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewISubscriptionRepository(t)
+			validator, _ := validation.New()
+			converter := converters.New()
+			useCase := New(mockRepo, mocks.NewIServiceRepository(t), validator, converter, nil)
+
+			tt.setupMocks(mockRepo)
+
+			result, err := useCase.GetPrices(context.Background(), tt.id)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, result, tt.wantLen)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package subscription_usecase
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"AggregationService/internal/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Pause pauses an active subscription from the month req.From, by default
+// from the next month. The paused months cost nothing until it is resumed.
+// The subscription stays active until the pause starts. version works like in
+// Update.
+func (u *subscriptionUseCase) Pause(ctx context.Context, id uuid.UUID, req *dto.PauseSubscriptionRequest, version *int) (*dto.SubscriptionResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to pause subscription: id=%s", id))
+
+	if err := u.validator.Validate(req); err != nil {
+		log.Error(fmt.Sprintf("invalid input: %v", custom_err.ErrInvalidRequest))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	now := time.Now()
+	sub, err := u.subscriptionForStatus(ctx, id, version, entity.SubscriptionStatusActive, now)
+	if err != nil {
+		return nil, err
+	}
+
+	from := utils.MonthStart(now).AddDate(0, 1, 0)
+	if req.From != nil {
+		from, _ = utils.ParseMonthYearToTime(*req.From)
+	}
+	if from.Before(utils.MonthStart(sub.StartDate)) || (sub.EndDate != nil && from.After(*sub.EndDate)) {
+		log.Error(fmt.Sprintf("pause outside of subscription: %s", utils.TimeToMonthYear(from)))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	budgets := u.snapshotBudgets(ctx, sub.UserID)
+
+	pause := &entity.SubscriptionPause{SubscriptionID: sub.ID, PausedFrom: from}
+	paused, err := u.subscriptionRepository.Pause(ctx, pause, sub.Version)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to pause subscription: %v", err))
+		return nil, statusChangeError(err)
+	}
+
+	u.alertBudgets(ctx, budgets)
+
+	log.Debug(fmt.Sprintf("success pause subscription: id=%s from %s", id, utils.TimeToMonthYear(from)))
+	return u.converter.ToSubscriptionDTO(paused), nil
+}
+
+// Resume resumes a paused subscription from the month req.From, by default
+// from the current month. version works like in Update.
+func (u *subscriptionUseCase) Resume(ctx context.Context, id uuid.UUID, req *dto.ResumeSubscriptionRequest, version *int) (*dto.SubscriptionResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to resume subscription: id=%s", id))
+
+	if err := u.validator.Validate(req); err != nil {
+		log.Error(fmt.Sprintf("invalid input: %v", custom_err.ErrInvalidRequest))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	now := time.Now()
+	sub, err := u.subscriptionForStatus(ctx, id, version, entity.SubscriptionStatusPaused, now)
+	if err != nil {
+		return nil, err
+	}
+
+	from := latestMonth(now, sub.StartDate)
+	if req.From != nil {
+		from, _ = utils.ParseMonthYearToTime(*req.From)
+	}
+	if from.Before(utils.MonthStart(sub.StartDate)) {
+		log.Error(fmt.Sprintf("resume before subscription start: %s", utils.TimeToMonthYear(from)))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	budgets := u.snapshotBudgets(ctx, sub.UserID)
+
+	resumed, err := u.subscriptionRepository.Resume(ctx, sub.ID, from, sub.Version)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to resume subscription: %v", err))
+		return nil, statusChangeError(err)
+	}
+
+	u.alertBudgets(ctx, budgets)
+
+	log.Debug(fmt.Sprintf("success resume subscription: id=%s from %s", id, utils.TimeToMonthYear(from)))
+	return u.converter.ToSubscriptionDTO(resumed), nil
+}
+
+// Cancel cancels an active or paused subscription after the month
+// req.EndDate, by default after the current month. An earlier end date of the
+// subscription is kept. version works like in Update.
+func (u *subscriptionUseCase) Cancel(ctx context.Context, id uuid.UUID, req *dto.CancelSubscriptionRequest, version *int) (*dto.SubscriptionResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to cancel subscription: id=%s", id))
+
+	if err := u.validator.Validate(req); err != nil {
+		log.Error(fmt.Sprintf("invalid input: %v", custom_err.ErrInvalidRequest))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	now := time.Now()
+	sub, err := u.subscriptionForStatus(ctx, id, version, "", now)
+	if err != nil {
+		return nil, err
+	}
+
+	endDate := latestMonth(now, sub.StartDate)
+	if req.EndDate != nil {
+		endDate, _ = utils.ParseMonthYearToTime(*req.EndDate)
+	}
+	if endDate.Before(utils.MonthStart(sub.StartDate)) {
+		log.Error(fmt.Sprintf("cancel before subscription start: %s", utils.TimeToMonthYear(endDate)))
+		return nil, custom_err.ErrInvalidRequest
+	}
+	if sub.EndDate != nil && sub.EndDate.Before(endDate) {
+		endDate = *sub.EndDate
+	}
+
+	budgets := u.snapshotBudgets(ctx, sub.UserID)
+
+	cancelled, err := u.subscriptionRepository.Cancel(ctx, sub.ID, endDate, sub.Version)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to cancel subscription: %v", err))
+		return nil, statusChangeError(err)
+	}
+
+	u.alertBudgets(ctx, budgets)
+
+	log.Debug(fmt.Sprintf("success cancel subscription: id=%s after %s", id, utils.TimeToMonthYear(endDate)))
+	return u.converter.ToSubscriptionDTO(cancelled), nil
+}
+
+func (u *subscriptionUseCase) GetPauses(ctx context.Context, id uuid.UUID) ([]*dto.SubscriptionPauseResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to get subscription pauses: id=%s", id))
+
+	sub, err := u.subscriptionRepository.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, custom_err.ErrSubscriptionNotFound) {
+			log.Error(fmt.Sprintf("failed to get subscription for pauses: %v", err))
+			return nil, custom_err.ErrSubscriptionNotFound
+		}
+		log.Error(fmt.Sprintf("failed to get subscription for pauses: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	pauses, err := u.subscriptionRepository.GetPauses(ctx, sub.ID)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get subscription pauses: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success getting subscription pauses: id=%s", id))
+	return u.converter.ToSubscriptionPauseDTOs(pauses), nil
+}
+
+// subscriptionForStatus reads the subscription whose status is about to
+// change. It must be at version when version is set and in status at now;
+// an empty status accepts an active or paused subscription.
+func (u *subscriptionUseCase) subscriptionForStatus(ctx context.Context, id uuid.UUID, version *int, status string, now time.Time) (*entity.Subscription, error) {
+	log := logger.FromContext(ctx)
+
+	sub, err := u.subscriptionRepository.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, custom_err.ErrSubscriptionNotFound) {
+			log.Error(fmt.Sprintf("failed to get subscription for status change: %v", err))
+			return nil, custom_err.ErrSubscriptionNotFound
+		}
+		log.Error(fmt.Sprintf("failed to get subscription for status change: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+	if version != nil && *version != sub.Version {
+		log.Error(fmt.Sprintf("stale subscription version: want %d, have %d", *version, sub.Version))
+		return nil, custom_err.ErrVersionMismatch
+	}
+
+	current := sub.StatusAt(now)
+	// a pause that starts later can be resumed, which drops it, but not
+	// paused again
+	if sub.Status == entity.SubscriptionStatusPaused {
+		current = entity.SubscriptionStatusPaused
+	}
+	allowed := current == status
+	if status == "" {
+		allowed = current == entity.SubscriptionStatusActive || current == entity.SubscriptionStatusPaused
+	}
+	if !allowed {
+		log.Error(fmt.Sprintf("subscription %s is %s", id, current))
+		return nil, custom_err.ErrInvalidStatusTransition
+	}
+	return sub, nil
+}
+
+// statusChangeError maps a failed status change in the repository to the
+// error returned to the caller.
+func statusChangeError(err error) error {
+	switch {
+	case errors.Is(err, custom_err.ErrSubscriptionNotFound):
+		return custom_err.ErrSubscriptionNotFound
+	case errors.Is(err, custom_err.ErrVersionMismatch):
+		return custom_err.ErrVersionMismatch
+	case errors.Is(err, custom_err.ErrInvalidRequest):
+		return custom_err.ErrInvalidRequest
+	default:
+		return custom_err.ErrInternalServer
+	}
+}
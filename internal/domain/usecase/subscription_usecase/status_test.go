@@ -0,0 +1,288 @@
+package subscription_usecase
+
+import (
+	"AggregationService/internal/converters"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/domain/ports/repository/mocks"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/money"
+	"AggregationService/internal/pkg/utils"
+	"AggregationService/internal/pkg/validation"
+)
+
+func newStatusUseCase(t *testing.T, repo *mocks.ISubscriptionRepository) ISubscriptionUseCase {
+	validator, _ := validation.New()
+	return New(repo, mocks.NewIServiceRepository(t), validator, converters.New(), nil)
+}
+
+func Test_PauseSubscription(t *testing.T) {
+	t.Parallel()
+
+	startDate := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2030, time.December, 1, 0, 0, 0, 0, time.UTC)
+	nextMonth := utils.MonthStart(time.Now()).AddDate(0, 1, 0)
+	active := func() *entity.Subscription {
+		return &entity.Subscription{ID: 1, Price: money.FromMajor(299), StartDate: startDate,
+			Status: entity.SubscriptionStatusActive, Version: 2}
+	}
+	from := func(s string) *string { return &s }
+	version := func(v int) *int { return &v }
+
+	tests := []struct {
+		name       string
+		input      dto.PauseSubscriptionRequest
+		version    *int
+		setupMocks func(repo *mocks.ISubscriptionRepository)
+		wantStatus string
+		wantErr    error
+	}{
+		{
+			name:  "Pause from month",
+			input: dto.PauseSubscriptionRequest{From: from("03-2025")},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetByID", mock.Anything, publicID(1)).Return(active(), nil)
+				repo.On("Pause", mock.Anything, mock.MatchedBy(func(p *entity.SubscriptionPause) bool {
+					return p.SubscriptionID == 1 && p.PausedFrom.Equal(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC))
+				}), 2).Return(&entity.Subscription{ID: 1, StartDate: startDate, Status: entity.SubscriptionStatusPaused, Version: 3,
+					Pauses: []*entity.SubscriptionPause{{SubscriptionID: 1, PausedFrom: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)}}}, nil)
+			},
+			wantStatus: entity.SubscriptionStatusPaused,
+		},
+		{
+			name:  "Pause from next month by default, active until then",
+			input: dto.PauseSubscriptionRequest{},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetByID", mock.Anything, publicID(1)).Return(active(), nil)
+				repo.On("Pause", mock.Anything, mock.MatchedBy(func(p *entity.SubscriptionPause) bool {
+					return p.PausedFrom.Equal(nextMonth)
+				}), 2).Return(&entity.Subscription{ID: 1, StartDate: startDate, Status: entity.SubscriptionStatusPaused, Version: 3,
+					Pauses: []*entity.SubscriptionPause{{SubscriptionID: 1, PausedFrom: nextMonth}}}, nil)
+			},
+			wantStatus: entity.SubscriptionStatusActive,
+		},
+		{
+			name:  "Pause not started yet",
+			input: dto.PauseSubscriptionRequest{},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				sub := active()
+				sub.Status = entity.SubscriptionStatusPaused
+				sub.Pauses = []*entity.SubscriptionPause{{SubscriptionID: 1, PausedFrom: nextMonth}}
+				repo.On("GetByID", mock.Anything, publicID(1)).Return(sub, nil)
+			},
+			wantErr: custom_err.ErrInvalidStatusTransition,
+		},
+		{
+			name:  "Already paused",
+			input: dto.PauseSubscriptionRequest{},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				sub := active()
+				sub.Status = entity.SubscriptionStatusPaused
+				repo.On("GetByID", mock.Anything, publicID(1)).Return(sub, nil)
+			},
+			wantErr: custom_err.ErrInvalidStatusTransition,
+		},
+		{
+			name:  "Expired",
+			input: dto.PauseSubscriptionRequest{},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				sub := active()
+				ended := time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)
+				sub.EndDate = &ended
+				repo.On("GetByID", mock.Anything, publicID(1)).Return(sub, nil)
+			},
+			wantErr: custom_err.ErrInvalidStatusTransition,
+		},
+		{
+			name:  "After end date",
+			input: dto.PauseSubscriptionRequest{From: from("01-2031")},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				sub := active()
+				sub.EndDate = &endDate
+				repo.On("GetByID", mock.Anything, publicID(1)).Return(sub, nil)
+			},
+			wantErr: custom_err.ErrInvalidRequest,
+		},
+		{
+			name:  "Before start date",
+			input: dto.PauseSubscriptionRequest{From: from("12-2024")},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetByID", mock.Anything, publicID(1)).Return(active(), nil)
+			},
+			wantErr: custom_err.ErrInvalidRequest,
+		},
+		{
+			name:    "Stale version",
+			input:   dto.PauseSubscriptionRequest{},
+			version: version(1),
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetByID", mock.Anything, publicID(1)).Return(active(), nil)
+			},
+			wantErr: custom_err.ErrVersionMismatch,
+		},
+		{
+			name:  "Overlaps earlier pause",
+			input: dto.PauseSubscriptionRequest{From: from("03-2025")},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetByID", mock.Anything, publicID(1)).Return(active(), nil)
+				repo.On("Pause", mock.Anything, mock.Anything, 2).Return(nil, custom_err.ErrInvalidRequest)
+			},
+			wantErr: custom_err.ErrInvalidRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewISubscriptionRepository(t)
+			useCase := newStatusUseCase(t, mockRepo)
+			tt.setupMocks(mockRepo)
+
+			result, err := useCase.Pause(context.Background(), publicID(1), &tt.input, tt.version)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantStatus, result.Status)
+				assert.Equal(t, 3, result.Version)
+			}
+		})
+	}
+}
+
+func Test_ResumeSubscription(t *testing.T) {
+	t.Parallel()
+
+	startDate := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	thisMonth := utils.MonthStart(time.Now())
+
+	t.Run("Resume from current month", func(t *testing.T) {
+		mockRepo := mocks.NewISubscriptionRepository(t)
+		useCase := newStatusUseCase(t, mockRepo)
+		mockRepo.On("GetByID", mock.Anything, publicID(1)).
+			Return(&entity.Subscription{ID: 1, StartDate: startDate, Status: entity.SubscriptionStatusPaused, Version: 3}, nil)
+		mockRepo.On("Resume", mock.Anything, 1, thisMonth, 3).
+			Return(&entity.Subscription{ID: 1, StartDate: startDate, Status: entity.SubscriptionStatusActive, Version: 4}, nil)
+
+		result, err := useCase.Resume(context.Background(), publicID(1), &dto.ResumeSubscriptionRequest{}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.SubscriptionStatusActive, result.Status)
+	})
+
+	t.Run("Resume before the pause starts", func(t *testing.T) {
+		mockRepo := mocks.NewISubscriptionRepository(t)
+		useCase := newStatusUseCase(t, mockRepo)
+		mockRepo.On("GetByID", mock.Anything, publicID(1)).
+			Return(&entity.Subscription{ID: 1, StartDate: startDate, Status: entity.SubscriptionStatusPaused, Version: 3,
+				Pauses: []*entity.SubscriptionPause{{SubscriptionID: 1, PausedFrom: thisMonth.AddDate(0, 1, 0)}}}, nil)
+		mockRepo.On("Resume", mock.Anything, 1, thisMonth, 3).
+			Return(&entity.Subscription{ID: 1, StartDate: startDate, Status: entity.SubscriptionStatusActive, Version: 4}, nil)
+
+		result, err := useCase.Resume(context.Background(), publicID(1), &dto.ResumeSubscriptionRequest{}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.SubscriptionStatusActive, result.Status)
+	})
+
+	t.Run("Not paused", func(t *testing.T) {
+		mockRepo := mocks.NewISubscriptionRepository(t)
+		useCase := newStatusUseCase(t, mockRepo)
+		mockRepo.On("GetByID", mock.Anything, publicID(1)).
+			Return(&entity.Subscription{ID: 1, StartDate: startDate, Status: entity.SubscriptionStatusActive, Version: 3}, nil)
+
+		_, err := useCase.Resume(context.Background(), publicID(1), &dto.ResumeSubscriptionRequest{}, nil)
+		assert.ErrorIs(t, err, custom_err.ErrInvalidStatusTransition)
+	})
+}
+
+func Test_CancelSubscription(t *testing.T) {
+	t.Parallel()
+
+	startDate := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		sub        *entity.Subscription
+		input      dto.CancelSubscriptionRequest
+		wantEnd    time.Time
+		wantErr    error
+		callCancel bool
+	}{
+		{
+			name:       "Cancel paused subscription",
+			sub:        &entity.Subscription{ID: 1, StartDate: startDate, Status: entity.SubscriptionStatusPaused, Version: 2},
+			input:      dto.CancelSubscriptionRequest{EndDate: func() *string { s := "09-2025"; return &s }()},
+			wantEnd:    time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC),
+			callCancel: true,
+		},
+		{
+			name:       "Earlier end date is kept",
+			sub:        &entity.Subscription{ID: 1, StartDate: startDate, EndDate: &endDate, Status: entity.SubscriptionStatusPaused, Version: 2},
+			input:      dto.CancelSubscriptionRequest{EndDate: func() *string { s := "09-2025"; return &s }()},
+			wantEnd:    endDate,
+			callCancel: true,
+		},
+		{
+			name:    "Already cancelled",
+			sub:     &entity.Subscription{ID: 1, StartDate: startDate, Status: entity.SubscriptionStatusCancelled, Version: 2},
+			wantErr: custom_err.ErrInvalidStatusTransition,
+		},
+		{
+			name:    "End before start",
+			sub:     &entity.Subscription{ID: 1, StartDate: startDate, Status: entity.SubscriptionStatusActive, Version: 2},
+			input:   dto.CancelSubscriptionRequest{EndDate: func() *string { s := "12-2024"; return &s }()},
+			wantErr: custom_err.ErrInvalidRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewISubscriptionRepository(t)
+			useCase := newStatusUseCase(t, mockRepo)
+			mockRepo.On("GetByID", mock.Anything, publicID(1)).Return(tt.sub, nil)
+			if tt.callCancel {
+				cancelled := *tt.sub
+				cancelled.Status = entity.SubscriptionStatusCancelled
+				cancelled.EndDate = &tt.wantEnd
+				mockRepo.On("Cancel", mock.Anything, 1, tt.wantEnd, 2).Return(&cancelled, nil)
+			}
+
+			result, err := useCase.Cancel(context.Background(), publicID(1), &tt.input, nil)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, entity.SubscriptionStatusCancelled, result.Status)
+			assert.Equal(t, utils.TimeToMonthYear(tt.wantEnd), *result.EndDate)
+		})
+	}
+}
+
+func Test_GetPauses(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := mocks.NewISubscriptionRepository(t)
+	useCase := newStatusUseCase(t, mockRepo)
+
+	resumed := time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("GetByID", mock.Anything, publicID(1)).Return(&entity.Subscription{ID: 1}, nil)
+	mockRepo.On("GetPauses", mock.Anything, 1).Return([]*entity.SubscriptionPause{
+		{ID: 1, SubscriptionID: 1, PausedFrom: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC), ResumedFrom: &resumed},
+	}, nil)
+
+	result, err := useCase.GetPauses(context.Background(), publicID(1))
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "03-2025", result[0].PausedFrom)
+	assert.Equal(t, "05-2025", *result[0].ResumedFrom)
+}
@@ -2,8 +2,10 @@ package subscription_usecase
 
 import (
 	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
 	custom_err "AggregationService/internal/errors"
 	"AggregationService/internal/pkg/logger"
+	"AggregationService/internal/pkg/utils"
 	"context"
 	"errors"
 	"fmt"
@@ -27,26 +29,42 @@ func (u *subscriptionUseCase) Create(ctx context.Context, req *dto.CreateSubscri
 	entitySub.CreatedAt = time.Now()
 	entitySub.UpdatedAt = entitySub.CreatedAt
 
+	budgets := u.snapshotBudgets(ctx, entitySub.UserID)
+
 	createdSub, err := u.subscriptionRepository.Create(ctx, entitySub)
 	if err != nil {
 		if errors.Is(err, custom_err.ErrSubscriptionAlreadyFound) {
 			log.Error(fmt.Sprintf("duplicate subscription: %v", err))
-			return nil, custom_err.ErrSubscriptionAlreadyFound
+			return nil, overlapError(err)
+		}
+		if errors.Is(err, custom_err.ErrServiceNotFound) {
+			log.Error(fmt.Sprintf("unknown service: %v", err))
+			return nil, custom_err.ErrServiceNotFound
+		}
+		if errors.Is(err, custom_err.ErrCategoryNotFound) {
+			log.Error(fmt.Sprintf("unknown category: %v", err))
+			return nil, custom_err.ErrCategoryNotFound
 		}
 		log.Error(fmt.Sprintf("failed to create subscription: %v", err))
 		return nil, custom_err.ErrInternalServer // <-- вот тут!
 	}
 
+	u.alertBudgets(ctx, budgets)
+
 	log.Debug(fmt.Sprintf("success creating subscription: %+v", createdSub))
 	return u.converter.ToSubscriptionDTO(createdSub), nil
 }
 
-func (u *subscriptionUseCase) Update(ctx context.Context, id int, req *dto.UpdateSubscriptionRequest) (*dto.SubscriptionResponse, error) {
+// Update changes a subscription. version, when set, is the version the caller
+// last saw; the update fails with ErrVersionMismatch if the subscription has
+// changed since. The update also fails when the subscription changes between
+// reading and writing it.
+func (u *subscriptionUseCase) Update(ctx context.Context, id uuid.UUID, req *dto.UpdateSubscriptionRequest, version *int) (*dto.SubscriptionResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
 	log := logger.FromContext(ctx)
-	log.Debug(fmt.Sprintf("trying to update subscription: id=%d", id))
+	log.Debug(fmt.Sprintf("trying to update subscription: id=%s", id))
 
 	if err := u.validator.Validate(req); err != nil {
 		log.Error(fmt.Sprintf("invalid input: %v", custom_err.ErrInvalidRequest))
@@ -62,26 +80,77 @@ func (u *subscriptionUseCase) Update(ctx context.Context, id int, req *dto.Updat
 		log.Error(fmt.Sprintf("failed to get subscription for update: %v", err))
 		return nil, custom_err.ErrInternalServer // исправлено!
 	}
+	if version != nil && *version != sub.Version {
+		log.Error(fmt.Sprintf("stale subscription version: want %d, have %d", *version, sub.Version))
+		return nil, custom_err.ErrVersionMismatch
+	}
+
+	// a new price starts a new price period instead of rewriting the old one
+	var price *entity.SubscriptionPrice
+	if req.Price != nil {
+		priceReq := &dto.CreateSubscriptionPriceRequest{
+			Price:         *req.Price,
+			EffectiveFrom: utils.TimeToMonthYear(latestMonth(time.Now(), sub.StartDate)),
+		}
+		if req.PriceFrom != nil {
+			priceReq.EffectiveFrom = *req.PriceFrom
+		}
+		if price, err = u.newPrice(sub, priceReq); err != nil {
+			log.Error(fmt.Sprintf("invalid price change: %v", err))
+			return nil, err
+		}
+	}
+
+	budgets := u.snapshotBudgets(ctx, sub.UserID)
 
 	u.converter.ApplyUpdateToEntity(sub, req)
 	sub.UpdatedAt = time.Now()
 
-	updatedSub, err := u.subscriptionRepository.Update(ctx, sub)
+	updatedSub, err := u.subscriptionRepository.Update(ctx, sub, price)
 	if err != nil {
+		if errors.Is(err, custom_err.ErrSubscriptionNotFound) {
+			log.Error(fmt.Sprintf("failed to update subscription: %v", err))
+			return nil, custom_err.ErrSubscriptionNotFound
+		}
+		if errors.Is(err, custom_err.ErrVersionMismatch) {
+			log.Error(fmt.Sprintf("concurrent subscription update: %v", err))
+			return nil, custom_err.ErrVersionMismatch
+		}
+		if errors.Is(err, custom_err.ErrSubscriptionAlreadyFound) {
+			log.Error(fmt.Sprintf("duplicate subscription: %v", err))
+			return nil, overlapError(err)
+		}
+		if errors.Is(err, custom_err.ErrServiceNotFound) {
+			log.Error(fmt.Sprintf("unknown service: %v", err))
+			return nil, custom_err.ErrServiceNotFound
+		}
+		if errors.Is(err, custom_err.ErrCategoryNotFound) {
+			log.Error(fmt.Sprintf("unknown category: %v", err))
+			return nil, custom_err.ErrCategoryNotFound
+		}
 		log.Error(fmt.Sprintf("failed to update subscription: %v", err))
 		return nil, custom_err.ErrInternalServer
 	}
 
-	log.Debug(fmt.Sprintf("success update subscription: id=%d", id))
+	if price != nil {
+		if updatedSub, err = u.subscriptionRepository.GetByID(ctx, id); err != nil {
+			log.Error(fmt.Sprintf("failed to get updated subscription: %v", err))
+			return nil, custom_err.ErrInternalServer
+		}
+	}
+
+	u.alertBudgets(ctx, budgets)
+
+	log.Debug(fmt.Sprintf("success update subscription: id=%s", id))
 	return u.converter.ToSubscriptionDTO(updatedSub), nil
 }
 
-func (u *subscriptionUseCase) GetByID(ctx context.Context, id int) (*dto.SubscriptionResponse, error) {
+func (u *subscriptionUseCase) GetByID(ctx context.Context, id uuid.UUID) (*dto.SubscriptionResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
 	log := logger.FromContext(ctx)
-	log.Debug(fmt.Sprintf("trying to get subscription by id: %d", id))
+	log.Debug(fmt.Sprintf("trying to get subscription by id: %s", id))
 
 	sub, err := u.subscriptionRepository.GetByID(ctx, id)
 	if err != nil {
@@ -93,17 +162,44 @@ func (u *subscriptionUseCase) GetByID(ctx context.Context, id int) (*dto.Subscri
 		return nil, custom_err.ErrInternalServer
 	}
 
-	log.Debug(fmt.Sprintf("success get subscription by id: %d", id))
+	log.Debug(fmt.Sprintf("success get subscription by id: %s", id))
 	return u.converter.ToSubscriptionDTO(sub), nil
 }
 
-func (u *subscriptionUseCase) GetAll(ctx context.Context, userID *uuid.UUID, serviceName *string, limit, offset int) ([]*dto.SubscriptionResponse, error) {
+// PublicID resolves the deprecated integer id of a subscription, deleted or
+// not, to the public id the other methods take.
+func (u *subscriptionUseCase) PublicID(ctx context.Context, id int) (uuid.UUID, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+
+	publicID, err := u.subscriptionRepository.GetPublicID(ctx, id)
+	if err != nil {
+		if errors.Is(err, custom_err.ErrSubscriptionNotFound) {
+			log.Error(fmt.Sprintf("failed to resolve subscription id %d: %v", id, err))
+			return uuid.Nil, custom_err.ErrSubscriptionNotFound
+		}
+		log.Error(fmt.Sprintf("failed to resolve subscription id %d: %v", id, err))
+		return uuid.Nil, custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("resolved deprecated subscription id %d to %s", id, publicID))
+	return publicID, nil
+}
+
+func (u *subscriptionUseCase) GetAll(ctx context.Context, req *dto.ListSubscriptionsRequest) ([]*dto.SubscriptionResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
 	log := logger.FromContext(ctx)
 
-	subs, err := u.subscriptionRepository.GetAll(ctx, userID, serviceName, limit, offset)
+	if err := u.validator.Validate(req); err != nil {
+		log.Error(fmt.Sprintf("invalid input: %v", custom_err.ErrInvalidRequest))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	subs, err := u.subscriptionRepository.GetAll(ctx, u.converter.ToSubscriptionFilter(req))
 	if err != nil {
 		if errors.Is(err, custom_err.ErrNoSubscriptionsFound) {
 			log.Error(fmt.Sprintf("no subscriptions found: %v", err))
@@ -118,39 +214,203 @@ func (u *subscriptionUseCase) GetAll(ctx context.Context, userID *uuid.UUID, ser
 	return result, nil
 }
 
-func (u *subscriptionUseCase) Delete(ctx context.Context, id int) error {
+// Delete marks a subscription deleted. version works like in Update.
+func (u *subscriptionUseCase) Delete(ctx context.Context, id uuid.UUID, version *int) error {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
 	log := logger.FromContext(ctx)
-	log.Debug(fmt.Sprintf("trying to delete subscription: id=%d", id))
+	log.Debug(fmt.Sprintf("trying to delete subscription: id=%s", id))
 
-	if err := u.subscriptionRepository.Delete(ctx, id); err != nil {
+	// the subscription is read first for the audit trail
+	sub, err := u.subscriptionRepository.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, custom_err.ErrSubscriptionNotFound) {
+			log.Error(fmt.Sprintf("failed to get subscription for delete: %v", err))
+			return custom_err.ErrSubscriptionNotFound
+		}
+		log.Error(fmt.Sprintf("failed to get subscription for delete: %v", err))
+		return custom_err.ErrInternalServer
+	}
+	if version != nil && *version != sub.Version {
+		log.Error(fmt.Sprintf("stale subscription version: want %d, have %d", *version, sub.Version))
+		return custom_err.ErrVersionMismatch
+	}
+
+	if err = u.subscriptionRepository.Delete(ctx, sub.ID, sub.Version); err != nil {
 		if errors.Is(err, custom_err.ErrSubscriptionNotFound) {
 			log.Error(fmt.Sprintf("failed to delete subscription: %v", err))
 			return custom_err.ErrSubscriptionNotFound
 		}
+		if errors.Is(err, custom_err.ErrVersionMismatch) {
+			log.Error(fmt.Sprintf("concurrent subscription delete: %v", err))
+			return custom_err.ErrVersionMismatch
+		}
 		log.Error(fmt.Sprintf("failed to delete subscription: %v", err))
 		return custom_err.ErrInternalServer
 	}
 
-	log.Debug(fmt.Sprintf("success delete subscription: id=%d", id))
+	log.Debug(fmt.Sprintf("success delete subscription: id=%s", id))
 	return nil
 }
 
-func (u *subscriptionUseCase) CalculateCost(ctx context.Context, userID *uuid.UUID, serviceName *string, startDate, endDate *time.Time) (int, error) {
+// Restore brings back a deleted subscription with its prices and tags.
+func (u *subscriptionUseCase) Restore(ctx context.Context, id uuid.UUID) (*dto.SubscriptionResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to restore subscription: id=%s", id))
+
+	sub, err := u.subscriptionRepository.Restore(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, custom_err.ErrSubscriptionNotFound):
+			log.Error(fmt.Sprintf("failed to restore subscription: %v", err))
+			return nil, custom_err.ErrSubscriptionNotFound
+		case errors.Is(err, custom_err.ErrSubscriptionNotDeleted):
+			log.Error(fmt.Sprintf("failed to restore subscription: %v", err))
+			return nil, custom_err.ErrSubscriptionNotDeleted
+		case errors.Is(err, custom_err.ErrSubscriptionAlreadyFound):
+			log.Error(fmt.Sprintf("restored subscription would overlap: %v", err))
+			return nil, overlapError(err)
+		}
+		log.Error(fmt.Sprintf("failed to restore subscription: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success restore subscription: id=%s", id))
+	return u.converter.ToSubscriptionDTO(sub), nil
+}
+
+func (u *subscriptionUseCase) CalculateCost(ctx context.Context, req *dto.CalculateCostRequest) (*dto.CalculateCostResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
 	log := logger.FromContext(ctx)
-	log.Debug(fmt.Sprintf("trying to calculate cost"))
+	log.Debug(fmt.Sprintf("trying to calculate cost: %+v", req))
+
+	filter, err := u.costFilter(req)
+	if err != nil {
+		log.Error(fmt.Sprintf("invalid cost request: %v", err))
+		return nil, err
+	}
 
-	cost, err := u.subscriptionRepository.CalculateCost(ctx, userID, serviceName, startDate, endDate)
+	report, err := u.subscriptionRepository.CalculateCost(ctx, filter)
 	if err != nil {
+		if errors.Is(err, custom_err.ErrExchangeRateNotFound) {
+			log.Error(fmt.Sprintf("failed to convert cost: %v", err))
+			return nil, custom_err.ErrExchangeRateNotFound
+		}
 		log.Error(fmt.Sprintf("failed to calculate cost: %v", err))
-		return 0, custom_err.ErrInternalServer
+		return nil, custom_err.ErrInternalServer
+	}
+
+	result := u.converter.ToCalculateCostResponse(report, filter)
+	log.Debug(fmt.Sprintf("success calculating cost: %s %s", result.TotalCost, result.Currency))
+	return result, nil
+}
+
+func (u *subscriptionUseCase) CostTimeSeries(ctx context.Context, req *dto.CalculateCostRequest) (*dto.CostTimeSeriesResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to build cost time series: %+v", req))
+
+	filter, err := u.costFilter(req)
+	if err != nil {
+		log.Error(fmt.Sprintf("invalid cost request: %v", err))
+		return nil, err
+	}
+
+	buckets, err := u.subscriptionRepository.CostTimeSeries(ctx, filter)
+	if err != nil {
+		if errors.Is(err, custom_err.ErrExchangeRateNotFound) {
+			log.Error(fmt.Sprintf("failed to convert cost time series: %v", err))
+			return nil, custom_err.ErrExchangeRateNotFound
+		}
+		log.Error(fmt.Sprintf("failed to build cost time series: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	result := u.converter.ToCostTimeSeriesResponse(buckets, filter)
+	log.Debug(fmt.Sprintf("success building cost time series: %d buckets", len(result.Buckets)))
+	return result, nil
+}
+
+// Forecast projects the monthly spend of the currently active subscriptions
+// with the same per-month cost query as CalculateCost.
+func (u *subscriptionUseCase) Forecast(ctx context.Context, req *dto.ForecastRequest) (*dto.ForecastResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to forecast cost: %+v", req))
+
+	if err := u.validator.Validate(req); err != nil {
+		log.Error(fmt.Sprintf("invalid input: %v", custom_err.ErrInvalidRequest))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	filter := u.converter.ToForecastFilter(req, time.Now())
+	report, err := u.subscriptionRepository.CalculateCost(ctx, filter)
+	if err != nil {
+		if errors.Is(err, custom_err.ErrExchangeRateNotFound) {
+			log.Error(fmt.Sprintf("failed to convert forecast: %v", err))
+			return nil, custom_err.ErrExchangeRateNotFound
+		}
+		log.Error(fmt.Sprintf("failed to forecast cost: %v", err))
+		return nil, custom_err.ErrInternalServer
 	}
 
-	log.Debug(fmt.Sprintf("success calculating cost: %d", cost))
-	return cost, nil
+	result := u.converter.ToForecastResponse(report.Groups, filter)
+	log.Debug(fmt.Sprintf("success forecasting cost: %s %s", result.TotalCost, result.Currency))
+	return result, nil
+}
+
+// snapshotBudgets records the budget spend of a user before a change to the
+// user's subscriptions. Budget failures never fail the change itself.
+func (u *subscriptionUseCase) snapshotBudgets(ctx context.Context, userID uuid.UUID) *entity.BudgetSnapshot {
+	if u.budgets == nil {
+		return nil
+	}
+	snapshot, err := u.budgets.Snapshot(ctx, userID)
+	if err != nil {
+		logger.FromContext(ctx).Error(fmt.Sprintf("failed to snapshot budgets: %v", err))
+		return nil
+	}
+	return snapshot
+}
+
+// alertBudgets alerts on the budgets pushed over their threshold since before.
+func (u *subscriptionUseCase) alertBudgets(ctx context.Context, before *entity.BudgetSnapshot) {
+	if before == nil {
+		return
+	}
+	if err := u.budgets.AlertCrossed(ctx, before); err != nil {
+		logger.FromContext(ctx).Error(fmt.Sprintf("failed to check budget alerts: %v", err))
+	}
+}
+
+// overlapError keeps the conflicting subscription of an overlap error so the
+// caller can point at it.
+func overlapError(err error) error {
+	var conflict *custom_err.SubscriptionConflictError
+	if errors.As(err, &conflict) {
+		return conflict
+	}
+	return custom_err.ErrSubscriptionAlreadyFound
+}
+
+// costFilter validates a cost request and converts it into a repository filter.
+func (u *subscriptionUseCase) costFilter(req *dto.CalculateCostRequest) (*entity.CostFilter, error) {
+	if err := u.validator.Validate(req); err != nil {
+		return nil, custom_err.ErrInvalidRequest
+	}
+	filter := u.converter.ToCostFilter(req)
+	if filter.EndDate.Before(filter.StartDate) {
+		return nil, custom_err.ErrInvalidRequest
+	}
+	return filter, nil
 }
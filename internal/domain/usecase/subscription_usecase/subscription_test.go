@@ -3,6 +3,7 @@ package subscription_usecase
 import (
 	"AggregationService/internal/converters"
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,15 +15,26 @@ import (
 	"AggregationService/internal/domain/models/entity"
 	"AggregationService/internal/domain/ports/repository/mocks"
 	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/money"
+	"AggregationService/internal/pkg/utils"
 	"AggregationService/internal/pkg/validation"
 	"errors"
+	"fmt"
 )
 
+// publicID returns the public id the tests give the subscription with the
+// internal id.
+func publicID(id int) uuid.UUID {
+	return uuid.MustParse(fmt.Sprintf("01932c07-a6b2-7a3e-9f1c-%012d", id))
+}
+
 func Test_CreateSubscription(t *testing.T) {
 	t.Parallel()
 
 	validUUID := uuid.New()
 	endDate := "12-2025"
+	serviceID := 7
+	unknownServiceID := 999
 
 	tests := []struct {
 		name       string
@@ -40,11 +52,72 @@ func Test_CreateSubscription(t *testing.T) {
 				EndDate:     &endDate,
 			},
 			setupMocks: func(repo *mocks.ISubscriptionRepository) {
-				repo.On("Create", mock.Anything, mock.Anything).
-					Return(&entity.Subscription{ID: 1}, nil)
+				repo.On("Create", mock.Anything, mock.MatchedBy(func(sub *entity.Subscription) bool {
+					return sub.Category == entity.DefaultCategory && len(sub.Tags) == 0
+				})).Return(&entity.Subscription{ID: 1}, nil)
 			},
 			wantErr: nil,
 		},
+		{
+			name: "Category and tags",
+			input: dto.CreateSubscriptionRequest{
+				UserID:      validUUID,
+				ServiceName: "yandex",
+				Price:       299,
+				StartDate:   "09-2025",
+				Category:    " Streaming",
+				Tags:        []string{"Family ", "work", "family"},
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("Create", mock.Anything, mock.MatchedBy(func(sub *entity.Subscription) bool {
+					return sub.Category == "streaming" && assert.ObjectsAreEqual([]string{"family", "work"}, sub.Tags)
+				})).Return(&entity.Subscription{ID: 1, Category: "streaming", Tags: []string{"family", "work"}}, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Metadata",
+			input: dto.CreateSubscriptionRequest{
+				UserID:      validUUID,
+				ServiceName: "yandex",
+				Price:       299,
+				StartDate:   "09-2025",
+				Metadata:    map[string]string{"account_email": "family@example.com", "cost_center": "R&D"},
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("Create", mock.Anything, mock.MatchedBy(func(sub *entity.Subscription) bool {
+					return sub.Metadata["cost_center"] == "R&D" && len(sub.Metadata) == 2
+				})).Return(&entity.Subscription{ID: 1, Metadata: entity.Metadata{"cost_center": "R&D"}}, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Metadata value too long",
+			input: dto.CreateSubscriptionRequest{
+				UserID:      validUUID,
+				ServiceName: "yandex",
+				Price:       299,
+				StartDate:   "09-2025",
+				Metadata:    map[string]string{"contract": strings.Repeat("x", 513)},
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name: "Unknown category",
+			input: dto.CreateSubscriptionRequest{
+				UserID:      validUUID,
+				ServiceName: "yandex",
+				Price:       299,
+				StartDate:   "09-2025",
+				Category:    "hobbies",
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("Create", mock.Anything, mock.Anything).
+					Return(nil, custom_err.ErrCategoryNotFound)
+			},
+			wantErr: custom_err.ErrCategoryNotFound,
+		},
 		{
 			name: "Invalid price",
 			input: dto.CreateSubscriptionRequest{
@@ -69,6 +142,75 @@ func Test_CreateSubscription(t *testing.T) {
 			setupMocks: func(repo *mocks.ISubscriptionRepository) {},
 			wantErr:    custom_err.ErrInvalidRequest,
 		},
+		{
+			name: "By service id",
+			input: dto.CreateSubscriptionRequest{
+				UserID:    validUUID,
+				ServiceID: &serviceID,
+				Price:     299,
+				StartDate: "09-2025",
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("Create", mock.Anything, mock.MatchedBy(func(sub *entity.Subscription) bool {
+					return sub.ServiceID == serviceID && sub.ServiceName == ""
+				})).Return(&entity.Subscription{ID: 1, ServiceID: serviceID, ServiceName: "Yandex Plus"}, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Unknown service id",
+			input: dto.CreateSubscriptionRequest{
+				UserID:    validUUID,
+				ServiceID: &unknownServiceID,
+				Price:     299,
+				StartDate: "09-2025",
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("Create", mock.Anything, mock.Anything).
+					Return(nil, custom_err.ErrServiceNotFound)
+			},
+			wantErr: custom_err.ErrServiceNotFound,
+		},
+		{
+			name: "Annual plan",
+			input: dto.CreateSubscriptionRequest{
+				UserID:        validUUID,
+				ServiceName:   "yandex",
+				Price:         2990,
+				BillingPeriod: "year",
+				StartDate:     "09-2025",
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("Create", mock.Anything, mock.MatchedBy(func(sub *entity.Subscription) bool {
+					return sub.BillingPeriod == "year" && sub.BillingMonths == 12
+				})).Return(&entity.Subscription{ID: 1, BillingPeriod: "year", BillingMonths: 12}, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Custom period without months",
+			input: dto.CreateSubscriptionRequest{
+				UserID:        validUUID,
+				ServiceName:   "yandex",
+				Price:         299,
+				BillingPeriod: "custom",
+				StartDate:     "09-2025",
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name: "Unknown billing period",
+			input: dto.CreateSubscriptionRequest{
+				UserID:        validUUID,
+				ServiceName:   "yandex",
+				Price:         299,
+				BillingPeriod: "daily",
+				StartDate:     "09-2025",
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
 		{
 			name: "Duplicate subscription",
 			input: dto.CreateSubscriptionRequest{
@@ -118,7 +260,7 @@ func Test_CreateSubscription(t *testing.T) {
 			mockRepo := mocks.NewISubscriptionRepository(t)
 			validator, _ := validation.New()
 			converter := converters.New()
-			useCase := New(mockRepo, validator, converter)
+			useCase := New(mockRepo, mocks.NewIServiceRepository(t), validator, converter, nil)
 
 			tt.setupMocks(mockRepo)
 
@@ -134,37 +276,60 @@ func Test_CreateSubscription(t *testing.T) {
 	}
 }
 
+func Test_CreateSubscription_Overlap(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := mocks.NewISubscriptionRepository(t)
+	validator, _ := validation.New()
+	useCase := New(mockRepo, mocks.NewIServiceRepository(t), validator, converters.New(), nil)
+
+	mockRepo.On("Create", mock.Anything, mock.Anything).
+		Return(nil, &custom_err.SubscriptionConflictError{ConflictingID: 42})
+
+	_, err := useCase.Create(context.Background(), &dto.CreateSubscriptionRequest{
+		UserID:      uuid.New(),
+		ServiceName: "yandex",
+		Price:       299,
+		StartDate:   "09-2025",
+	})
+	assert.ErrorIs(t, err, custom_err.ErrSubscriptionAlreadyFound)
+	var conflict *custom_err.SubscriptionConflictError
+	if assert.ErrorAs(t, err, &conflict) {
+		assert.Equal(t, 42, conflict.ConflictingID)
+	}
+}
+
 func Test_GetByID(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
 		name       string
-		id         int
+		id         uuid.UUID
 		setupMocks func(repo *mocks.ISubscriptionRepository)
 		wantErr    error
 	}{
 		{
 			name: "Found",
-			id:   1,
+			id:   publicID(1),
 			setupMocks: func(repo *mocks.ISubscriptionRepository) {
-				repo.On("GetByID", mock.Anything, 1).
+				repo.On("GetByID", mock.Anything, publicID(1)).
 					Return(&entity.Subscription{ID: 1, ServiceName: "yandex"}, nil)
 			},
 			wantErr: nil,
 		},
 		{
 			name: "Not found",
-			id:   999,
+			id:   publicID(999),
 			setupMocks: func(repo *mocks.ISubscriptionRepository) {
-				repo.On("GetByID", mock.Anything, 999).
+				repo.On("GetByID", mock.Anything, publicID(999)).
 					Return(nil, custom_err.ErrSubscriptionNotFound)
 			},
 			wantErr: custom_err.ErrSubscriptionNotFound,
 		},
 		{
 			name: "Repository error",
-			id:   2,
+			id:   publicID(2),
 			setupMocks: func(repo *mocks.ISubscriptionRepository) {
-				repo.On("GetByID", mock.Anything, 2).
+				repo.On("GetByID", mock.Anything, publicID(2)).
 					Return(nil, custom_err.ErrInternalServer)
 			},
 			wantErr: custom_err.ErrInternalServer,
@@ -176,7 +341,7 @@ func Test_GetByID(t *testing.T) {
 			mockRepo := mocks.NewISubscriptionRepository(t)
 			validator, _ := validation.New()
 			converter := converters.New()
-			useCase := New(mockRepo, validator, converter)
+			useCase := New(mockRepo, mocks.NewIServiceRepository(t), validator, converter, nil)
 
 			tt.setupMocks(mockRepo)
 
@@ -192,63 +357,227 @@ func Test_GetByID(t *testing.T) {
 	}
 }
 
+func Test_PublicID(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		id      int
+		repoID  uuid.UUID
+		repoErr error
+		wantErr error
+	}{
+		{name: "Resolved", id: 1, repoID: publicID(1)},
+		{name: "Not found", id: 999, repoErr: custom_err.ErrSubscriptionNotFound, wantErr: custom_err.ErrSubscriptionNotFound},
+		{name: "Repository error", id: 2, repoErr: errors.New("db down"), wantErr: custom_err.ErrInternalServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewISubscriptionRepository(t)
+			validator, _ := validation.New()
+			useCase := New(mockRepo, mocks.NewIServiceRepository(t), validator, converters.New(), nil)
+			mockRepo.On("GetPublicID", mock.Anything, tt.id).Return(tt.repoID, tt.repoErr)
+
+			id, err := useCase.PublicID(context.Background(), tt.id)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, uuid.Nil, id)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.repoID, id)
+		})
+	}
+}
+
 func Test_UpdateSubscription(t *testing.T) {
 	t.Parallel()
 	serviceName := "yandex plus"
-	price := 399
+	price := money.FromMajor(399)
 	endDate := "12-2025"
+	priceFrom := "11-2025"
+	beforeStart := "08-2025"
+	startDate := time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)
+	category := "Music"
+	tags := []string{" FUN "}
+	metadata := map[string]string{"plan": "family"}
+	tooManyKeys := make(map[string]string)
+	for i := 0; i < 51; i++ {
+		tooManyKeys[fmt.Sprintf("key%d", i)] = "value"
+	}
+	staleVersion := 1
 
 	tests := []struct {
 		name       string
-		id         int
+		id         uuid.UUID
 		input      dto.UpdateSubscriptionRequest
+		version    *int
 		setupMocks func(repo *mocks.ISubscriptionRepository)
 		wantErr    error
 	}{
 		{
 			name: "Valid update",
-			id:   1,
+			id:   publicID(1),
 			input: dto.UpdateSubscriptionRequest{
 				ServiceName: &serviceName,
 				Price:       &price,
 				EndDate:     &endDate,
 			},
 			setupMocks: func(repo *mocks.ISubscriptionRepository) {
-				repo.On("GetByID", mock.Anything, 1).
+				repo.On("GetByID", mock.Anything, publicID(1)).
+					Return(&entity.Subscription{ID: 1, ServiceName: "yandex"}, nil)
+				repo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(p *entity.SubscriptionPrice) bool {
+					return p.SubscriptionID == 1 && p.Price == price && p.EffectiveFrom.Equal(utils.MonthStart(time.Now()))
+				})).Return(&entity.Subscription{ID: 1, ServiceName: "yandex plus"}, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Replace tags and category",
+			id:   publicID(1),
+			input: dto.UpdateSubscriptionRequest{
+				Category: &category,
+				Tags:     &tags,
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetByID", mock.Anything, publicID(1)).
+					Return(&entity.Subscription{ID: 1, ServiceName: "yandex", Category: "other", Tags: []string{"old"}}, nil)
+				repo.On("Update", mock.Anything, mock.MatchedBy(func(sub *entity.Subscription) bool {
+					return sub.Category == "music" && assert.ObjectsAreEqual([]string{"fun"}, sub.Tags)
+				}), (*entity.SubscriptionPrice)(nil)).Return(&entity.Subscription{ID: 1, ServiceName: "yandex", Category: "music", Tags: []string{"fun"}}, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "Replace metadata",
+			id:    publicID(1),
+			input: dto.UpdateSubscriptionRequest{Metadata: &metadata},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetByID", mock.Anything, publicID(1)).
+					Return(&entity.Subscription{ID: 1, ServiceName: "yandex", Metadata: entity.Metadata{"plan": "solo", "team": "core"}}, nil)
+				repo.On("Update", mock.Anything, mock.MatchedBy(func(sub *entity.Subscription) bool {
+					return assert.ObjectsAreEqual(entity.Metadata{"plan": "family"}, sub.Metadata)
+				}), (*entity.SubscriptionPrice)(nil)).Return(&entity.Subscription{ID: 1, ServiceName: "yandex", Metadata: entity.Metadata{"plan": "family"}}, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name:       "Too many metadata keys",
+			id:         publicID(1),
+			input:      dto.UpdateSubscriptionRequest{Metadata: &tooManyKeys},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name: "Keep tags",
+			id:   publicID(1),
+			input: dto.UpdateSubscriptionRequest{
+				EndDate: &endDate,
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetByID", mock.Anything, publicID(1)).
+					Return(&entity.Subscription{ID: 1, ServiceName: "yandex", Category: "other", Tags: []string{"old"}}, nil)
+				repo.On("Update", mock.Anything, mock.MatchedBy(func(sub *entity.Subscription) bool {
+					return sub.Category == "other" && assert.ObjectsAreEqual([]string{"old"}, sub.Tags)
+				}), (*entity.SubscriptionPrice)(nil)).Return(&entity.Subscription{ID: 1, ServiceName: "yandex", Category: "other", Tags: []string{"old"}}, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Price change from a given month",
+			id:   publicID(1),
+			input: dto.UpdateSubscriptionRequest{
+				Price:     &price,
+				PriceFrom: &priceFrom,
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetByID", mock.Anything, publicID(1)).
+					Return(&entity.Subscription{ID: 1, ServiceName: "yandex", StartDate: startDate}, nil)
+				repo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(p *entity.SubscriptionPrice) bool {
+					return p.EffectiveFrom.Equal(time.Date(2025, time.November, 1, 0, 0, 0, 0, time.UTC))
+				})).Return(&entity.Subscription{ID: 1, ServiceName: "yandex", StartDate: startDate}, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Price change before start",
+			id:   publicID(1),
+			input: dto.UpdateSubscriptionRequest{
+				Price:     &price,
+				PriceFrom: &beforeStart,
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetByID", mock.Anything, publicID(1)).
+					Return(&entity.Subscription{ID: 1, ServiceName: "yandex", StartDate: startDate}, nil)
+			},
+			wantErr: custom_err.ErrInvalidRequest,
+		},
+		{
+			name: "Update without price keeps history",
+			id:   publicID(1),
+			input: dto.UpdateSubscriptionRequest{
+				ServiceName: &serviceName,
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetByID", mock.Anything, publicID(1)).
 					Return(&entity.Subscription{ID: 1, ServiceName: "yandex"}, nil)
-				repo.On("Update", mock.Anything, mock.Anything).
+				repo.On("Update", mock.Anything, mock.Anything, (*entity.SubscriptionPrice)(nil)).
 					Return(&entity.Subscription{ID: 1, ServiceName: "yandex plus"}, nil)
 			},
 			wantErr: nil,
 		},
 		{
 			name: "Not found",
-			id:   999,
+			id:   publicID(999),
 			input: dto.UpdateSubscriptionRequest{
 				ServiceName: &serviceName,
 				Price:       &price,
 			},
 			setupMocks: func(repo *mocks.ISubscriptionRepository) {
-				repo.On("GetByID", mock.Anything, 999).
+				repo.On("GetByID", mock.Anything, publicID(999)).
 					Return(nil, custom_err.ErrSubscriptionNotFound)
 			},
 			wantErr: custom_err.ErrSubscriptionNotFound,
 		},
 		{
 			name: "Repository error",
-			id:   1,
+			id:   publicID(1),
 			input: dto.UpdateSubscriptionRequest{
 				ServiceName: &serviceName,
 				Price:       &price,
 			},
 			setupMocks: func(repo *mocks.ISubscriptionRepository) {
-				repo.On("GetByID", mock.Anything, 1).
+				repo.On("GetByID", mock.Anything, publicID(1)).
 					Return(&entity.Subscription{ID: 1, ServiceName: "yandex"}, nil)
-				repo.On("Update", mock.Anything, mock.Anything).
+				repo.On("Update", mock.Anything, mock.Anything, mock.Anything).
 					Return(nil, custom_err.ErrInternalServer)
 			},
 			wantErr: custom_err.ErrInternalServer,
 		},
+		{
+			name:    "Stale If-Match version",
+			id:      publicID(1),
+			input:   dto.UpdateSubscriptionRequest{ServiceName: &serviceName},
+			version: &staleVersion,
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetByID", mock.Anything, publicID(1)).
+					Return(&entity.Subscription{ID: 1, ServiceName: "yandex", Version: 2}, nil)
+			},
+			wantErr: custom_err.ErrVersionMismatch,
+		},
+		{
+			name:  "Changed between read and write",
+			id:    publicID(1),
+			input: dto.UpdateSubscriptionRequest{ServiceName: &serviceName},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetByID", mock.Anything, publicID(1)).
+					Return(&entity.Subscription{ID: 1, ServiceName: "yandex", Version: 2}, nil)
+				repo.On("Update", mock.Anything, mock.MatchedBy(func(sub *entity.Subscription) bool {
+					return sub.Version == 2
+				}), (*entity.SubscriptionPrice)(nil)).Return(nil, fmt.Errorf("update: %w", custom_err.ErrVersionMismatch))
+			},
+			wantErr: custom_err.ErrVersionMismatch,
+		},
 	}
 
 	for _, tt := range tests {
@@ -256,12 +585,12 @@ func Test_UpdateSubscription(t *testing.T) {
 			mockRepo := mocks.NewISubscriptionRepository(t)
 			validator, _ := validation.New()
 			converter := converters.New()
-			useCase := New(mockRepo, validator, converter)
+			useCase := New(mockRepo, mocks.NewIServiceRepository(t), validator, converter, nil)
 
 			tt.setupMocks(mockRepo)
 
 			ctx := context.Background()
-			_, err := useCase.Update(ctx, tt.id, &tt.input)
+			_, err := useCase.Update(ctx, tt.id, &tt.input, tt.version)
 			if tt.wantErr != nil {
 				assert.Error(t, err)
 				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
@@ -274,35 +603,74 @@ func Test_UpdateSubscription(t *testing.T) {
 
 func Test_DeleteSubscription(t *testing.T) {
 	t.Parallel()
+	version := 3
 	tests := []struct {
 		name       string
-		id         int
+		id         uuid.UUID
+		version    *int
 		setupMocks func(repo *mocks.ISubscriptionRepository)
 		wantErr    error
 	}{
+		{
+			name:    "Matching If-Match version",
+			id:      publicID(4),
+			version: &version,
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetByID", mock.Anything, publicID(4)).
+					Return(&entity.Subscription{ID: 4, ServiceName: "yandex", Version: 3}, nil)
+				repo.On("Delete", mock.Anything, 4, 3).
+					Return(nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "Stale If-Match version",
+			id:      publicID(5),
+			version: &version,
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetByID", mock.Anything, publicID(5)).
+					Return(&entity.Subscription{ID: 5, ServiceName: "yandex", Version: 4}, nil)
+			},
+			wantErr: custom_err.ErrVersionMismatch,
+		},
 		{
 			name: "Valid delete",
-			id:   1,
+			id:   publicID(1),
 			setupMocks: func(repo *mocks.ISubscriptionRepository) {
-				repo.On("Delete", mock.Anything, 1).
+				repo.On("GetByID", mock.Anything, publicID(1)).
+					Return(&entity.Subscription{ID: 1, ServiceName: "yandex"}, nil)
+				repo.On("Delete", mock.Anything, 1, 0).
 					Return(nil)
 			},
 			wantErr: nil,
 		},
 		{
 			name: "Not found",
-			id:   999,
+			id:   publicID(999),
 			setupMocks: func(repo *mocks.ISubscriptionRepository) {
-				repo.On("Delete", mock.Anything, 999).
+				repo.On("GetByID", mock.Anything, publicID(999)).
+					Return(nil, custom_err.ErrSubscriptionNotFound)
+			},
+			wantErr: custom_err.ErrSubscriptionNotFound,
+		},
+		{
+			name: "Deleted concurrently",
+			id:   publicID(3),
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetByID", mock.Anything, publicID(3)).
+					Return(&entity.Subscription{ID: 3, ServiceName: "yandex"}, nil)
+				repo.On("Delete", mock.Anything, 3, 0).
 					Return(custom_err.ErrSubscriptionNotFound)
 			},
 			wantErr: custom_err.ErrSubscriptionNotFound,
 		},
 		{
 			name: "Repository error",
-			id:   2,
+			id:   publicID(2),
 			setupMocks: func(repo *mocks.ISubscriptionRepository) {
-				repo.On("Delete", mock.Anything, 2).
+				repo.On("GetByID", mock.Anything, publicID(2)).
+					Return(&entity.Subscription{ID: 2, ServiceName: "yandex"}, nil)
+				repo.On("Delete", mock.Anything, 2, 0).
 					Return(custom_err.ErrInternalServer)
 			},
 			wantErr: custom_err.ErrInternalServer,
@@ -314,12 +682,79 @@ func Test_DeleteSubscription(t *testing.T) {
 			mockRepo := mocks.NewISubscriptionRepository(t)
 			validator, _ := validation.New()
 			converter := converters.New()
-			useCase := New(mockRepo, validator, converter)
+			useCase := New(mockRepo, mocks.NewIServiceRepository(t), validator, converter, nil)
+
+			tt.setupMocks(mockRepo)
+
+			ctx := context.Background()
+			err := useCase.Delete(ctx, tt.id, tt.version)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_RestoreSubscription(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		id         uuid.UUID
+		setupMocks func(repo *mocks.ISubscriptionRepository)
+		wantErr    error
+	}{
+		{
+			name: "Restored",
+			id:   publicID(1),
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("Restore", mock.Anything, publicID(1)).
+					Return(&entity.Subscription{ID: 1, ServiceName: "yandex"}, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Not found",
+			id:   publicID(999),
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("Restore", mock.Anything, publicID(999)).
+					Return(nil, custom_err.ErrSubscriptionNotFound)
+			},
+			wantErr: custom_err.ErrSubscriptionNotFound,
+		},
+		{
+			name: "Not deleted",
+			id:   publicID(3),
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("Restore", mock.Anything, publicID(3)).
+					Return(nil, custom_err.ErrSubscriptionNotDeleted)
+			},
+			wantErr: custom_err.ErrSubscriptionNotDeleted,
+		},
+		{
+			name: "Repository error",
+			id:   publicID(2),
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("Restore", mock.Anything, publicID(2)).
+					Return(nil, errors.New("connection refused"))
+			},
+			wantErr: custom_err.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewISubscriptionRepository(t)
+			validator, _ := validation.New()
+			converter := converters.New()
+			useCase := New(mockRepo, mocks.NewIServiceRepository(t), validator, converter, nil)
 
 			tt.setupMocks(mockRepo)
 
 			ctx := context.Background()
-			err := useCase.Delete(ctx, tt.id)
+			_, err := useCase.Restore(ctx, tt.id)
 			if tt.wantErr != nil {
 				assert.Error(t, err)
 				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
@@ -334,23 +769,18 @@ func Test_GetAllSubscriptions(t *testing.T) {
 	t.Parallel()
 	validUUID := uuid.New()
 	serviceName := "yandex"
+	serviceID := 7
 	tests := []struct {
 		name       string
-		userID     *uuid.UUID
-		service    *string
-		limit      int
-		offset     int
+		req        dto.ListSubscriptionsRequest
 		setupMocks func(repo *mocks.ISubscriptionRepository)
 		wantErr    error
 	}{
 		{
-			name:    "Found",
-			userID:  &validUUID,
-			service: &serviceName,
-			limit:   10,
-			offset:  0,
+			name: "Found",
+			req:  dto.ListSubscriptionsRequest{UserID: &validUUID, ServiceName: &serviceName, Limit: 10},
 			setupMocks: func(repo *mocks.ISubscriptionRepository) {
-				repo.On("GetAll", mock.Anything, &validUUID, &serviceName, 10, 0).
+				repo.On("GetAll", mock.Anything, &entity.SubscriptionFilter{UserID: &validUUID, ServiceName: &serviceName, Limit: 10}).
 					Return([]*entity.Subscription{
 						{ID: 1, ServiceName: "yandex"},
 						{ID: 2, ServiceName: "yandex plus"},
@@ -359,25 +789,43 @@ func Test_GetAllSubscriptions(t *testing.T) {
 			wantErr: nil,
 		},
 		{
-			name:    "Empty result",
-			userID:  &validUUID,
-			service: &serviceName,
-			limit:   10,
-			offset:  0,
+			name: "By service id",
+			req:  dto.ListSubscriptionsRequest{ServiceID: &serviceID, Limit: 10},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetAll", mock.Anything, &entity.SubscriptionFilter{ServiceID: &serviceID, Limit: 10}).
+					Return([]*entity.Subscription{{ID: 1, ServiceID: serviceID, ServiceName: "Yandex Plus"}}, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "By metadata",
+			req:  dto.ListSubscriptionsRequest{Metadata: map[string]string{"plan": "family"}, Limit: 10},
 			setupMocks: func(repo *mocks.ISubscriptionRepository) {
-				repo.On("GetAll", mock.Anything, &validUUID, &serviceName, 10, 0).
+				repo.On("GetAll", mock.Anything, &entity.SubscriptionFilter{Metadata: map[string]string{"plan": "family"}, Limit: 10}).
+					Return([]*entity.Subscription{{ID: 1, Metadata: entity.Metadata{"plan": "family"}}}, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name:       "Empty metadata key",
+			req:        dto.ListSubscriptionsRequest{Metadata: map[string]string{"": "family"}, Limit: 10},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name: "Empty result",
+			req:  dto.ListSubscriptionsRequest{UserID: &validUUID, ServiceName: &serviceName, Limit: 10},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("GetAll", mock.Anything, mock.Anything).
 					Return([]*entity.Subscription{}, nil)
 			},
 			wantErr: nil,
 		},
 		{
-			name:    "Repository error",
-			userID:  &validUUID,
-			service: &serviceName,
-			limit:   10,
-			offset:  0,
+			name: "Repository error",
+			req:  dto.ListSubscriptionsRequest{UserID: &validUUID, ServiceName: &serviceName, Limit: 10},
 			setupMocks: func(repo *mocks.ISubscriptionRepository) {
-				repo.On("GetAll", mock.Anything, &validUUID, &serviceName, 10, 0).
+				repo.On("GetAll", mock.Anything, mock.Anything).
 					Return(nil, custom_err.ErrInternalServer)
 			},
 			wantErr: custom_err.ErrInternalServer,
@@ -389,12 +837,12 @@ func Test_GetAllSubscriptions(t *testing.T) {
 			mockRepo := mocks.NewISubscriptionRepository(t)
 			validator, _ := validation.New()
 			converter := converters.New()
-			useCase := New(mockRepo, validator, converter)
+			useCase := New(mockRepo, mocks.NewIServiceRepository(t), validator, converter, nil)
 
 			tt.setupMocks(mockRepo)
 
 			ctx := context.Background()
-			_, err := useCase.GetAll(ctx, tt.userID, tt.service, tt.limit, tt.offset)
+			_, err := useCase.GetAll(ctx, &tt.req)
 			if tt.wantErr != nil {
 				assert.Error(t, err)
 				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
@@ -407,46 +855,205 @@ func Test_GetAllSubscriptions(t *testing.T) {
 
 func Test_CalculateCost(t *testing.T) {
 	t.Parallel()
+
 	validUUID := uuid.New()
 	serviceName := "yandex"
-	startDate := time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)
-	endDate := time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)
+	filter := &entity.CostFilter{
+		UserID:      &validUUID,
+		ServiceName: &serviceName,
+		StartDate:   time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC),
+		Currency:    "RUB",
+		Mode:        "charged",
+	}
+	groupedFilter := *filter
+	groupedFilter.GroupBy = []string{"service_name"}
+	usdFilter := *filter
+	usdFilter.Currency = "USD"
+	amortizedFilter := *filter
+	amortizedFilter.Mode = "amortized"
 
 	tests := []struct {
 		name       string
-		userID     *uuid.UUID
-		service    *string
-		startDate  *time.Time
-		endDate    *time.Time
+		input      dto.CalculateCostRequest
 		setupMocks func(repo *mocks.ISubscriptionRepository)
-		wantCost   int
+		wantCost   money.Amount
+		wantMonths []int
+		wantGroups int
 		wantErr    error
 	}{
 		{
-			name:      "Valid cost",
-			userID:    &validUUID,
-			service:   &serviceName,
-			startDate: &startDate,
-			endDate:   &endDate,
+			name: "Valid cost",
+			input: dto.CalculateCostRequest{
+				UserID:      &validUUID,
+				ServiceName: &serviceName,
+				StartDate:   "09-2025",
+				EndDate:     "12-2025",
+			},
 			setupMocks: func(repo *mocks.ISubscriptionRepository) {
-				repo.On("CalculateCost", mock.Anything, &validUUID, &serviceName, &startDate, &endDate).
-					Return(451, nil)
+				repo.On("CalculateCost", mock.Anything, filter).
+					Return(&entity.CostReport{Subscriptions: []*entity.SubscriptionCost{
+						{SubscriptionID: 1, ServiceName: "yandex", Price: 400, Months: 4, Cost: 1600},
+						{SubscriptionID: 2, ServiceName: "yandex plus", Price: 299, Months: 2, Cost: 598},
+					}}, nil)
 			},
-			wantCost: 451,
-			wantErr:  nil,
+			wantCost:   2198,
+			wantMonths: []int{4, 2},
+			wantErr:    nil,
 		},
 		{
-			name:      "Repository error",
-			userID:    &validUUID,
-			service:   &serviceName,
-			startDate: &startDate,
-			endDate:   &endDate,
+			name: "No subscriptions in period",
+			input: dto.CalculateCostRequest{
+				UserID:      &validUUID,
+				ServiceName: &serviceName,
+				StartDate:   "09-2025",
+				EndDate:     "12-2025",
+			},
 			setupMocks: func(repo *mocks.ISubscriptionRepository) {
-				repo.On("CalculateCost", mock.Anything, &validUUID, &serviceName, &startDate, &endDate).
-					Return(0, custom_err.ErrInternalServer)
+				repo.On("CalculateCost", mock.Anything, filter).
+					Return(&entity.CostReport{Subscriptions: []*entity.SubscriptionCost{}}, nil)
 			},
-			wantCost: 0,
-			wantErr:  custom_err.ErrInternalServer,
+			wantCost:   0,
+			wantMonths: []int{},
+			wantErr:    nil,
+		},
+		{
+			name: "Grouped by service",
+			input: dto.CalculateCostRequest{
+				UserID:      &validUUID,
+				ServiceName: &serviceName,
+				StartDate:   "09-2025",
+				EndDate:     "12-2025",
+				GroupBy:     []string{"service_name"},
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				yandex, yandexPlus := "yandex", "yandex plus"
+				repo.On("CalculateCost", mock.Anything, &groupedFilter).
+					Return(&entity.CostReport{Groups: []*entity.CostGroup{
+						{ServiceName: &yandex, Subscriptions: 1, Cost: 1600},
+						{ServiceName: &yandexPlus, Subscriptions: 1, Cost: 598},
+					}}, nil)
+			},
+			wantCost:   2198,
+			wantMonths: []int{},
+			wantGroups: 2,
+			wantErr:    nil,
+		},
+		{
+			name: "Converted to USD",
+			input: dto.CalculateCostRequest{
+				UserID:      &validUUID,
+				ServiceName: &serviceName,
+				StartDate:   "09-2025",
+				EndDate:     "12-2025",
+				Currency:    "USD",
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("CalculateCost", mock.Anything, &usdFilter).
+					Return(&entity.CostReport{Subscriptions: []*entity.SubscriptionCost{
+						{SubscriptionID: 1, ServiceName: "yandex", Price: 400, Currency: "RUB", Months: 4, Cost: 20},
+						{SubscriptionID: 2, ServiceName: "youtube", Price: 12, Currency: "USD", Months: 4, Cost: 48},
+					}}, nil)
+			},
+			wantCost:   68,
+			wantMonths: []int{4, 4},
+			wantErr:    nil,
+		},
+		{
+			name: "Amortized annual plan",
+			input: dto.CalculateCostRequest{
+				UserID:      &validUUID,
+				ServiceName: &serviceName,
+				StartDate:   "09-2025",
+				EndDate:     "12-2025",
+				Mode:        "amortized",
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("CalculateCost", mock.Anything, &amortizedFilter).
+					Return(&entity.CostReport{Subscriptions: []*entity.SubscriptionCost{
+						{SubscriptionID: 1, ServiceName: "yandex", Price: 1200, BillingPeriod: "year", Months: 4, Cost: 400},
+					}}, nil)
+			},
+			wantCost:   400,
+			wantMonths: []int{4},
+			wantErr:    nil,
+		},
+		{
+			name: "Unknown mode",
+			input: dto.CalculateCostRequest{
+				StartDate: "09-2025",
+				EndDate:   "12-2025",
+				Mode:      "daily",
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name: "Missing exchange rate",
+			input: dto.CalculateCostRequest{
+				UserID:      &validUUID,
+				ServiceName: &serviceName,
+				StartDate:   "09-2025",
+				EndDate:     "12-2025",
+				Currency:    "USD",
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("CalculateCost", mock.Anything, &usdFilter).
+					Return(nil, custom_err.ErrExchangeRateNotFound)
+			},
+			wantErr: custom_err.ErrExchangeRateNotFound,
+		},
+		{
+			name: "Invalid currency",
+			input: dto.CalculateCostRequest{
+				StartDate: "09-2025",
+				EndDate:   "12-2025",
+				Currency:  "RUR",
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name: "Unknown group key",
+			input: dto.CalculateCostRequest{
+				StartDate: "09-2025",
+				EndDate:   "12-2025",
+				GroupBy:   []string{"price"},
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name: "Missing period",
+			input: dto.CalculateCostRequest{
+				UserID:  &validUUID,
+				EndDate: "12-2025",
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name: "End before start",
+			input: dto.CalculateCostRequest{
+				StartDate: "12-2025",
+				EndDate:   "09-2025",
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name: "Repository error",
+			input: dto.CalculateCostRequest{
+				UserID:      &validUUID,
+				ServiceName: &serviceName,
+				StartDate:   "09-2025",
+				EndDate:     "12-2025",
+			},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("CalculateCost", mock.Anything, filter).
+					Return(nil, custom_err.ErrInternalServer)
+			},
+			wantErr: custom_err.ErrInternalServer,
 		},
 	}
 
@@ -455,19 +1062,290 @@ func Test_CalculateCost(t *testing.T) {
 			mockRepo := mocks.NewISubscriptionRepository(t)
 			validator, _ := validation.New()
 			converter := converters.New()
-			useCase := New(mockRepo, validator, converter)
+			useCase := New(mockRepo, mocks.NewIServiceRepository(t), validator, converter, nil)
 
 			tt.setupMocks(mockRepo)
+			ctx := context.Background()
+
+			cost, err := useCase.CalculateCost(ctx, &tt.input)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
+				assert.Nil(t, cost)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantCost, cost.TotalCost)
+				months := make([]int, 0, len(cost.Subscriptions))
+				for _, item := range cost.Subscriptions {
+					months = append(months, item.Months)
+				}
+				assert.Equal(t, tt.wantMonths, months)
+				assert.Len(t, cost.Groups, tt.wantGroups)
+			}
+		})
+	}
+}
+
+func Test_CostTimeSeries(t *testing.T) {
+	t.Parallel()
+
+	validUUID := uuid.New()
+	startDate := time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, time.November, 1, 0, 0, 0, 0, time.UTC)
+	filter := &entity.CostFilter{UserID: &validUUID, StartDate: startDate, EndDate: endDate, Currency: "RUB", Mode: "charged"}
+
+	tests := []struct {
+		name       string
+		input      dto.CalculateCostRequest
+		setupMocks func(repo *mocks.ISubscriptionRepository)
+		wantCost   money.Amount
+		wantMonths []string
+		wantErr    error
+	}{
+		{
+			name:  "Valid series",
+			input: dto.CalculateCostRequest{UserID: &validUUID, StartDate: "09-2025", EndDate: "11-2025"},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("CostTimeSeries", mock.Anything, filter).
+					Return([]*entity.CostBucket{
+						{Month: startDate, Cost: 699, Subscriptions: 2},
+						{Month: startDate.AddDate(0, 1, 0), Cost: 400, Subscriptions: 1},
+						{Month: endDate},
+					}, nil)
+			},
+			wantCost:   1099,
+			wantMonths: []string{"09-2025", "10-2025", "11-2025"},
+			wantErr:    nil,
+		},
+		{
+			name:       "End before start",
+			input:      dto.CalculateCostRequest{UserID: &validUUID, StartDate: "11-2025", EndDate: "09-2025"},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name:  "Repository error",
+			input: dto.CalculateCostRequest{UserID: &validUUID, StartDate: "09-2025", EndDate: "11-2025"},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("CostTimeSeries", mock.Anything, filter).
+					Return(nil, custom_err.ErrInternalServer)
+			},
+			wantErr: custom_err.ErrInternalServer,
+		},
+	}
 
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewISubscriptionRepository(t)
+			validator, _ := validation.New()
+			converter := converters.New()
+			useCase := New(mockRepo, mocks.NewIServiceRepository(t), validator, converter, nil)
+
+			tt.setupMocks(mockRepo)
 			ctx := context.Background()
-			cost, err := useCase.CalculateCost(ctx, tt.userID, tt.service, tt.startDate, tt.endDate)
-			assert.Equal(t, tt.wantCost, cost)
+
+			series, err := useCase.CostTimeSeries(ctx, &tt.input)
 			if tt.wantErr != nil {
 				assert.Error(t, err)
 				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantCost, series.TotalCost)
+				months := make([]string, 0, len(series.Buckets))
+				for _, bucket := range series.Buckets {
+					months = append(months, bucket.Month)
+				}
+				assert.Equal(t, tt.wantMonths, months)
+			}
+		})
+	}
+}
+
+func Test_Forecast(t *testing.T) {
+	t.Parallel()
+
+	start := utils.MonthStart(time.Now())
+	next := start.AddDate(0, 1, 0)
+	yandex, youtube := "yandex", "youtube"
+	isForecastFilter := func(months int) interface{} {
+		return mock.MatchedBy(func(f *entity.CostFilter) bool {
+			return f.StartDate.Equal(start) && f.EndDate.Equal(start.AddDate(0, months-1, 0)) &&
+				f.ActiveAt != nil && f.ActiveAt.Equal(start) &&
+				len(f.GroupBy) == 2 && f.Currency == "RUB" && f.Mode == "charged"
+		})
+	}
+
+	tests := []struct {
+		name         string
+		input        dto.ForecastRequest
+		setupMocks   func(repo *mocks.ISubscriptionRepository)
+		wantCost     money.Amount
+		wantMonths   []money.Amount
+		wantServices map[string]money.Amount
+		wantErr      error
+	}{
+		{
+			name:  "Per month and per service",
+			input: dto.ForecastRequest{Months: 3},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("CalculateCost", mock.Anything, isForecastFilter(3)).
+					Return(&entity.CostReport{Groups: []*entity.CostGroup{
+						{Month: &start, ServiceName: &yandex, Subscriptions: 1, Cost: 400},
+						{Month: &start, ServiceName: &youtube, Subscriptions: 1, Cost: 300},
+						{Month: &next, ServiceName: &yandex, Subscriptions: 1, Cost: 500},
+					}}, nil)
+			},
+			wantCost:     1200,
+			wantMonths:   []money.Amount{700, 500, 0},
+			wantServices: map[string]money.Amount{"yandex": 900, "youtube": 300},
+		},
+		{
+			name:  "No active subscriptions",
+			input: dto.ForecastRequest{Months: 2},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("CalculateCost", mock.Anything, isForecastFilter(2)).
+					Return(&entity.CostReport{Groups: []*entity.CostGroup{}}, nil)
+			},
+			wantCost:     0,
+			wantMonths:   []money.Amount{0, 0},
+			wantServices: map[string]money.Amount{},
+		},
+		{
+			name:       "Horizon too long",
+			input:      dto.ForecastRequest{Months: 61},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name:       "Empty horizon",
+			input:      dto.ForecastRequest{Months: 0},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name:  "Missing exchange rate",
+			input: dto.ForecastRequest{Months: 3, Currency: "USD"},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("CalculateCost", mock.Anything, mock.Anything).
+					Return(nil, custom_err.ErrExchangeRateNotFound)
+			},
+			wantErr: custom_err.ErrExchangeRateNotFound,
+		},
+		{
+			name:  "Repository error",
+			input: dto.ForecastRequest{Months: 3},
+			setupMocks: func(repo *mocks.ISubscriptionRepository) {
+				repo.On("CalculateCost", mock.Anything, mock.Anything).
+					Return(nil, errors.New("db error"))
+			},
+			wantErr: custom_err.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewISubscriptionRepository(t)
+			validator, _ := validation.New()
+			converter := converters.New()
+			useCase := New(mockRepo, mocks.NewIServiceRepository(t), validator, converter, nil)
+
+			tt.setupMocks(mockRepo)
+
+			result, err := useCase.Forecast(context.Background(), &tt.input)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
+				assert.Nil(t, result)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCost, result.TotalCost)
+			assert.Len(t, result.Months, len(tt.wantMonths))
+			for i, cost := range tt.wantMonths {
+				assert.Equal(t, cost, result.Months[i].Cost)
+			}
+			assert.Len(t, result.Services, len(tt.wantServices))
+			for _, service := range result.Services {
+				assert.Equal(t, tt.wantServices[service.ServiceName], service.Cost)
+			}
+		})
+	}
+}
+
+type mockBudgetWatcher struct{ mock.Mock }
+
+func (m *mockBudgetWatcher) Snapshot(ctx context.Context, userID uuid.UUID) (*entity.BudgetSnapshot, error) {
+	args := m.Called(ctx, userID)
+	snapshot, _ := args.Get(0).(*entity.BudgetSnapshot)
+	return snapshot, args.Error(1)
+}
+
+func (m *mockBudgetWatcher) AlertCrossed(ctx context.Context, before *entity.BudgetSnapshot) error {
+	args := m.Called(ctx, before)
+	return args.Error(0)
+}
+
+func Test_CreateSubscription_BudgetAlerts(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	input := dto.CreateSubscriptionRequest{UserID: userID, ServiceName: "yandex", Price: 299, StartDate: "09-2025"}
+	snapshot := &entity.BudgetSnapshot{UserID: userID, Spent: map[int]money.Amount{1: 900}}
+
+	tests := []struct {
+		name       string
+		setupMocks func(repo *mocks.ISubscriptionRepository, budgets *mockBudgetWatcher)
+		wantErr    error
+	}{
+		{
+			name: "Checks budgets after create",
+			setupMocks: func(repo *mocks.ISubscriptionRepository, budgets *mockBudgetWatcher) {
+				budgets.On("Snapshot", mock.Anything, userID).Return(snapshot, nil)
+				repo.On("Create", mock.Anything, mock.Anything).Return(&entity.Subscription{ID: 1, UserID: userID}, nil)
+				budgets.On("AlertCrossed", mock.Anything, snapshot).Return(nil)
+			},
+		},
+		{
+			name: "Budget failure does not fail create",
+			setupMocks: func(repo *mocks.ISubscriptionRepository, budgets *mockBudgetWatcher) {
+				budgets.On("Snapshot", mock.Anything, userID).Return(snapshot, nil)
+				repo.On("Create", mock.Anything, mock.Anything).Return(&entity.Subscription{ID: 1, UserID: userID}, nil)
+				budgets.On("AlertCrossed", mock.Anything, snapshot).Return(errors.New("publish failed"))
+			},
+		},
+		{
+			name: "No alerts without snapshot",
+			setupMocks: func(repo *mocks.ISubscriptionRepository, budgets *mockBudgetWatcher) {
+				budgets.On("Snapshot", mock.Anything, userID).Return(nil, errors.New("db error"))
+				repo.On("Create", mock.Anything, mock.Anything).Return(&entity.Subscription{ID: 1, UserID: userID}, nil)
+			},
+		},
+		{
+			name: "No alerts when create fails",
+			setupMocks: func(repo *mocks.ISubscriptionRepository, budgets *mockBudgetWatcher) {
+				budgets.On("Snapshot", mock.Anything, userID).Return(snapshot, nil)
+				repo.On("Create", mock.Anything, mock.Anything).Return(nil, custom_err.ErrSubscriptionAlreadyFound)
+			},
+			wantErr: custom_err.ErrSubscriptionAlreadyFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewISubscriptionRepository(t)
+			budgets := new(mockBudgetWatcher)
+			validator, _ := validation.New()
+			useCase := New(mockRepo, mocks.NewIServiceRepository(t), validator, converters.New(), budgets)
+
+			tt.setupMocks(mockRepo, budgets)
+
+			_, err := useCase.Create(context.Background(), &input)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), "expected error: %v, got: %v", tt.wantErr, err)
 			} else {
 				assert.NoError(t, err)
 			}
+			budgets.AssertExpectations(t)
 		})
 	}
 }
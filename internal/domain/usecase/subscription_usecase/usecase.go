@@ -3,36 +3,65 @@ package subscription_usecase
 import (
 	"AggregationService/internal/converters"
 	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
 	"AggregationService/internal/domain/ports/repository"
 	"AggregationService/internal/pkg/validation"
 	"context"
 	"github.com/google/uuid"
-	"time"
+	"io"
 )
 
 type ISubscriptionUseCase interface {
 	Create(ctx context.Context, req *dto.CreateSubscriptionRequest) (*dto.SubscriptionResponse, error)
-	GetByID(ctx context.Context, id int) (*dto.SubscriptionResponse, error)
-	GetAll(ctx context.Context, userID *uuid.UUID, serviceName *string, limit, offset int) ([]*dto.SubscriptionResponse, error)
-	Update(ctx context.Context, id int, req *dto.UpdateSubscriptionRequest) (*dto.SubscriptionResponse, error)
-	Delete(ctx context.Context, id int) error
-	CalculateCost(ctx context.Context, userID *uuid.UUID, serviceName *string, startDate, endDate *time.Time) (int, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*dto.SubscriptionResponse, error)
+	PublicID(ctx context.Context, id int) (uuid.UUID, error)
+	GetAll(ctx context.Context, req *dto.ListSubscriptionsRequest) ([]*dto.SubscriptionResponse, error)
+	Update(ctx context.Context, id uuid.UUID, req *dto.UpdateSubscriptionRequest, version *int) (*dto.SubscriptionResponse, error)
+	Delete(ctx context.Context, id uuid.UUID, version *int) error
+	Batch(ctx context.Context, req *dto.SubscriptionBatchRequest) (*dto.SubscriptionBatchResponse, error)
+	Import(ctx context.Context, file io.Reader, req *dto.ImportSubscriptionsRequest) (*dto.ImportSubscriptionsResponse, error)
+	Restore(ctx context.Context, id uuid.UUID) (*dto.SubscriptionResponse, error)
+	Pause(ctx context.Context, id uuid.UUID, req *dto.PauseSubscriptionRequest, version *int) (*dto.SubscriptionResponse, error)
+	Resume(ctx context.Context, id uuid.UUID, req *dto.ResumeSubscriptionRequest, version *int) (*dto.SubscriptionResponse, error)
+	Cancel(ctx context.Context, id uuid.UUID, req *dto.CancelSubscriptionRequest, version *int) (*dto.SubscriptionResponse, error)
+	GetPauses(ctx context.Context, id uuid.UUID) ([]*dto.SubscriptionPauseResponse, error)
+	AddPrice(ctx context.Context, id uuid.UUID, req *dto.CreateSubscriptionPriceRequest) (*dto.SubscriptionPriceResponse, error)
+	GetPrices(ctx context.Context, id uuid.UUID) ([]*dto.SubscriptionPriceResponse, error)
+	CalculateCost(ctx context.Context, req *dto.CalculateCostRequest) (*dto.CalculateCostResponse, error)
+	CostTimeSeries(ctx context.Context, req *dto.CalculateCostRequest) (*dto.CostTimeSeriesResponse, error)
+	Forecast(ctx context.Context, req *dto.ForecastRequest) (*dto.ForecastResponse, error)
+	Upcoming(ctx context.Context, req *dto.UpcomingChargesRequest) (*dto.UpcomingChargesResponse, error)
+}
+
+// IBudgetWatcher alerts on the budgets that a change to the subscriptions of
+// a user pushes over their threshold.
+type IBudgetWatcher interface {
+	Snapshot(ctx context.Context, userID uuid.UUID) (*entity.BudgetSnapshot, error)
+	AlertCrossed(ctx context.Context, before *entity.BudgetSnapshot) error
 }
 
 type subscriptionUseCase struct {
 	subscriptionRepository repository.ISubscriptionRepository
+	serviceRepository      repository.IServiceRepository
 	validator              *validation.Validator
 	converter              *converters.SubscriptionConverter
+	budgets                IBudgetWatcher
 }
 
+// New creates the subscription use case. budgets may be nil to skip budget
+// alerts.
 func New(
 	subscriptionRepository repository.ISubscriptionRepository,
+	serviceRepository repository.IServiceRepository,
 	validator *validation.Validator,
 	converter *converters.SubscriptionConverter,
+	budgets IBudgetWatcher,
 ) ISubscriptionUseCase {
 	return &subscriptionUseCase{
 		subscriptionRepository: subscriptionRepository,
+		serviceRepository:      serviceRepository,
 		validator:              validator,
 		converter:              converter,
+		budgets:                budgets,
 	}
 }
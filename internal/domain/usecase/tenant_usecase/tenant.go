@@ -0,0 +1,93 @@
+package tenant_usecase
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/logger"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+// apiKeyBytes is the length of a generated API key before it is hex-encoded.
+const apiKeyBytes = 32
+
+// actorPrefix and actorHashLength make the audit actor of an API key: the
+// start of its hash names the key without giving it away.
+const (
+	actorPrefix     = "api-key:"
+	actorHashLength = 12
+)
+
+// Create stores a tenant with a new API key and returns the key. Only its
+// hash is stored, so it can't be shown again.
+func (u *tenantUseCase) Create(ctx context.Context, req *dto.CreateTenantRequest) (*dto.CreateTenantResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+	log.Debug(fmt.Sprintf("trying to create tenant: %s", req.Name))
+
+	req.Name = strings.TrimSpace(req.Name)
+	if err := u.validator.Validate(req); err != nil {
+		log.Error(fmt.Sprintf("invalid input: %v", custom_err.ErrInvalidRequest))
+		return nil, custom_err.ErrInvalidRequest
+	}
+
+	raw := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(raw); err != nil {
+		log.Error(fmt.Sprintf("failed to generate api key: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+	apiKey := hex.EncodeToString(raw)
+
+	tenant := &entity.Tenant{Name: req.Name, CreatedAt: time.Now()}
+	created, err := u.tenantRepository.Create(ctx, tenant, keyHash(apiKey))
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to create tenant: %v", err))
+		return nil, custom_err.ErrInternalServer
+	}
+
+	log.Debug(fmt.Sprintf("success creating tenant: %s", created.ID))
+	return &dto.CreateTenantResponse{
+		ID:        created.ID,
+		Name:      created.Name,
+		APIKey:    apiKey,
+		CreatedAt: created.CreatedAt,
+	}, nil
+}
+
+// Authenticate returns the tenant of apiKey and the actor the audit trail
+// records for the key. A missing, unknown or revoked key fails with
+// ErrUnauthorized.
+func (u *tenantUseCase) Authenticate(ctx context.Context, apiKey string) (uuid.UUID, string, error) {
+	log := logger.FromContext(ctx)
+
+	if apiKey == "" {
+		return uuid.Nil, "", custom_err.ErrUnauthorized
+	}
+
+	hash := keyHash(apiKey)
+	tenant, err := u.tenantRepository.GetByKeyHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, custom_err.ErrTenantNotFound) {
+			log.Error("unknown api key")
+			return uuid.Nil, "", custom_err.ErrUnauthorized
+		}
+		log.Error(fmt.Sprintf("failed to authenticate: %v", err))
+		return uuid.Nil, "", custom_err.ErrInternalServer
+	}
+	return tenant.ID, actorPrefix + hash[:actorHashLength], nil
+}
+
+func keyHash(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,139 @@
+package tenant_usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/models/entity"
+	"AggregationService/internal/domain/ports/repository/mocks"
+	custom_err "AggregationService/internal/errors"
+	"AggregationService/internal/pkg/validation"
+)
+
+func newTenantUseCase(repo *mocks.ITenantRepository) ITenantUseCase {
+	validator, _ := validation.New()
+	return New(repo, validator)
+}
+
+func Test_CreateTenant(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		input      dto.CreateTenantRequest
+		setupMocks func(repo *mocks.ITenantRepository)
+		wantErr    error
+	}{
+		{
+			name:  "Valid tenant",
+			input: dto.CreateTenantRequest{Name: " Acme "},
+			setupMocks: func(repo *mocks.ITenantRepository) {
+				repo.On("Create", mock.Anything, mock.MatchedBy(func(tenant *entity.Tenant) bool {
+					return tenant.Name == "Acme" && !tenant.CreatedAt.IsZero()
+				}), mock.MatchedBy(func(hash string) bool { return len(hash) == 64 })).
+					Return(&entity.Tenant{ID: uuid.New(), Name: "Acme"}, nil)
+			},
+		},
+		{
+			name:       "Missing name",
+			input:      dto.CreateTenantRequest{Name: "  "},
+			setupMocks: func(repo *mocks.ITenantRepository) {},
+			wantErr:    custom_err.ErrInvalidRequest,
+		},
+		{
+			name:  "Repository error",
+			input: dto.CreateTenantRequest{Name: "Acme"},
+			setupMocks: func(repo *mocks.ITenantRepository) {
+				repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+			},
+			wantErr: custom_err.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewITenantRepository(t)
+			useCase := newTenantUseCase(mockRepo)
+			tt.setupMocks(mockRepo)
+
+			result, err := useCase.Create(context.Background(), &tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, result.APIKey, 2*apiKeyBytes)
+			mockRepo.AssertCalled(t, "Create", mock.Anything, mock.Anything, keyHash(result.APIKey))
+		})
+	}
+}
+
+func Test_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	tenantID := uuid.New()
+
+	tests := []struct {
+		name       string
+		apiKey     string
+		setupMocks func(repo *mocks.ITenantRepository)
+		wantErr    error
+	}{
+		{
+			name:   "Valid key",
+			apiKey: "secret",
+			setupMocks: func(repo *mocks.ITenantRepository) {
+				repo.On("GetByKeyHash", mock.Anything, keyHash("secret")).Return(&entity.Tenant{ID: tenantID}, nil)
+			},
+		},
+		{
+			name:       "Missing key",
+			apiKey:     "",
+			setupMocks: func(repo *mocks.ITenantRepository) {},
+			wantErr:    custom_err.ErrUnauthorized,
+		},
+		{
+			name:   "Unknown or revoked key",
+			apiKey: "secret",
+			setupMocks: func(repo *mocks.ITenantRepository) {
+				repo.On("GetByKeyHash", mock.Anything, mock.Anything).Return(nil, custom_err.ErrTenantNotFound)
+			},
+			wantErr: custom_err.ErrUnauthorized,
+		},
+		{
+			name:   "Repository error",
+			apiKey: "secret",
+			setupMocks: func(repo *mocks.ITenantRepository) {
+				repo.On("GetByKeyHash", mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+			},
+			wantErr: custom_err.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewITenantRepository(t)
+			useCase := newTenantUseCase(mockRepo)
+			tt.setupMocks(mockRepo)
+
+			result, actor, err := useCase.Authenticate(context.Background(), tt.apiKey)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, uuid.Nil, result)
+				assert.Empty(t, actor)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tenantID, result)
+			assert.Equal(t, "api-key:"+keyHash(tt.apiKey)[:12], actor)
+			assert.NotContains(t, actor, tt.apiKey)
+		})
+	}
+}
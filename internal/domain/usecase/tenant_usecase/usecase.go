@@ -0,0 +1,26 @@
+package tenant_usecase
+
+import (
+	"AggregationService/internal/domain/models/dto"
+	"AggregationService/internal/domain/ports/repository"
+	"AggregationService/internal/pkg/validation"
+	"context"
+	"github.com/google/uuid"
+)
+
+type ITenantUseCase interface {
+	Create(ctx context.Context, req *dto.CreateTenantRequest) (*dto.CreateTenantResponse, error)
+	Authenticate(ctx context.Context, apiKey string) (uuid.UUID, string, error)
+}
+
+type tenantUseCase struct {
+	tenantRepository repository.ITenantRepository
+	validator        *validation.Validator
+}
+
+func New(tenantRepository repository.ITenantRepository, validator *validation.Validator) ITenantUseCase {
+	return &tenantUseCase{
+		tenantRepository: tenantRepository,
+		validator:        validator,
+	}
+}
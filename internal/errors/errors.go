@@ -1,6 +1,10 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+)
 
 //var (
 //	ErrSubscriptionNotFound = NewAppError("subscription not found", http.StatusNotFound)
@@ -43,4 +47,35 @@ var (
 	ErrNoSubscriptionsFound     = errors.New("0 subscriptions were found")
 	ErrInvalidPagination        = errors.New("invalid pagination parameters")
 	ErrInvalidServiceName       = errors.New("invalid service name")
+	ErrExchangeRateNotFound     = errors.New("exchange rate not found")
+	ErrBudgetNotFound           = errors.New("budget not found")
+	ErrServiceNotFound          = errors.New("service not found")
+	ErrServiceAlreadyExists     = errors.New("service with this name already exists")
+	ErrServiceInUse             = errors.New("service is used by subscriptions")
+	ErrServiceAliasNotFound     = errors.New("service alias not found")
+	ErrCategoryNotFound         = errors.New("category not found")
+	ErrCategoryAlreadyExists    = errors.New("category with this code already exists")
+	ErrSubscriptionNotDeleted   = errors.New("subscription is not deleted")
+	ErrVersionMismatch          = errors.New("subscription was changed by another request")
+	ErrInvalidStatusTransition  = errors.New("subscription status can't change this way")
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was used with another request")
+	ErrIdempotencyKeyInUse      = errors.New("request with this idempotency key is still in progress")
+	ErrTenantNotFound           = errors.New("tenant not found")
+	ErrUnauthorized             = errors.New("missing or invalid API key")
+	ErrBatchRolledBack          = errors.New("batch was rolled back")
 )
+
+// SubscriptionConflictError is ErrSubscriptionAlreadyFound naming the active
+// subscription of the same user and service that overlaps the rejected one.
+type SubscriptionConflictError struct {
+	ConflictingID       int
+	ConflictingPublicID uuid.UUID
+}
+
+func (e *SubscriptionConflictError) Error() string {
+	return fmt.Sprintf("%s: overlaps subscription %s", ErrSubscriptionAlreadyFound, e.ConflictingPublicID)
+}
+
+func (e *SubscriptionConflictError) Unwrap() error {
+	return ErrSubscriptionAlreadyFound
+}
@@ -2,10 +2,13 @@ package go_postgres
 
 import (
 	"AggregationService/internal/pkg/logger"
+	"AggregationService/internal/pkg/tenant"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 	"time"
@@ -39,3 +42,57 @@ func NewPGClient(ctx context.Context, cfg IPGConfig) (*PostgresClient, error) {
 func (p *PostgresClient) SQLDB() *sql.DB {
 	return p.DB.DB
 }
+
+// ErrNoTenant is returned for queries made without a tenant in the context.
+var ErrNoTenant = errors.New("no tenant in context")
+
+// BeginTx starts a transaction scoped to the tenant of ctx. Row-level
+// security lets it see and write only the rows of that tenant.
+func (p *PostgresClient) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, ErrNoTenant
+	}
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	if err = SetTenant(ctx, tx, tenantID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return tx, nil
+}
+
+// AppRole is the role tenant transactions run as. Row-level security applies
+// to it even when the service logs in as a superuser, see
+// 20251220120000_app_role.sql.
+const AppRole = "aggregation_app"
+
+// SetTenant scopes tx to tenantID until it ends, see current_tenant_id, and
+// switches it to AppRole.
+func SetTenant(ctx context.Context, tx *sqlx.Tx, tenantID uuid.UUID) error {
+	const query = "SELECT set_config('app.tenant_id', $1, true), set_config('role', $2, true)"
+	if _, err := tx.ExecContext(ctx, query, tenantID.String(), AppRole); err != nil {
+		return fmt.Errorf("set tenant: %w", err)
+	}
+	return nil
+}
+
+// InTx runs fn in a transaction scoped to the tenant of ctx and commits it
+// when fn succeeds. The error of fn is returned as is.
+func (p *PostgresClient) InTx(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	tx, err := p.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subscriptions
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'RUB';
+
+-- rate is the price of one unit of currency in roubles, valid from the given month
+CREATE TABLE exchange_rates (
+    id SERIAL PRIMARY KEY,
+    currency CHAR(3) NOT NULL,
+    rate NUMERIC(18, 8) NOT NULL CHECK (rate > 0),
+    valid_from DATE NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (currency, valid_from)
+);
+
+CREATE OR REPLACE FUNCTION exchange_rate(p_currency CHAR(3), p_month DATE) RETURNS NUMERIC AS $$
+DECLARE
+    v_rate NUMERIC;
+BEGIN
+    IF p_currency = 'RUB' THEN
+        RETURN 1;
+    END IF;
+
+    SELECT rate INTO v_rate
+    FROM exchange_rates
+    WHERE currency = p_currency AND valid_from <= p_month
+    ORDER BY valid_from DESC
+    LIMIT 1;
+
+    IF v_rate IS NULL THEN
+        RAISE EXCEPTION 'no exchange rate for % at %', p_currency, p_month USING ERRCODE = 'no_data_found';
+    END IF;
+
+    RETURN v_rate;
+END;
+$$ LANGUAGE plpgsql STABLE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS exchange_rate(CHAR(3), DATE);
+DROP TABLE IF EXISTS exchange_rates;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS currency;
+-- +goose StatementEnd
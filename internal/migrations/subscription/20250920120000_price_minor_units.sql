@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subscriptions
+    ALTER COLUMN price TYPE BIGINT USING price::BIGINT * 100;
+
+COMMENT ON COLUMN subscriptions.price IS 'monthly price in minor units (kopecks, cents)';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+COMMENT ON COLUMN subscriptions.price IS NULL;
+
+ALTER TABLE subscriptions
+    ALTER COLUMN price TYPE INT USING ROUND(price / 100.0)::INT;
+-- +goose StatementEnd
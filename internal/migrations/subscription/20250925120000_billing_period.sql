@@ -0,0 +1,64 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subscriptions
+    ADD COLUMN billing_period VARCHAR(16) NOT NULL DEFAULT 'month'
+        CHECK (billing_period IN ('week', 'month', 'quarter', 'year', 'custom')),
+    ADD COLUMN billing_months INT NOT NULL DEFAULT 1
+        CHECK (billing_months BETWEEN 1 AND 120);
+
+COMMENT ON COLUMN subscriptions.price IS 'price per billing period in minor units (kopecks, cents)';
+COMMENT ON COLUMN subscriptions.billing_months IS 'length of the billing period in months, ignored for weekly billing';
+
+-- billing_factor returns how many times the price of a subscription counts in
+-- the given month: the number of charges made in that month, or the monthly
+-- share of one period when amortized.
+CREATE OR REPLACE FUNCTION billing_factor(
+    p_period VARCHAR,
+    p_months INT,
+    p_start DATE,
+    p_end DATE,
+    p_month DATE,
+    p_amortized BOOLEAN
+) RETURNS NUMERIC AS $$
+DECLARE
+    v_next DATE := (p_month + INTERVAL '1 month')::DATE;
+    v_from DATE;
+    v_to DATE;
+BEGIN
+    IF p_period = 'week' THEN
+        IF p_amortized THEN
+            RETURN (v_next - p_month) / 7.0;
+        END IF;
+
+        -- charges happen every 7 days from p_start until the end of p_end's month
+        v_from := GREATEST(p_month, p_start);
+        v_to := LEAST(v_next, (p_end + INTERVAL '1 month')::DATE);
+        IF v_to <= v_from THEN
+            RETURN 0;
+        END IF;
+        RETURN (v_to - p_start + 6) / 7 - (v_from - p_start + 6) / 7;
+    END IF;
+
+    IF p_amortized THEN
+        RETURN 1.0 / p_months;
+    END IF;
+
+    IF ((EXTRACT(YEAR FROM p_month) - EXTRACT(YEAR FROM p_start)) * 12
+        + EXTRACT(MONTH FROM p_month) - EXTRACT(MONTH FROM p_start))::INT % p_months = 0 THEN
+        RETURN 1;
+    END IF;
+    RETURN 0;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS billing_factor(VARCHAR, INT, DATE, DATE, DATE, BOOLEAN);
+
+COMMENT ON COLUMN subscriptions.price IS 'monthly price in minor units (kopecks, cents)';
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS billing_months,
+    DROP COLUMN IF EXISTS billing_period;
+-- +goose StatementEnd
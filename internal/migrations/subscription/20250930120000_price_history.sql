@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- every row is the price of a subscription from effective_from until the next row
+CREATE TABLE subscription_prices (
+    id SERIAL PRIMARY KEY,
+    subscription_id INT NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    price BIGINT NOT NULL CHECK (price > 0),
+    effective_from DATE NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (subscription_id, effective_from)
+);
+
+INSERT INTO subscription_prices (subscription_id, price, effective_from)
+SELECT id, price, date_trunc('month', start_date)::date
+FROM subscriptions;
+
+COMMENT ON COLUMN subscriptions.price IS 'latest price per billing period in minor units, history in subscription_prices';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+COMMENT ON COLUMN subscriptions.price IS 'price per billing period in minor units (kopecks, cents)';
+
+DROP TABLE IF EXISTS subscription_prices;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a budget limits the spend of a user per calendar month or year, optionally
+-- for one service only; threshold is the share of amount in percent that
+-- raises an alert
+CREATE TABLE budgets (
+    id SERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    service_name VARCHAR(255),
+    period VARCHAR(8) NOT NULL CHECK (period IN ('month', 'year')),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL DEFAULT 'RUB',
+    threshold INT NOT NULL DEFAULT 100 CHECK (threshold BETWEEN 1 AND 1000),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_budgets_user_id ON budgets(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_budgets_user_id;
+DROP TABLE IF EXISTS budgets;
+-- +goose StatementEnd
@@ -0,0 +1,98 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- the single row holds the last month kept in subscription_monthly_rollup,
+-- open-ended subscriptions are rolled up until then
+CREATE TABLE subscription_rollup_state (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    horizon DATE NOT NULL
+);
+
+-- every row is what a subscription costs in one month in its own currency,
+-- charged and amortized
+CREATE TABLE subscription_monthly_rollup (
+    subscription_id INT NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    service_name VARCHAR(255) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    month DATE NOT NULL,
+    charged NUMERIC NOT NULL,
+    amortized NUMERIC NOT NULL,
+    PRIMARY KEY (subscription_id, month)
+);
+
+CREATE INDEX idx_subscription_monthly_rollup_user_month ON subscription_monthly_rollup (user_id, month);
+CREATE INDEX idx_subscription_monthly_rollup_month ON subscription_monthly_rollup (month);
+CREATE INDEX idx_subscription_monthly_rollup_service_name ON subscription_monthly_rollup
+    USING GIN (service_name gin_trgm_ops);
+
+-- subscription_rollup_rows computes the rollup rows of one subscription, or of
+-- all of them when p_subscription_id is NULL, up to the stored horizon.
+CREATE OR REPLACE FUNCTION subscription_rollup_rows(p_subscription_id INT)
+RETURNS TABLE (
+    subscription_id INT,
+    user_id UUID,
+    service_name VARCHAR,
+    currency CHAR(3),
+    month DATE,
+    charged NUMERIC,
+    amortized NUMERIC
+) AS $$
+    SELECT s.id, s.user_id, s.service_name, s.currency, m.month::date,
+        p.price * billing_factor(s.billing_period, s.billing_months, s.start_date, s.end_date, m.month::date, FALSE),
+        p.price * billing_factor(s.billing_period, s.billing_months, s.start_date, s.end_date, m.month::date, TRUE)
+    FROM subscriptions s
+    CROSS JOIN subscription_rollup_state r
+    CROSS JOIN LATERAL generate_series(
+        date_trunc('month', s.start_date),
+        date_trunc('month', LEAST(COALESCE(s.end_date, r.horizon), r.horizon)),
+        interval '1 month'
+    ) AS m(month)
+    CROSS JOIN LATERAL (
+        SELECT sp.price FROM subscription_prices sp
+        WHERE sp.subscription_id = s.id AND sp.effective_from <= m.month
+        ORDER BY sp.effective_from DESC
+        LIMIT 1
+    ) AS p
+    WHERE p_subscription_id IS NULL OR s.id = p_subscription_id;
+$$ LANGUAGE sql STABLE;
+
+-- refresh_subscription_rollup recomputes the rollup rows of one subscription.
+CREATE OR REPLACE FUNCTION refresh_subscription_rollup(p_subscription_id INT) RETURNS VOID AS $$
+BEGIN
+    DELETE FROM subscription_monthly_rollup WHERE subscription_monthly_rollup.subscription_id = p_subscription_id;
+    INSERT INTO subscription_monthly_rollup
+    SELECT * FROM subscription_rollup_rows(p_subscription_id);
+END;
+$$ LANGUAGE plpgsql;
+
+-- rebuild_subscription_rollup moves the horizon and recomputes every row,
+-- returning how many rows were written.
+CREATE OR REPLACE FUNCTION rebuild_subscription_rollup(p_horizon DATE) RETURNS BIGINT AS $$
+DECLARE
+    v_rows BIGINT;
+BEGIN
+    UPDATE subscription_rollup_state SET horizon = date_trunc('month', p_horizon)::date;
+    DELETE FROM subscription_monthly_rollup;
+    INSERT INTO subscription_monthly_rollup
+    SELECT * FROM subscription_rollup_rows(NULL);
+    GET DIAGNOSTICS v_rows = ROW_COUNT;
+    RETURN v_rows;
+END;
+$$ LANGUAGE plpgsql;
+
+INSERT INTO subscription_rollup_state (horizon)
+VALUES (date_trunc('month', NOW() + interval '5 years')::date);
+
+SELECT rebuild_subscription_rollup(date_trunc('month', NOW() + interval '5 years')::date);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS rebuild_subscription_rollup(DATE);
+DROP FUNCTION IF EXISTS refresh_subscription_rollup(INT);
+DROP FUNCTION IF EXISTS subscription_rollup_rows(INT);
+DROP TABLE IF EXISTS subscription_monthly_rollup;
+DROP TABLE IF EXISTS subscription_rollup_state;
+-- +goose StatementEnd
@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+-- normalize_service_name is the key two service names are the same service
+-- by: trimmed, inner whitespace collapsed and lower-cased.
+CREATE OR REPLACE FUNCTION normalize_service_name(p_name TEXT) RETURNS TEXT AS $$
+    SELECT lower(regexp_replace(btrim(p_name), '\s+', ' ', 'g'));
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE TABLE services (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    normalized_name VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- one service per normalized name, named after its most used spelling
+INSERT INTO services (name, normalized_name)
+SELECT DISTINCT ON (normalize_service_name(service_name))
+    regexp_replace(btrim(service_name), '\s+', ' ', 'g'),
+    normalize_service_name(service_name)
+FROM subscriptions
+GROUP BY service_name
+ORDER BY normalize_service_name(service_name), COUNT(*) DESC, service_name;
+
+ALTER TABLE subscriptions ADD COLUMN service_id INT REFERENCES services(id);
+
+UPDATE subscriptions s
+SET service_id = sv.id, service_name = sv.name
+FROM services sv
+WHERE sv.normalized_name = normalize_service_name(s.service_name);
+
+UPDATE subscription_monthly_rollup r
+SET service_name = s.service_name
+FROM subscriptions s
+WHERE s.id = r.subscription_id;
+
+ALTER TABLE subscriptions ALTER COLUMN service_id SET NOT NULL;
+
+CREATE INDEX idx_subscriptions_service_id ON subscriptions(service_id);
+
+COMMENT ON COLUMN subscriptions.service_name IS 'copy of services.name kept for display and cost grouping';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+COMMENT ON COLUMN subscriptions.service_name IS NULL;
+
+DROP INDEX IF EXISTS idx_subscriptions_service_id;
+
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS service_id;
+
+DROP TABLE IF EXISTS services;
+DROP FUNCTION IF EXISTS normalize_service_name(TEXT);
+-- +goose StatementEnd
@@ -0,0 +1,69 @@
+-- +goose Up
+-- +goose StatementBegin
+-- normalize_service_name mirrors utils.NormalizeServiceName: trimmed, inner
+-- whitespace collapsed, lower-cased and Cyrillic transliterated to Latin.
+CREATE OR REPLACE FUNCTION normalize_service_name(p_name TEXT) RETURNS TEXT AS $$
+    SELECT translate(
+        replace(replace(replace(replace(replace(replace(replace(replace(
+            lower(regexp_replace(btrim(p_name), '\s+', ' ', 'g')),
+            'ж', 'zh'), 'х', 'kh'), 'ц', 'ts'), 'ч', 'ch'),
+            'щ', 'shch'), 'ш', 'sh'), 'ю', 'yu'), 'я', 'ya'),
+        'абвгдеёзийклмнопрстуфыэъь',
+        'abvgdeeziyklmnoprstufye'
+    );
+$$ LANGUAGE sql IMMUTABLE;
+
+-- every alias resolves to its service, aliases share the key space of
+-- services.normalized_name
+CREATE TABLE service_aliases (
+    id SERIAL PRIMARY KEY,
+    service_id INT NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+    alias VARCHAR(255) NOT NULL,
+    normalized_alias VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_service_aliases_service_id ON service_aliases(service_id);
+CREATE INDEX idx_services_normalized_name_trgm ON services USING GIN (normalized_name gin_trgm_ops);
+
+-- services whose names only differ by script become one service, the oldest
+CREATE TEMP TABLE service_merges ON COMMIT DROP AS
+SELECT id, keep_id FROM (
+    SELECT id, MIN(id) OVER (PARTITION BY normalize_service_name(name)) AS keep_id
+    FROM services
+) keyed
+WHERE id <> keep_id;
+
+UPDATE subscriptions s
+SET service_id = m.keep_id
+FROM service_merges m
+WHERE s.service_id = m.id;
+
+DELETE FROM services sv
+USING service_merges m
+WHERE sv.id = m.id;
+
+UPDATE services SET normalized_name = normalize_service_name(name);
+
+UPDATE subscriptions s
+SET service_name = sv.name
+FROM services sv
+WHERE sv.id = s.service_id AND s.service_name <> sv.name;
+
+UPDATE subscription_monthly_rollup r
+SET service_name = s.service_name
+FROM subscriptions s
+WHERE s.id = r.subscription_id AND r.service_name <> s.service_name;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_services_normalized_name_trgm;
+DROP TABLE IF EXISTS service_aliases;
+
+CREATE OR REPLACE FUNCTION normalize_service_name(p_name TEXT) RETURNS TEXT AS $$
+    SELECT lower(regexp_replace(btrim(p_name), '\s+', ' ', 'g'));
+$$ LANGUAGE sql IMMUTABLE;
+
+UPDATE services SET normalized_name = normalize_service_name(name);
+-- +goose StatementEnd
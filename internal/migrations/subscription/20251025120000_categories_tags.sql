@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE categories (
+    code VARCHAR(50) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO categories (code, name) VALUES
+    ('streaming', 'Стриминг'),
+    ('music', 'Музыка'),
+    ('gaming', 'Игры'),
+    ('cloud', 'Облачные хранилища'),
+    ('productivity', 'Продуктивность'),
+    ('education', 'Образование'),
+    ('news', 'Новости и медиа'),
+    ('fitness', 'Здоровье и спорт'),
+    ('other', 'Другое');
+
+-- every subscription belongs to one category, 'other' until it is set
+ALTER TABLE subscriptions
+    ADD COLUMN category VARCHAR(50) NOT NULL DEFAULT 'other' REFERENCES categories(code);
+
+CREATE INDEX idx_subscriptions_category ON subscriptions(category);
+
+-- tags are free-form labels, stored trimmed and lower-cased
+CREATE TABLE subscription_tags (
+    subscription_id INT NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    tag VARCHAR(50) NOT NULL,
+    PRIMARY KEY (subscription_id, tag)
+);
+
+CREATE INDEX idx_subscription_tags_tag ON subscription_tags(tag);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS subscription_tags;
+
+DROP INDEX IF EXISTS idx_subscriptions_category;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS category;
+
+DROP TABLE IF EXISTS categories;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- audit rows outlive the subscriptions they describe, so there is no foreign key
+CREATE TABLE subscription_audit (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id INT NOT NULL,
+    user_id UUID NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore', 'price')),
+    before JSONB,
+    after JSONB,
+    request_id VARCHAR(255) NOT NULL DEFAULT '',
+    actor VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_subscription_audit_subscription ON subscription_audit(subscription_id, id);
+CREATE INDEX idx_subscription_audit_user ON subscription_audit(user_id, id);
+CREATE INDEX idx_subscription_audit_created_at ON subscription_audit(created_at);
+
+CREATE OR REPLACE FUNCTION subscription_audit_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'subscription_audit is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER subscription_audit_no_update
+    BEFORE UPDATE OR DELETE ON subscription_audit
+    FOR EACH ROW EXECUTE FUNCTION subscription_audit_append_only();
+
+CREATE TRIGGER subscription_audit_no_truncate
+    BEFORE TRUNCATE ON subscription_audit
+    FOR EACH STATEMENT EXECUTE FUNCTION subscription_audit_append_only();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS subscription_audit;
+DROP FUNCTION IF EXISTS subscription_audit_append_only();
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- version is bumped by every change of a subscription and guards concurrent updates
+ALTER TABLE subscriptions ADD COLUMN version INT NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS version;
+-- +goose StatementEnd
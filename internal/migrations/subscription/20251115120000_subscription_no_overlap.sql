@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+-- btree_gist lets the exclusion constraint compare user_id and service_id with =
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- the constraint can't be added while subscriptions overlap. Of each set of
+-- overlapping subscriptions the one that starts first is kept, the others are
+-- soft-deleted like by DELETE /subscriptions/{id} and reported, so they can
+-- be restored once the subscription they overlap is changed.
+DO $$
+DECLARE
+    r RECORD;
+BEGIN
+    FOR r IN
+        SELECT * FROM subscriptions
+        WHERE deleted_at IS NULL
+        ORDER BY user_id, service_id, start_date, id
+    LOOP
+        IF EXISTS (
+            SELECT 1 FROM subscriptions kept
+            WHERE kept.deleted_at IS NULL
+                AND kept.user_id = r.user_id
+                AND kept.service_id = r.service_id
+                AND (kept.start_date, kept.id) < (r.start_date, r.id)
+                AND daterange(kept.start_date, kept.end_date, '[]') && daterange(r.start_date, r.end_date, '[]')
+        ) THEN
+            UPDATE subscriptions SET deleted_at = NOW(), version = version + 1 WHERE id = r.id;
+            INSERT INTO subscription_audit (subscription_id, user_id, action, before, actor)
+            VALUES (r.id, r.user_id, 'delete', to_jsonb(r), 'migration:subscription_no_overlap');
+            RAISE NOTICE 'subscription % overlaps an earlier subscription of user % to service % and is deleted',
+                r.id, r.user_id, r.service_id;
+        END IF;
+    END LOOP;
+END;
+$$;
+
+-- a user can't have two active subscriptions to one service in the same month;
+-- end_date is the last paid month, so the range includes it.
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_no_overlap
+    EXCLUDE USING gist (
+        user_id WITH =,
+        service_id WITH =,
+        daterange(start_date, end_date, '[]') WITH &&
+    ) WHERE (deleted_at IS NULL);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_no_overlap;
+-- +goose StatementEnd
@@ -0,0 +1,103 @@
+-- +goose Up
+-- +goose StatementBegin
+-- expired is not stored: an active subscription whose end_date has passed is expired
+ALTER TABLE subscriptions
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'paused', 'cancelled'));
+
+-- every row is a pause of a subscription from paused_from until the month
+-- before resumed_from, open while resumed_from is NULL; paused months cost nothing
+CREATE TABLE subscription_pauses (
+    id SERIAL PRIMARY KEY,
+    subscription_id INT NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    paused_from DATE NOT NULL,
+    resumed_from DATE CHECK (resumed_from > paused_from),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT subscription_pauses_no_overlap EXCLUDE USING gist (
+        subscription_id WITH =,
+        daterange(paused_from, resumed_from) WITH &&
+    )
+);
+
+-- same as in 20251010120000_monthly_rollup.sql, without the paused months
+CREATE OR REPLACE FUNCTION subscription_rollup_rows(p_subscription_id INT)
+RETURNS TABLE (
+    subscription_id INT,
+    user_id UUID,
+    service_name VARCHAR,
+    currency CHAR(3),
+    month DATE,
+    charged NUMERIC,
+    amortized NUMERIC
+) AS $$
+    SELECT s.id, s.user_id, s.service_name, s.currency, m.month::date,
+        p.price * billing_factor(s.billing_period, s.billing_months, s.start_date, s.end_date, m.month::date, FALSE),
+        p.price * billing_factor(s.billing_period, s.billing_months, s.start_date, s.end_date, m.month::date, TRUE)
+    FROM subscriptions s
+    CROSS JOIN subscription_rollup_state r
+    CROSS JOIN LATERAL generate_series(
+        date_trunc('month', s.start_date),
+        date_trunc('month', LEAST(COALESCE(s.end_date, r.horizon), r.horizon)),
+        interval '1 month'
+    ) AS m(month)
+    CROSS JOIN LATERAL (
+        SELECT sp.price FROM subscription_prices sp
+        WHERE sp.subscription_id = s.id AND sp.effective_from <= m.month
+        ORDER BY sp.effective_from DESC
+        LIMIT 1
+    ) AS p
+    WHERE (p_subscription_id IS NULL OR s.id = p_subscription_id)
+        AND NOT EXISTS (
+            SELECT 1 FROM subscription_pauses pp
+            WHERE pp.subscription_id = s.id AND pp.paused_from <= m.month
+                AND (pp.resumed_from IS NULL OR pp.resumed_from > m.month)
+        );
+$$ LANGUAGE sql STABLE;
+
+ALTER TABLE subscription_audit DROP CONSTRAINT subscription_audit_action_check;
+ALTER TABLE subscription_audit ADD CONSTRAINT subscription_audit_action_check
+    CHECK (action IN ('create', 'update', 'delete', 'restore', 'price', 'pause', 'resume', 'cancel'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- the audit trail is append-only, so earlier status changes stay unchecked
+ALTER TABLE subscription_audit DROP CONSTRAINT subscription_audit_action_check;
+ALTER TABLE subscription_audit ADD CONSTRAINT subscription_audit_action_check
+    CHECK (action IN ('create', 'update', 'delete', 'restore', 'price')) NOT VALID;
+
+CREATE OR REPLACE FUNCTION subscription_rollup_rows(p_subscription_id INT)
+RETURNS TABLE (
+    subscription_id INT,
+    user_id UUID,
+    service_name VARCHAR,
+    currency CHAR(3),
+    month DATE,
+    charged NUMERIC,
+    amortized NUMERIC
+) AS $$
+    SELECT s.id, s.user_id, s.service_name, s.currency, m.month::date,
+        p.price * billing_factor(s.billing_period, s.billing_months, s.start_date, s.end_date, m.month::date, FALSE),
+        p.price * billing_factor(s.billing_period, s.billing_months, s.start_date, s.end_date, m.month::date, TRUE)
+    FROM subscriptions s
+    CROSS JOIN subscription_rollup_state r
+    CROSS JOIN LATERAL generate_series(
+        date_trunc('month', s.start_date),
+        date_trunc('month', LEAST(COALESCE(s.end_date, r.horizon), r.horizon)),
+        interval '1 month'
+    ) AS m(month)
+    CROSS JOIN LATERAL (
+        SELECT sp.price FROM subscription_prices sp
+        WHERE sp.subscription_id = s.id AND sp.effective_from <= m.month
+        ORDER BY sp.effective_from DESC
+        LIMIT 1
+    ) AS p
+    WHERE p_subscription_id IS NULL OR s.id = p_subscription_id;
+$$ LANGUAGE sql STABLE;
+
+DROP TABLE IF EXISTS subscription_pauses;
+
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS status;
+
+SELECT rebuild_subscription_rollup(horizon) FROM subscription_rollup_state;
+-- +goose StatementEnd
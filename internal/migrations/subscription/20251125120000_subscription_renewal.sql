@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subscriptions
+    ADD COLUMN billing_day SMALLINT NOT NULL DEFAULT 1
+        CHECK (billing_day BETWEEN 1 AND 31),
+    ADD COLUMN auto_renew BOOLEAN NOT NULL DEFAULT TRUE;
+
+COMMENT ON COLUMN subscriptions.billing_day IS 'day of the month charges are made on, the last day of shorter months; ignored for weekly billing';
+COMMENT ON COLUMN subscriptions.auto_renew IS 'whether the subscription is charged automatically, only these have upcoming charges';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS auto_renew,
+    DROP COLUMN IF EXISTS billing_day;
+-- +goose StatementEnd
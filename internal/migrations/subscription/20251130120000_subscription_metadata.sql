@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subscriptions
+    ADD COLUMN metadata JSONB NOT NULL DEFAULT '{}'
+        CHECK (jsonb_typeof(metadata) = 'object');
+
+COMMENT ON COLUMN subscriptions.metadata IS 'client attributes of the subscription as an object of strings';
+
+-- serves the metadata @> '{"key": "value"}' filters of the subscription list
+CREATE INDEX idx_subscriptions_metadata ON subscriptions USING GIN (metadata jsonb_path_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_subscriptions_metadata;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS metadata;
+-- +goose StatementEnd
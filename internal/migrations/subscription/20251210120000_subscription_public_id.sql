@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+-- uuid_generate_v7 builds a UUIDv7 (RFC 9562): 48 bits of unix milliseconds
+-- followed by random bits, so new ids still sort by creation time.
+CREATE OR REPLACE FUNCTION uuid_generate_v7(ts TIMESTAMPTZ DEFAULT clock_timestamp()) RETURNS UUID AS $$
+    SELECT encode(
+        set_bit(
+            set_bit(
+                overlay(uuid_send(gen_random_uuid())
+                    PLACING substring(int8send(floor(extract(epoch FROM ts) * 1000)::BIGINT) FROM 3)
+                    FROM 1 FOR 6),
+                52, 1),
+            53, 1),
+        'hex')::UUID;
+$$ LANGUAGE SQL VOLATILE;
+
+ALTER TABLE subscriptions ADD COLUMN public_id UUID;
+UPDATE subscriptions SET public_id = uuid_generate_v7(created_at);
+ALTER TABLE subscriptions
+    ALTER COLUMN public_id SET DEFAULT uuid_generate_v7(),
+    ALTER COLUMN public_id SET NOT NULL;
+
+COMMENT ON COLUMN subscriptions.public_id IS 'id of the subscription in the API, the serial id is internal';
+
+CREATE UNIQUE INDEX idx_subscriptions_public_id ON subscriptions(public_id);
+
+-- the audit trail outlives the subscriptions, so it keeps the public id too;
+-- entries of already purged subscriptions stay without one
+ALTER TABLE subscription_audit ADD COLUMN subscription_public_id UUID;
+ALTER TABLE subscription_audit DISABLE TRIGGER subscription_audit_no_update;
+UPDATE subscription_audit a SET subscription_public_id = s.public_id
+FROM subscriptions s
+WHERE s.id = a.subscription_id;
+ALTER TABLE subscription_audit ENABLE TRIGGER subscription_audit_no_update;
+
+CREATE INDEX idx_subscription_audit_subscription_public_id ON subscription_audit(subscription_public_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_subscription_audit_subscription_public_id;
+ALTER TABLE subscription_audit DROP COLUMN IF EXISTS subscription_public_id;
+DROP INDEX IF EXISTS idx_subscriptions_public_id;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS public_id;
+DROP FUNCTION IF EXISTS uuid_generate_v7(TIMESTAMPTZ);
+-- +goose StatementEnd
@@ -0,0 +1,252 @@
+-- +goose Up
+-- +goose StatementBegin
+-- every organization using the service is a tenant, it only reaches its own
+-- data with one of its API keys
+CREATE TABLE tenants (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- only the SHA-256 of a key is stored; a revoked key no longer authenticates
+CREATE TABLE tenant_api_keys (
+    key_hash CHAR(64) PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX idx_tenant_api_keys_tenant_id ON tenant_api_keys(tenant_id);
+
+-- the data stored before tenants belongs to the default tenant
+INSERT INTO tenants (id, name) VALUES ('00000000-0000-0000-0000-000000000001', 'default');
+
+-- current_tenant_id is the tenant the transaction is scoped to, set by the
+-- application with set_config('app.tenant_id', ..., true). It is NULL outside
+-- of such a transaction, which matches no row.
+CREATE OR REPLACE FUNCTION current_tenant_id() RETURNS UUID AS $$
+    SELECT NULLIF(current_setting('app.tenant_id', true), '')::UUID;
+$$ LANGUAGE sql STABLE;
+
+-- every table gets the tenant of its rows, filled in from the transaction,
+-- and a policy that hides the rows of other tenants even from a query that
+-- forgets to filter by tenant. FORCE applies it to the table owner too; only
+-- superusers and BYPASSRLS roles are not bound by it.
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'subscriptions', 'subscription_prices', 'subscription_tags', 'subscription_pauses',
+        'subscription_audit', 'subscription_monthly_rollup', 'subscription_rollup_state',
+        'exchange_rates', 'budgets', 'services', 'service_aliases', 'categories', 'idempotency_keys'
+    ] LOOP
+        EXECUTE format('ALTER TABLE %I ADD COLUMN tenant_id UUID NOT NULL DEFAULT %L REFERENCES tenants(id)',
+            t, '00000000-0000-0000-0000-000000000001');
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN tenant_id SET DEFAULT current_tenant_id()', t);
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format('CREATE POLICY tenant_isolation ON %I
+            USING (tenant_id = current_tenant_id())
+            WITH CHECK (tenant_id = current_tenant_id())', t);
+    END LOOP;
+END;
+$$;
+
+CREATE INDEX idx_subscriptions_tenant_id ON subscriptions(tenant_id);
+CREATE INDEX idx_subscription_prices_tenant_id ON subscription_prices(tenant_id);
+CREATE INDEX idx_subscription_tags_tenant_id ON subscription_tags(tenant_id);
+CREATE INDEX idx_subscription_pauses_tenant_id ON subscription_pauses(tenant_id);
+CREATE INDEX idx_subscription_audit_tenant_id ON subscription_audit(tenant_id, id);
+CREATE INDEX idx_subscription_monthly_rollup_tenant_id ON subscription_monthly_rollup(tenant_id, month);
+CREATE INDEX idx_budgets_tenant_id ON budgets(tenant_id);
+
+-- names, codes and keys are unique within a tenant
+ALTER TABLE services DROP CONSTRAINT services_normalized_name_key;
+ALTER TABLE services ADD CONSTRAINT services_normalized_name_key UNIQUE (tenant_id, normalized_name);
+
+ALTER TABLE service_aliases DROP CONSTRAINT service_aliases_normalized_alias_key;
+ALTER TABLE service_aliases ADD CONSTRAINT service_aliases_normalized_alias_key UNIQUE (tenant_id, normalized_alias);
+
+ALTER TABLE exchange_rates DROP CONSTRAINT exchange_rates_currency_valid_from_key;
+ALTER TABLE exchange_rates ADD CONSTRAINT exchange_rates_currency_valid_from_key UNIQUE (tenant_id, currency, valid_from);
+
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (tenant_id, scope, key);
+
+ALTER TABLE subscriptions DROP CONSTRAINT subscriptions_category_fkey;
+ALTER TABLE categories DROP CONSTRAINT categories_pkey;
+ALTER TABLE categories ADD PRIMARY KEY (tenant_id, code);
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_category_fkey
+    FOREIGN KEY (tenant_id, category) REFERENCES categories(tenant_id, code);
+
+-- the rollup horizon is kept per tenant
+ALTER TABLE subscription_rollup_state DROP COLUMN id;
+ALTER TABLE subscription_rollup_state ADD PRIMARY KEY (tenant_id);
+
+-- same as in 20251120120000_subscription_status.sql, for the subscriptions of
+-- the current tenant up to its own horizon
+CREATE OR REPLACE FUNCTION subscription_rollup_rows(p_subscription_id INT)
+RETURNS TABLE (
+    subscription_id INT,
+    user_id UUID,
+    service_name VARCHAR,
+    currency CHAR(3),
+    month DATE,
+    charged NUMERIC,
+    amortized NUMERIC
+) AS $$
+    SELECT s.id, s.user_id, s.service_name, s.currency, m.month::date,
+        p.price * billing_factor(s.billing_period, s.billing_months, s.start_date, s.end_date, m.month::date, FALSE),
+        p.price * billing_factor(s.billing_period, s.billing_months, s.start_date, s.end_date, m.month::date, TRUE)
+    FROM subscriptions s
+    JOIN subscription_rollup_state r ON r.tenant_id = s.tenant_id
+    CROSS JOIN LATERAL generate_series(
+        date_trunc('month', s.start_date),
+        date_trunc('month', LEAST(COALESCE(s.end_date, r.horizon), r.horizon)),
+        interval '1 month'
+    ) AS m(month)
+    CROSS JOIN LATERAL (
+        SELECT sp.price FROM subscription_prices sp
+        WHERE sp.subscription_id = s.id AND sp.effective_from <= m.month
+        ORDER BY sp.effective_from DESC
+        LIMIT 1
+    ) AS p
+    WHERE s.tenant_id = current_tenant_id()
+        AND (p_subscription_id IS NULL OR s.id = p_subscription_id)
+        AND NOT EXISTS (
+            SELECT 1 FROM subscription_pauses pp
+            WHERE pp.subscription_id = s.id AND pp.paused_from <= m.month
+                AND (pp.resumed_from IS NULL OR pp.resumed_from > m.month)
+        );
+$$ LANGUAGE sql STABLE;
+
+-- rebuild_subscription_rollup moves the horizon of the current tenant and
+-- recomputes its rows, returning how many rows were written.
+CREATE OR REPLACE FUNCTION rebuild_subscription_rollup(p_horizon DATE) RETURNS BIGINT AS $$
+DECLARE
+    v_rows BIGINT;
+BEGIN
+    INSERT INTO subscription_rollup_state (tenant_id, horizon)
+    VALUES (current_tenant_id(), date_trunc('month', p_horizon)::date)
+    ON CONFLICT (tenant_id) DO UPDATE SET horizon = EXCLUDED.horizon;
+    DELETE FROM subscription_monthly_rollup WHERE tenant_id = current_tenant_id();
+    INSERT INTO subscription_monthly_rollup
+    SELECT * FROM subscription_rollup_rows(NULL);
+    GET DIAGNOSTICS v_rows = ROW_COUNT;
+    RETURN v_rows;
+END;
+$$ LANGUAGE plpgsql;
+
+-- seed_tenant_categories gives the current tenant the categories every
+-- tenant starts with, the ones of 20251025120000_categories_tags.sql.
+CREATE OR REPLACE FUNCTION seed_tenant_categories() RETURNS VOID AS $$
+    INSERT INTO categories (code, name) VALUES
+        ('streaming', 'Стриминг'),
+        ('music', 'Музыка'),
+        ('gaming', 'Игры'),
+        ('cloud', 'Облачные хранилища'),
+        ('productivity', 'Продуктивность'),
+        ('education', 'Образование'),
+        ('news', 'Новости и медиа'),
+        ('fitness', 'Здоровье и спорт'),
+        ('other', 'Другое')
+    ON CONFLICT DO NOTHING;
+$$ LANGUAGE sql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- only possible while the default tenant is the only one
+DROP FUNCTION IF EXISTS seed_tenant_categories();
+
+ALTER TABLE subscription_rollup_state DROP CONSTRAINT subscription_rollup_state_pkey;
+ALTER TABLE subscription_rollup_state ADD COLUMN id BOOLEAN NOT NULL DEFAULT TRUE CHECK (id);
+ALTER TABLE subscription_rollup_state ADD PRIMARY KEY (id);
+
+ALTER TABLE subscriptions DROP CONSTRAINT subscriptions_category_fkey;
+ALTER TABLE categories DROP CONSTRAINT categories_pkey;
+ALTER TABLE categories ADD PRIMARY KEY (code);
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_category_fkey
+    FOREIGN KEY (category) REFERENCES categories(code);
+
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (scope, key);
+
+ALTER TABLE exchange_rates DROP CONSTRAINT exchange_rates_currency_valid_from_key;
+ALTER TABLE exchange_rates ADD CONSTRAINT exchange_rates_currency_valid_from_key UNIQUE (currency, valid_from);
+
+ALTER TABLE service_aliases DROP CONSTRAINT service_aliases_normalized_alias_key;
+ALTER TABLE service_aliases ADD CONSTRAINT service_aliases_normalized_alias_key UNIQUE (normalized_alias);
+
+ALTER TABLE services DROP CONSTRAINT services_normalized_name_key;
+ALTER TABLE services ADD CONSTRAINT services_normalized_name_key UNIQUE (normalized_name);
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'subscriptions', 'subscription_prices', 'subscription_tags', 'subscription_pauses',
+        'subscription_audit', 'subscription_monthly_rollup', 'subscription_rollup_state',
+        'exchange_rates', 'budgets', 'services', 'service_aliases', 'categories', 'idempotency_keys'
+    ] LOOP
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', t);
+        EXECUTE format('ALTER TABLE %I NO FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I DISABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I DROP COLUMN IF EXISTS tenant_id', t);
+    END LOOP;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION subscription_rollup_rows(p_subscription_id INT)
+RETURNS TABLE (
+    subscription_id INT,
+    user_id UUID,
+    service_name VARCHAR,
+    currency CHAR(3),
+    month DATE,
+    charged NUMERIC,
+    amortized NUMERIC
+) AS $$
+    SELECT s.id, s.user_id, s.service_name, s.currency, m.month::date,
+        p.price * billing_factor(s.billing_period, s.billing_months, s.start_date, s.end_date, m.month::date, FALSE),
+        p.price * billing_factor(s.billing_period, s.billing_months, s.start_date, s.end_date, m.month::date, TRUE)
+    FROM subscriptions s
+    CROSS JOIN subscription_rollup_state r
+    CROSS JOIN LATERAL generate_series(
+        date_trunc('month', s.start_date),
+        date_trunc('month', LEAST(COALESCE(s.end_date, r.horizon), r.horizon)),
+        interval '1 month'
+    ) AS m(month)
+    CROSS JOIN LATERAL (
+        SELECT sp.price FROM subscription_prices sp
+        WHERE sp.subscription_id = s.id AND sp.effective_from <= m.month
+        ORDER BY sp.effective_from DESC
+        LIMIT 1
+    ) AS p
+    WHERE (p_subscription_id IS NULL OR s.id = p_subscription_id)
+        AND NOT EXISTS (
+            SELECT 1 FROM subscription_pauses pp
+            WHERE pp.subscription_id = s.id AND pp.paused_from <= m.month
+                AND (pp.resumed_from IS NULL OR pp.resumed_from > m.month)
+        );
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION rebuild_subscription_rollup(p_horizon DATE) RETURNS BIGINT AS $$
+DECLARE
+    v_rows BIGINT;
+BEGIN
+    UPDATE subscription_rollup_state SET horizon = date_trunc('month', p_horizon)::date;
+    DELETE FROM subscription_monthly_rollup;
+    INSERT INTO subscription_monthly_rollup
+    SELECT * FROM subscription_rollup_rows(NULL);
+    GET DIAGNOSTICS v_rows = ROW_COUNT;
+    RETURN v_rows;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP FUNCTION IF EXISTS current_tenant_id();
+DROP TABLE IF EXISTS tenant_api_keys;
+DROP TABLE IF EXISTS tenants;
+-- +goose StatementEnd
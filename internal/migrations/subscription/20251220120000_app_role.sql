@@ -0,0 +1,77 @@
+-- +goose Up
+-- +goose StatementBegin
+-- aggregation_app is the role the service works as. It is neither a superuser
+-- nor BYPASSRLS, so the tenant_isolation policies apply to it whatever role
+-- the service logs in with: every tenant transaction switches to it, see
+-- go_postgres.SetTenant. The role that runs the migrations becomes a member of
+-- it to be allowed to switch.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'aggregation_app') THEN
+        CREATE ROLE aggregation_app NOLOGIN NOSUPERUSER NOBYPASSRLS NOCREATEDB NOCREATEROLE;
+    END IF;
+END;
+$$;
+
+GRANT aggregation_app TO CURRENT_USER;
+GRANT USAGE ON SCHEMA public TO aggregation_app;
+GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO aggregation_app;
+GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO aggregation_app;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO aggregation_app;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT USAGE, SELECT ON SEQUENCES TO aggregation_app;
+
+-- same as in 20250915120000_currency.sql, with the rates of the current tenant
+CREATE OR REPLACE FUNCTION exchange_rate(p_currency CHAR(3), p_month DATE) RETURNS NUMERIC AS $$
+DECLARE
+    v_rate NUMERIC;
+BEGIN
+    IF p_currency = 'RUB' THEN
+        RETURN 1;
+    END IF;
+
+    SELECT rate INTO v_rate
+    FROM exchange_rates
+    WHERE tenant_id = current_tenant_id() AND currency = p_currency AND valid_from <= p_month
+    ORDER BY valid_from DESC
+    LIMIT 1;
+
+    IF v_rate IS NULL THEN
+        RAISE EXCEPTION 'no exchange rate for % at %', p_currency, p_month USING ERRCODE = 'no_data_found';
+    END IF;
+
+    RETURN v_rate;
+END;
+$$ LANGUAGE plpgsql STABLE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION exchange_rate(p_currency CHAR(3), p_month DATE) RETURNS NUMERIC AS $$
+DECLARE
+    v_rate NUMERIC;
+BEGIN
+    IF p_currency = 'RUB' THEN
+        RETURN 1;
+    END IF;
+
+    SELECT rate INTO v_rate
+    FROM exchange_rates
+    WHERE currency = p_currency AND valid_from <= p_month
+    ORDER BY valid_from DESC
+    LIMIT 1;
+
+    IF v_rate IS NULL THEN
+        RAISE EXCEPTION 'no exchange rate for % at %', p_currency, p_month USING ERRCODE = 'no_data_found';
+    END IF;
+
+    RETURN v_rate;
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+ALTER DEFAULT PRIVILEGES IN SCHEMA public REVOKE USAGE, SELECT ON SEQUENCES FROM aggregation_app;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public REVOKE SELECT, INSERT, UPDATE, DELETE ON TABLES FROM aggregation_app;
+REVOKE ALL ON ALL SEQUENCES IN SCHEMA public FROM aggregation_app;
+REVOKE ALL ON ALL TABLES IN SCHEMA public FROM aggregation_app;
+REVOKE USAGE ON SCHEMA public FROM aggregation_app;
+-- the role is shared by the databases of the cluster and is left in place
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the tenants created before their rollup horizon was set on creation have no
+-- subscription_rollup_state row, so their rollup was never filled and the
+-- cost endpoints read their subscriptions live. Roll them up like
+-- 20251010120000_monthly_rollup.sql did for the existing data.
+DO $$
+DECLARE
+    t UUID;
+BEGIN
+    FOR t IN
+        SELECT id FROM tenants
+        WHERE NOT EXISTS (SELECT 1 FROM subscription_rollup_state r WHERE r.tenant_id = tenants.id)
+    LOOP
+        PERFORM set_config('app.tenant_id', t::text, true);
+        PERFORM rebuild_subscription_rollup(date_trunc('month', NOW() + interval '5 years')::date);
+    END LOOP;
+    PERFORM set_config('app.tenant_id', '', true);
+END;
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- the rows written are the same ones cmd/rollup writes, they are kept
+SELECT 1;
+-- +goose StatementEnd
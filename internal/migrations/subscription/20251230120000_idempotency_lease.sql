@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a running request holds its key until locked_until; a request that died
+-- before completing it no longer blocks its key until expires_at, the next
+-- request with the key takes it over once the lease has passed. NULL once the
+-- response is stored.
+ALTER TABLE idempotency_keys ADD COLUMN locked_until TIMESTAMP;
+
+-- the keys still running now are as old as their requests, their leases
+-- have passed
+UPDATE idempotency_keys SET locked_until = created_at WHERE status_code IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS locked_until;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a budget can be limited to the subscriptions of one category, alone or
+-- together with service_name
+ALTER TABLE budgets ADD COLUMN category VARCHAR(50);
+ALTER TABLE budgets ADD CONSTRAINT budgets_category_fkey
+    FOREIGN KEY (tenant_id, category) REFERENCES categories(tenant_id, code);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE budgets DROP CONSTRAINT IF EXISTS budgets_category_fkey;
+ALTER TABLE budgets DROP COLUMN IF EXISTS category;
+-- +goose StatementEnd
@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a budget for one service points at its catalog service and counts exactly
+-- its subscriptions; service_name is kept as a copy of services.name, like on
+-- subscriptions. Until now the name was matched as a substring of the names
+-- of the subscriptions, so a budget for 'Yandex' also counted 'Yandex Plus'.
+ALTER TABLE budgets ADD COLUMN service_id INT REFERENCES services(id);
+
+CREATE INDEX idx_budgets_service_id ON budgets(service_id);
+
+-- the names of existing budgets are resolved like the name of a new
+-- subscription: by service name or alias, an unknown name becomes a service
+DO $$
+DECLARE
+    t UUID;
+BEGIN
+    FOR t IN SELECT id FROM tenants LOOP
+        PERFORM set_config('app.tenant_id', t::text, true);
+
+        INSERT INTO services (name, normalized_name)
+        SELECT DISTINCT ON (normalize_service_name(b.service_name))
+            regexp_replace(btrim(b.service_name), '\s+', ' ', 'g'),
+            normalize_service_name(b.service_name)
+        FROM budgets b
+        WHERE b.tenant_id = t
+            AND b.service_name IS NOT NULL
+            AND NOT EXISTS (
+                SELECT 1 FROM service_aliases a
+                WHERE a.tenant_id = t AND a.normalized_alias = normalize_service_name(b.service_name)
+            )
+        ORDER BY normalize_service_name(b.service_name), b.service_name
+        ON CONFLICT (tenant_id, normalized_name) DO NOTHING;
+
+        UPDATE budgets b
+        SET service_id = sv.id, service_name = sv.name
+        FROM services sv
+        WHERE b.tenant_id = t
+            AND sv.tenant_id = t
+            AND b.service_name IS NOT NULL
+            AND (
+                sv.normalized_name = normalize_service_name(b.service_name)
+                OR EXISTS (
+                    SELECT 1 FROM service_aliases a
+                    WHERE a.service_id = sv.id AND a.normalized_alias = normalize_service_name(b.service_name)
+                )
+            );
+    END LOOP;
+    PERFORM set_config('app.tenant_id', '', true);
+END;
+$$;
+
+ALTER TABLE budgets ADD CONSTRAINT budgets_service_check CHECK ((service_id IS NULL) = (service_name IS NULL));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- the services created for the names of budgets are kept
+ALTER TABLE budgets DROP CONSTRAINT IF EXISTS budgets_service_check;
+DROP INDEX IF EXISTS idx_budgets_service_id;
+ALTER TABLE budgets DROP COLUMN IF EXISTS service_id;
+-- +goose StatementEnd
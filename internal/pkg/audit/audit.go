@@ -0,0 +1,26 @@
+package audit
+
+import "context"
+
+// AnonymousActor is the actor of requests without an API key.
+const AnonymousActor = "anonymous"
+
+// Meta describes who made a change and in which request.
+type Meta struct {
+	RequestID string
+	Actor     string
+}
+
+type ctxMeta struct{}
+
+func ContextWithMeta(ctx context.Context, meta Meta) context.Context {
+	return context.WithValue(ctx, ctxMeta{}, meta)
+}
+
+func FromContext(ctx context.Context) Meta {
+	if meta, ok := ctx.Value(ctxMeta{}).(Meta); ok {
+		return meta
+	}
+
+	return Meta{Actor: AnonymousActor}
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"AggregationService/internal/pkg/audit"
+	"github.com/go-chi/chi/v5/middleware"
+	"net/http"
+)
+
+// AuditMW stores the request ID of the request for the audit trail. The actor
+// stays anonymous until the API key of the request is checked, see
+// handlers.TenantHandler.Authenticate. It must run after chi's RequestID
+// middleware.
+func AuditMW(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := audit.ContextWithMeta(r.Context(), audit.Meta{
+			RequestID: middleware.GetReqID(r.Context()),
+			Actor:     audit.AnonymousActor,
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
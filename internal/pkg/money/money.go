@@ -0,0 +1,95 @@
+package money
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Amount is a sum of money in minor units (kopecks, cents), assuming two
+// fraction digits for every currency. It is encoded in JSON as a decimal
+// number and decodes whole numbers as major units, so clients still sending
+// integer roubles keep working.
+type Amount int64
+
+const (
+	fractionDigits = 2
+	minorPerMajor  = 100
+)
+
+var ErrInvalidAmount = errors.New("invalid money amount")
+
+// FromMajor returns the amount of whole major units (roubles, dollars).
+func FromMajor(major int64) Amount {
+	return Amount(major * minorPerMajor)
+}
+
+// Parse reads a decimal amount such as "199", "199.9" or "-0.50". More than
+// two fraction digits and exponents are rejected rather than rounded.
+func Parse(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" || len(fraction) > fractionDigits || !isDigits(whole) || !isDigits(fraction) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	fraction += strings.Repeat("0", fractionDigits-len(fraction))
+
+	major, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || major > math.MaxInt64/minorPerMajor-1 {
+		return 0, fmt.Errorf("%w: %q out of range", ErrInvalidAmount, s)
+	}
+	minor, _ := strconv.ParseInt(fraction, 10, 64)
+
+	amount := Amount(major*minorPerMajor + minor)
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+func (a Amount) String() string {
+	sign := ""
+	minor := int64(a)
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, minor/minorPerMajor, minor%minorPerMajor)
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON accepts both JSON numbers and quoted decimal strings.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	s := string(data)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	amount, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
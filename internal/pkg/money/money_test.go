@@ -0,0 +1,64 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		want    Amount
+		wantErr bool
+	}{
+		{name: "Whole roubles", input: "400", want: 40000},
+		{name: "One fraction digit", input: "199.9", want: 19990},
+		{name: "Two fraction digits", input: "9.99", want: 999},
+		{name: "Negative", input: "-0.50", want: -50},
+		{name: "Too precise", input: "1.999", wantErr: true},
+		{name: "Exponent", input: "1e3", wantErr: true},
+		{name: "Empty", input: "", wantErr: true},
+		{name: "Only fraction", input: ".5", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrInvalidAmount), "expected ErrInvalidAmount, got: %v", err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAmount_JSON(t *testing.T) {
+	t.Parallel()
+
+	var body struct {
+		Price Amount `json:"price"`
+	}
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"price": 400}`), &body))
+	assert.Equal(t, FromMajor(400), body.Price)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"price": 199.90}`), &body))
+	assert.Equal(t, Amount(19990), body.Price)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"price": "9.99"}`), &body))
+	assert.Equal(t, Amount(999), body.Price)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"price": 0.001}`), &body))
+
+	out, err := json.Marshal(body)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"price": 9.99}`, string(out))
+}
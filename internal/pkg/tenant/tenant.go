@@ -0,0 +1,22 @@
+package tenant
+
+import (
+	"context"
+	"github.com/google/uuid"
+)
+
+// DefaultID is the tenant that owns the data stored before tenants existed.
+var DefaultID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+type ctxID struct{}
+
+func ContextWithID(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, ctxID{}, id)
+}
+
+// FromContext returns the tenant the request is made for, false when it was
+// not authenticated.
+func FromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(ctxID{}).(uuid.UUID)
+	return id, ok && id != uuid.Nil
+}
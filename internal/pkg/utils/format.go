@@ -10,3 +10,28 @@ func ParseMonthYearToTime(s string) (time.Time, error) {
 func TimeToMonthYear(t time.Time) string {
 	return t.Format("01-2006")
 }
+
+// MonthStart returns the first day of the calendar month of t.
+func MonthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// MonthRange returns the first day of every calendar month between start and
+// end inclusive.
+func MonthRange(start, end time.Time) []time.Time {
+	current := MonthStart(start)
+	last := MonthStart(end)
+
+	var months []time.Time
+	for !current.After(last) {
+		months = append(months, current)
+		current = current.AddDate(0, 1, 0)
+	}
+	return months
+}
+
+// TimeToDayMonthYear formats a day of a charge as DD-MM-YYYY, the daily
+// counterpart of TimeToMonthYear.
+func TimeToDayMonthYear(t time.Time) string {
+	return t.Format("02-01-2006")
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"strings"
+)
+
+// cyrillicToLatin transliterates lower-case Cyrillic letters. It mirrors the
+// normalize_service_name SQL function, change both together.
+var cyrillicToLatin = strings.NewReplacer(
+	"а", "a", "б", "b", "в", "v", "г", "g", "д", "d", "е", "e", "ё", "e",
+	"ж", "zh", "з", "z", "и", "i", "й", "y", "к", "k", "л", "l", "м", "m",
+	"н", "n", "о", "o", "п", "p", "р", "r", "с", "s", "т", "t", "у", "u",
+	"ф", "f", "х", "kh", "ц", "ts", "ч", "ch", "ш", "sh", "щ", "shch",
+	"ъ", "", "ы", "y", "ь", "", "э", "e", "ю", "yu", "я", "ya",
+)
+
+// TidyServiceName trims a service name and collapses its inner whitespace.
+func TidyServiceName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// NormalizeServiceName returns the key service names and aliases are matched
+// by: the tidy name case-folded and transliterated to Latin, so "Кинопоиск"
+// and " kinopoisk" share a key.
+func NormalizeServiceName(name string) string {
+	return cyrillicToLatin.Replace(strings.ToLower(TidyServiceName(name)))
+}
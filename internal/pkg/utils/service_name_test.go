@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeServiceName(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "Case and spaces", in: "  Yandex   Plus ", want: "yandex plus"},
+		{name: "Cyrillic", in: "Кинопоиск", want: "kinopoisk"},
+		{name: "Multi-letter transliteration", in: "Яндекс Плюс", want: "yandeks plyus"},
+		{name: "Soft and hard signs", in: "Объём", want: "obem"},
+		{name: "Mixed scripts", in: "KinoPoisk HD", want: "kinopoisk hd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeServiceName(tt.in))
+		})
+	}
+}
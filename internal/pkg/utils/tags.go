@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+)
+
+// NormalizeTag trims a tag, collapses its inner whitespace and lower-cases it.
+func NormalizeTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ToLower(tag)), " ")
+}
+
+// NormalizeTags returns tags normalized by NormalizeTag, without blanks and
+// duplicates and in sorted order.
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]struct{}, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" {
+			continue
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		result = append(result, tag)
+	}
+	sort.Strings(result)
+	return result
+}
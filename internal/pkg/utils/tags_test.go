@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want []string
+	}{
+		{name: "Nil", in: nil, want: []string{}},
+		{name: "Case and spaces", in: []string{" Family  Plan ", "work"}, want: []string{"family plan", "work"}},
+		{name: "Duplicates and blanks", in: []string{"Work", "work ", "  ", "fun"}, want: []string{"fun", "work"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeTags(tt.in))
+		})
+	}
+}